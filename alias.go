@@ -0,0 +1,97 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RegisterAlias registers name as an alias of the rules of tags, so
+// that a set of rules used on many fields can be referenced by name,
+// e.g. validate:"username" after
+//
+//	validator.RegisterAlias("username", "min=3,max=20,regexp=^[a-z0-9_]+$")
+//
+// Aliases are expanded when tags are parsed and take precedence over
+// validation functions of the same name, which they may use themselves,
+// e.g. RegisterAlias("password", "password=min:12;symbol"). A message
+// given to an alias applies to those of its rules without their own,
+// and @warn to all of them.
+// Calling this function with empty tags removes the alias.
+func RegisterAlias(name, tags string) error {
+	return defaultValidator.RegisterAlias(name, tags)
+}
+
+// RegisterAlias registers name as an alias of the rules of tags, so
+// that a set of rules used on many fields can be referenced by name.
+// Aliases are expanded when tags are parsed and take precedence over
+// validation functions of the same name, which they may use themselves.
+// A message given to an alias applies to those of its rules without
+// their own, and @warn to all of them. Calling this function with empty
+// tags removes the alias.
+func (mv *Validator) RegisterAlias(name, tags string) error {
+	if name == "" {
+		return errors.New("name cannot be empty")
+	}
+	mv.lock.Lock()
+	defer mv.lock.Unlock()
+	mv.tagsCache.reset()
+	mv.structCache.reset()
+	if tags == "" {
+		delete(mv.aliases, name)
+		return nil
+	}
+	if _, err := mv.expandTags(tags, []string{name}); err != nil {
+		return fmt.Errorf("invalid alias %s: %v", name, err)
+	}
+	if mv.aliases == nil {
+		mv.aliases = map[string]string{}
+	}
+	mv.aliases[name] = tags
+	return nil
+}
+
+// expandAlias returns the rules of the alias tg, whose message applies
+// to the rules without their own.
+func (mv *Validator) expandAlias(tg tag, tags string, expanding []string) ([]tag, error) {
+	if tg.Param != "" {
+		return nil, ErrBadParameter
+	}
+	rules, err := mv.expandTags(tags, append(expanding, tg.Name))
+	if err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		if rules[i].Msg == "" {
+			rules[i].Msg = tg.Msg
+		}
+		rules[i].Warn = rules[i].Warn || tg.Warn
+	}
+	return rules, nil
+}
+
+// isExpanding reports whether the alias name is being expanded, in
+// which case name refers to the validation function it shadows.
+func isExpanding(name string, expanding []string) bool {
+	for _, a := range expanding {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
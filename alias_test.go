@@ -0,0 +1,65 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestRegisterAlias(c *C) {
+	v := validator.New()
+	c.Assert(v.RegisterAlias("username", "nonzero,max=8,regexp=^[a-z]+$"), IsNil)
+	type user struct {
+		Name  string `validate:"username"`
+		Alias string `validate:"username ~ invalid alias,min=3"`
+	}
+	c.Assert(v.Validate(user{Name: "joe", Alias: "jo"}), NotNil)
+	c.Assert(v.Validate(user{Name: "joe", Alias: "bob"}), IsNil)
+
+	errs, ok := v.Validate(user{Name: "Joe", Alias: "JO"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"], HasError, validator.ErrRegexpDetailed("^[a-z]+$"))
+	c.Assert(errs["Alias"], HasLen, 2)
+	c.Assert(errs["Alias"][0].Error(), Equals, "invalid alias")
+	c.Assert(errs["Alias"][1].Error(), Equals, validator.ErrMinString(3, 2).Error())
+
+	c.Assert(v.Valid("abcdefghi", "username"), HasError, validator.ErrMaxString(8, 9))
+	c.Assert(v.Valid("joe", "username=1"), Equals, validator.ErrBadParameter)
+
+	// removing the alias makes it an unknown tag
+	c.Assert(v.RegisterAlias("username", ""), IsNil)
+	c.Assert(v.Valid("joe", "username"), Equals, validator.ErrUnknownTag)
+}
+
+func (ms *MySuite) TestRegisterAliasShadowing(c *C) {
+	v := validator.New()
+	c.Assert(v.RegisterAlias("len", "len,nonzero"), IsNil)
+	c.Assert(v.Valid("abc", "len=3"), Equals, validator.ErrBadParameter)
+
+	c.Assert(v.RegisterAlias("short", "max=3"), IsNil)
+	c.Assert(v.Valid("abcd", "short"), HasError, validator.ErrMaxString(3, 4))
+	// max within short refers to the validation function once expanding max
+	c.Assert(v.RegisterAlias("max", "short"), IsNil)
+	c.Assert(v.Valid("abcd", "max"), HasError, validator.ErrMaxString(3, 4))
+	c.Assert(v.RegisterAlias("a", "b"), NotNil)
+	c.Assert(v.RegisterAlias("", "max=3"), NotNil)
+
+	c.Assert(v.SetValidationFunc("short", func(interface{}, string) error { return nil }), IsNil)
+	c.Assert(v.Valid("abcd", "short"), IsNil)
+}
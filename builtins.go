@@ -20,38 +20,62 @@ import (
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 	"unicode/utf8"
 )
 
 // nonzero tests whether a variable value non-zero
 // as defined by the golang spec.
 func nonzero(v interface{}, param string) error {
+	// the most common types are checked without reflection
+	switch x := v.(type) {
+	case string:
+		return nonzeroString(x)
+	case int:
+		return nonzeroNumber(x == 0)
+	case int64:
+		return nonzeroNumber(x == 0)
+	case float64:
+		return nonzeroNumber(x == 0)
+	case bool:
+		if !x {
+			return ErrZeroValueBool
+		}
+		return nil
+	case []string:
+		return nonzeroLen(len(x))
+	case map[string]string:
+		return nonzeroLen(len(x))
+	case map[string]interface{}:
+		return nonzeroLen(len(x))
+	case time.Time:
+		if x.IsZero() {
+			return ErrZeroValueEmpty
+		}
+		return nil
+	}
+	if r, _, ok := asRat(v); ok {
+		return nonzeroNumber(r.Sign() == 0)
+	}
 	st := reflect.ValueOf(v)
 	switch st.Kind() {
 	case reflect.String:
-		if utf8.RuneCount([]byte(st.String())) == 0 {
-			return ErrZeroValueEmpty
-		}
+		return nonzeroString(st.String())
 	case reflect.Ptr, reflect.Interface:
 		if st.IsNil() {
 			return ErrZeroValueEmpty
 		}
 	case reflect.Slice, reflect.Map, reflect.Array:
-		if st.Len() == 0 {
-			return ErrZeroValueEmpty
-		}
+		return nonzeroLen(st.Len())
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if st.Int() == 0 {
-			return ErrZeroValueNumber
-		}
+		return nonzeroNumber(st.Int() == 0)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		if st.Uint() == 0 {
-			return ErrZeroValueNumber
-		}
+		return nonzeroNumber(st.Uint() == 0)
 	case reflect.Float32, reflect.Float64:
-		if st.Float() == 0 {
-			return ErrZeroValueNumber
-		}
+		return nonzeroNumber(st.Float() == 0)
 	case reflect.Bool:
 		if !st.Bool() {
 			return ErrZeroValueBool
@@ -67,39 +91,73 @@ func nonzero(v interface{}, param string) error {
 	return nil
 }
 
+// required tests whether a value is present: nil pointers, interfaces,
+// slices, maps, functions and channels are not, while any other value,
+// zero or not, is. Values implementing driver.Valuer are not present when
+// null, e.g. an invalid sql.NullInt64.
+func required(v interface{}, param string) error {
+	st := reflect.ValueOf(v)
+	switch st.Kind() {
+	case reflect.Invalid:
+		return ErrRequired
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+		if st.IsNil() {
+			return ErrRequired
+		}
+	}
+	return nil
+}
+
+func nonzeroString(s string) error {
+	if s == "" {
+		return ErrZeroValueEmpty
+	}
+	return nil
+}
+
+func nonzeroLen(n int) error {
+	if n == 0 {
+		return ErrZeroValueEmpty
+	}
+	return nil
+}
+
+func nonzeroNumber(zero bool) error {
+	if zero {
+		return ErrZeroValueNumber
+	}
+	return nil
+}
+
 // length tests whether a variable's length is equal to a given
 // value. For strings it tests the number of characters whereas
 // for maps and slices it tests the number of items.
 func length(v interface{}, param string) error {
+	// the most common types are checked without reflection
+	switch x := v.(type) {
+	case string:
+		return lengthString(x, param)
+	case int:
+		return lengthInt(int64(x), param)
+	case int64:
+		return lengthInt(x, param)
+	case float64:
+		return lengthFloat(x, param)
+	case []string:
+		return lengthArray(len(x), param)
+	case map[string]string:
+		return lengthArray(len(x), param)
+	case map[string]interface{}:
+		return lengthArray(len(x), param)
+	}
 	st := reflect.ValueOf(v)
 	switch st.Kind() {
 	case reflect.String:
-		p, err := asInt(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		actual := utf8.RuneCount([]byte(st.String()))
-		if int64(actual) != p {
-			return ErrLenString(p, actual)
-		}
+		return lengthString(st.String(), param)
 	case reflect.Slice, reflect.Map, reflect.Array:
-		p, err := asInt(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		actual := st.Len()
-		if int64(actual) != p {
-			return ErrLenArray(p, actual)
-		}
+		return lengthArray(st.Len(), param)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		p, err := asInt(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		actual := st.Int()
-		if actual != p {
-			return ErrLenInt(p, actual)
-		}
+		return lengthInt(st.Int(), param)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		p, err := asUint(param)
 		if err != nil {
@@ -107,18 +165,11 @@ func length(v interface{}, param string) error {
 		}
 		actual := st.Uint()
 		if actual != p {
-			return ErrLenInt(int64(p), int64(actual))
+			return ErrLenUint(p, actual)
 		}
 	case reflect.Float32, reflect.Float64:
-		p, err := asFloat(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		actual := st.Float()
-		if actual != p {
-			return ErrLenFloat(p, actual)
-		}
-	case reflect.Ptr:
+		return lengthFloat(st.Float(), param)
+	case reflect.Ptr, reflect.Invalid:
 		return nil
 	default:
 		return ErrUnsupported
@@ -127,40 +178,94 @@ func length(v interface{}, param string) error {
 	return nil
 }
 
+func lengthString(s, param string) error {
+	p, err := asInt(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	actual := utf8.RuneCountInString(s)
+	if int64(actual) != p {
+		return ErrLenString(p, actual)
+	}
+	return nil
+}
+
+func lengthArray(actual int, param string) error {
+	p, err := asInt(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if int64(actual) != p {
+		return ErrLenArray(p, actual)
+	}
+	return nil
+}
+
+func lengthInt(actual int64, param string) error {
+	p, err := asInt(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if actual != p {
+		return ErrLenInt(p, actual)
+	}
+	return nil
+}
+
+func lengthFloat(actual float64, param string) error {
+	p, err := asFloat(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if actual != p {
+		return ErrLenFloat(p, actual)
+	}
+	return nil
+}
+
 // min tests whether a variable value is larger or equal to a given
 // number. For number types, it's a simple lesser-than test; for
 // strings it tests the number of characters whereas for maps
-// and slices it tests the number of items.
+// and slices it tests the number of items. For durations, the
+// parameter may be given as a duration string, e.g. 100ms.
 func min(v interface{}, param string) error {
-	st := reflect.ValueOf(v)
-	switch st.Kind() {
-	case reflect.String:
-		p, err := asInt(param)
+	// the most common types are checked without reflection
+	switch x := v.(type) {
+	case string:
+		return minString(x, param)
+	case int:
+		return minInt(int64(x), param)
+	case int64:
+		return minInt(x, param)
+	case float64:
+		return minFloat(x, param)
+	case []string:
+		return minArray(len(x), param)
+	case map[string]string:
+		return minArray(len(x), param)
+	case map[string]interface{}:
+		return minArray(len(x), param)
+	case time.Duration:
+		p, err := asDuration(param)
 		if err != nil {
 			return ErrBadParameter
 		}
-		actual := utf8.RuneCount([]byte(st.String()))
-		if int64(actual) < p {
-			return ErrMinString(p, actual)
+		if x < p {
+			return ErrMinDuration(p, x)
 		}
+		return nil
+	}
+	if r, text, ok := asRat(v); ok {
+		return minBig(r, text, param)
+	}
+	st := reflect.ValueOf(v)
+	switch st.Kind() {
+	case reflect.String:
+		return minString(st.String(), param)
 	case reflect.Slice, reflect.Map, reflect.Array:
-		p, err := asInt(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		actual := st.Len()
-		if int64(actual) < p {
-			return ErrMinArray(p, actual)
-		}
+		return minArray(st.Len(), param)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		p, err := asInt(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		actual := st.Int()
-		if actual < p {
-			return ErrMinInt(int64(p), int64(actual))
-		}
+		return minInt(st.Int(), param)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		p, err := asUint(param)
 		if err != nil {
@@ -168,18 +273,11 @@ func min(v interface{}, param string) error {
 		}
 		actual := st.Uint()
 		if actual < p {
-			return ErrMinInt(int64(p), int64(actual))
+			return ErrMinUint(p, actual)
 		}
 	case reflect.Float32, reflect.Float64:
-		p, err := asFloat(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		actual := st.Float()
-		if actual < p {
-			return ErrMinFloat(p, actual)
-		}
-	case reflect.Ptr:
+		return minFloat(st.Float(), param)
+	case reflect.Ptr, reflect.Invalid:
 		return nil
 	default:
 		return ErrUnsupported
@@ -188,40 +286,94 @@ func min(v interface{}, param string) error {
 	return nil
 }
 
+func minString(s, param string) error {
+	p, err := asInt(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	actual := utf8.RuneCountInString(s)
+	if int64(actual) < p {
+		return ErrMinString(p, actual)
+	}
+	return nil
+}
+
+func minArray(actual int, param string) error {
+	p, err := asInt(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if int64(actual) < p {
+		return ErrMinArray(p, actual)
+	}
+	return nil
+}
+
+func minInt(actual int64, param string) error {
+	p, err := asInt(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if actual < p {
+		return ErrMinInt(p, actual)
+	}
+	return nil
+}
+
+func minFloat(actual float64, param string) error {
+	p, err := asFloat(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if actual < p {
+		return ErrMinFloat(p, actual)
+	}
+	return nil
+}
+
 // max tests whether a variable value is lesser than a given
 // value. For numbers, it's a simple lesser-than test; for
 // strings it tests the number of characters whereas for maps
-// and slices it tests the number of items.
+// and slices it tests the number of items. For durations, the
+// parameter may be given as a duration string, e.g. 30s.
 func max(v interface{}, param string) error {
-	st := reflect.ValueOf(v)
-	switch st.Kind() {
-	case reflect.String:
-		p, err := asInt(param)
+	// the most common types are checked without reflection
+	switch x := v.(type) {
+	case string:
+		return maxString(x, param)
+	case int:
+		return maxInt(int64(x), param)
+	case int64:
+		return maxInt(x, param)
+	case float64:
+		return maxFloat(x, param)
+	case []string:
+		return maxArray(len(x), param)
+	case map[string]string:
+		return maxArray(len(x), param)
+	case map[string]interface{}:
+		return maxArray(len(x), param)
+	case time.Duration:
+		p, err := asDuration(param)
 		if err != nil {
 			return ErrBadParameter
 		}
-		actual := utf8.RuneCount([]byte(st.String()))
-		if int64(actual) > p {
-			return ErrMaxString(p, actual)
+		if x > p {
+			return ErrMaxDuration(p, x)
 		}
+		return nil
+	}
+	if r, text, ok := asRat(v); ok {
+		return maxBig(r, text, param)
+	}
+	st := reflect.ValueOf(v)
+	switch st.Kind() {
+	case reflect.String:
+		return maxString(st.String(), param)
 	case reflect.Slice, reflect.Map, reflect.Array:
-		p, err := asInt(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		actual := st.Len()
-		if int64(actual) > p {
-			return ErrMaxArray(p, actual)
-		}
+		return maxArray(st.Len(), param)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		p, err := asInt(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		actual := st.Int()
-		if actual > p {
-			return ErrMaxInt(p, actual)
-		}
+		return maxInt(st.Int(), param)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		p, err := asUint(param)
 		if err != nil {
@@ -229,18 +381,11 @@ func max(v interface{}, param string) error {
 		}
 		actual := st.Uint()
 		if actual > p {
-			return ErrMaxInt(int64(p), int64(actual))
+			return ErrMaxUint(p, actual)
 		}
 	case reflect.Float32, reflect.Float64:
-		p, err := asFloat(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		actual := st.Float()
-		if actual > p {
-			return ErrMaxFloat(p, actual)
-		}
-	case reflect.Ptr:
+		return maxFloat(st.Float(), param)
+	case reflect.Ptr, reflect.Invalid:
 		return nil
 	default:
 		return ErrUnsupported
@@ -248,15 +393,167 @@ func max(v interface{}, param string) error {
 	return nil
 }
 
+func maxString(s, param string) error {
+	p, err := asInt(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	actual := utf8.RuneCountInString(s)
+	if int64(actual) > p {
+		return ErrMaxString(p, actual)
+	}
+	return nil
+}
+
+func maxArray(actual int, param string) error {
+	p, err := asInt(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if int64(actual) > p {
+		return ErrMaxArray(p, actual)
+	}
+	return nil
+}
+
+func maxInt(actual int64, param string) error {
+	p, err := asInt(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if actual > p {
+		return ErrMaxInt(p, actual)
+	}
+	return nil
+}
+
+func maxFloat(actual float64, param string) error {
+	p, err := asFloat(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if actual > p {
+		return ErrMaxFloat(p, actual)
+	}
+	return nil
+}
+
+// between tests whether a value is within the two bounds given as
+// parameter, bounds included: numbers by their value and strings, maps
+// and slices by their length, as for min and max, and times as for
+// after and before, e.g. between=1:100.
+func between(v interface{}, param string) error {
+	bounds := SplitParams(param)
+	if len(bounds) != 2 {
+		return ErrBadParameter
+	}
+	if isNil(v) {
+		return nil
+	}
+	if t, ok := v.(time.Time); ok {
+		return betweenTimes(t, bounds)
+	}
+	if err := min(v, bounds[0]); err != nil {
+		return err
+	}
+	return max(v, bounds[1])
+}
+
+// SplitParams splits the parameter of a rule taking several values, as
+// between does, for validation functions to follow the same convention.
+// Values are separated by whitespace or, if there is none, by colons,
+// e.g. 1:100, or 2000-01-01T00:00:00Z now for values holding colons.
+// A backslash takes the character following it literally, so that values
+// may hold the separator, e.g. a\:b:c is split into a:b and c. An empty
+// parameter holds no value.
+func SplitParams(param string) []string {
+	if param == "" {
+		return nil
+	}
+	if strings.IndexByte(param, '\\') < 0 {
+		if strings.IndexFunc(param, unicode.IsSpace) >= 0 {
+			return strings.Fields(param)
+		}
+		return strings.Split(param, ":")
+	}
+	return splitEscaped(param)
+}
+
+// splitEscaped splits param as SplitParams does, taking the characters
+// following backslashes literally.
+func splitEscaped(param string) []string {
+	spaces := false
+	for i := 0; i < len(param); i++ {
+		if param[i] == '\\' {
+			i++
+		} else if unicode.IsSpace(rune(param[i])) {
+			spaces = true
+			break
+		}
+	}
+	var params []string
+	var buf []byte
+	// value reports whether a value is being read, as values are
+	// separated by runs of whitespace
+	value := false
+	for i := 0; i < len(param); i++ {
+		c := param[i]
+		switch {
+		case c == '\\' && i+1 < len(param):
+			i++
+			buf = append(buf, param[i])
+			value = true
+		case spaces && unicode.IsSpace(rune(c)):
+			if value {
+				params = append(params, string(buf))
+				buf, value = buf[:0], false
+			}
+		case !spaces && c == ':':
+			params = append(params, string(buf))
+			buf = buf[:0]
+		default:
+			buf = append(buf, c)
+			value = true
+		}
+	}
+	if value || !spaces {
+		params = append(params, string(buf))
+	}
+	return params
+}
+
+// stringRule returns a validation function applying check to strings,
+// including those of named string types. Empty strings and nil values
+// are left to nonzero.
+func stringRule(check func(s, param string) error) ValidationFunc {
+	return func(v interface{}, param string) error {
+		if isNil(v) {
+			return nil
+		}
+		st := reflect.ValueOf(v)
+		if st.Kind() != reflect.String {
+			return ErrUnsupported
+		}
+		if st.Len() == 0 {
+			return nil
+		}
+		return check(st.String(), param)
+	}
+}
+
 // regex is the builtin validation function that checks
 // whether the string variable matches a regular expression
 func regex(v interface{}, param string) error {
-	s, ok := v.(string)
-	if !ok {
+	if v == nil {
+		return nil
+	}
+	st := reflect.ValueOf(v)
+	if st.Kind() != reflect.String {
 		return ErrUnsupported
 	}
+	s := st.String()
 
-	re, err := regexp.Compile(param)
+	re, err := compileRegexp(param)
 	if err != nil {
 		return ErrBadParameter
 	}
@@ -267,6 +564,58 @@ func regex(v interface{}, param string) error {
 	return nil
 }
 
+// regexpCache holds the regular expressions compiled by the regexp
+// builtin indexed by their pattern, so that each pattern is compiled
+// only once.
+var regexpCache = struct {
+	sync.RWMutex
+	res  map[string]*regexp.Regexp
+	errs map[string]error
+	size int
+}{
+	res:  map[string]*regexp.Regexp{},
+	errs: map[string]error{},
+}
+
+// SetRegexpCacheSize bounds the number of regular expressions kept
+// compiled by the regexp builtin. The cache is emptied whenever it
+// is full. Zero, the default, means no limit.
+func SetRegexpCacheSize(n int) {
+	regexpCache.Lock()
+	defer regexpCache.Unlock()
+	regexpCache.size = n
+	if n > 0 && len(regexpCache.res)+len(regexpCache.errs) > n {
+		regexpCache.res = map[string]*regexp.Regexp{}
+		regexpCache.errs = map[string]error{}
+	}
+}
+
+// compileRegexp returns the compiled regular expression for pattern,
+// from the cache if it was already compiled.
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	regexpCache.RLock()
+	re, ok := regexpCache.res[pattern]
+	err := regexpCache.errs[pattern]
+	regexpCache.RUnlock()
+	if ok || err != nil {
+		return re, err
+	}
+
+	re, err = regexp.Compile(pattern)
+	regexpCache.Lock()
+	defer regexpCache.Unlock()
+	if regexpCache.size > 0 && len(regexpCache.res)+len(regexpCache.errs) >= regexpCache.size {
+		regexpCache.res = map[string]*regexp.Regexp{}
+		regexpCache.errs = map[string]error{}
+	}
+	if err != nil {
+		regexpCache.errs[pattern] = err
+	} else {
+		regexpCache.res[pattern] = re
+	}
+	return re, err
+}
+
 // asInt retuns the parameter as a int64
 // or panics if it can't convert
 func asInt(param string) (int64, error) {
@@ -0,0 +1,106 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ErrPostcode is the error returned when a string is not a postal
+// code of the given country
+var ErrPostcode = func(country string) TextErr {
+	return TextErr{codef(CodePostcode, "Must be a valid %s postal code", country)}
+}
+
+// postcodePatterns holds the patterns of the postal codes of each
+// country, indexed by ISO 3166-1 alpha-2 code.
+var postcodePatterns = map[string]*regexp.Regexp{
+	"AR": regexp.MustCompile(`^([A-HJ-NP-Z]\d{4}[A-Z]{3}|\d{4})$`),
+	"AT": regexp.MustCompile(`^\d{4}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"BE": regexp.MustCompile(`^\d{4}$`),
+	"BR": regexp.MustCompile(`^\d{5}-?\d{3}$`),
+	"CA": regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] ?\d[ABCEGHJ-NPRSTV-Z]\d$`),
+	"CH": regexp.MustCompile(`^\d{4}$`),
+	"CN": regexp.MustCompile(`^\d{6}$`),
+	"CZ": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"DK": regexp.MustCompile(`^\d{4}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"FI": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{2} ?\d{3}$`),
+	"GB": regexp.MustCompile(`^([A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}|GIR ?0AA)$`),
+	"GR": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"HU": regexp.MustCompile(`^\d{4}$`),
+	"IE": regexp.MustCompile(`^([AC-FHKNPRTV-Y]\d{2}|D6W) ?[0-9AC-FHKNPRTV-Y]{4}$`),
+	"IL": regexp.MustCompile(`^\d{7}$`),
+	"IN": regexp.MustCompile(`^[1-9]\d{2} ?\d{3}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"JP": regexp.MustCompile(`^\d{3}-?\d{4}$`),
+	"KR": regexp.MustCompile(`^\d{5}$`),
+	"LU": regexp.MustCompile(`^(L-)?\d{4}$`),
+	"MX": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`),
+	"NO": regexp.MustCompile(`^\d{4}$`),
+	"NZ": regexp.MustCompile(`^\d{4}$`),
+	"PL": regexp.MustCompile(`^\d{2}-\d{3}$`),
+	"PT": regexp.MustCompile(`^\d{4}-\d{3}$`),
+	"RU": regexp.MustCompile(`^\d{6}$`),
+	"SE": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"SG": regexp.MustCompile(`^\d{6}$`),
+	"SK": regexp.MustCompile(`^\d{3} ?\d{2}$`),
+	"TR": regexp.MustCompile(`^\d{5}$`),
+	"UA": regexp.MustCompile(`^\d{5}$`),
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"ZA": regexp.MustCompile(`^\d{4}$`),
+}
+
+// postcode tests whether a string is a postal code of the country
+// given as parameter, e.g. postcode_iso3166_alpha2=US.
+var postcode = stringRule(func(s, param string) error {
+	country := strings.ToUpper(param)
+	re, ok := postcodePatterns[country]
+	if !ok {
+		return ErrBadParameter
+	}
+	if !re.MatchString(strings.ToUpper(s)) {
+		return ErrPostcode(country)
+	}
+	return nil
+})
+
+// postcodeFor tests whether a string is a postal code of the country
+// held by the field of the same struct given as parameter, e.g.
+// postcode_for=Country. Postal codes of countries without a known
+// pattern, or without country, are not checked.
+func postcodeFor(ctx context.Context, v interface{}, param string) error {
+	country, ok := parentField(ctx, param)
+	if !ok {
+		return ErrBadParameter
+	}
+	cv := reflect.ValueOf(country)
+	if cv.Kind() != reflect.String {
+		return nil
+	}
+	if _, ok := postcodePatterns[strings.ToUpper(cv.String())]; !ok {
+		return nil
+	}
+	return postcode(v, cv.String())
+}
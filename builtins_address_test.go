@@ -0,0 +1,64 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestPostcode(c *C) {
+	for _, p := range []string{"94105", "94105-1234"} {
+		c.Assert(validator.Valid(p, "postcode_iso3166_alpha2=US"), IsNil, Commentf(p))
+	}
+	for _, p := range []string{"9410", "94105-12", "ABCDE"} {
+		c.Assert(validator.Valid(p, "postcode_iso3166_alpha2=US"), HasError, validator.ErrPostcode("US"), Commentf(p))
+	}
+	c.Assert(validator.Valid("sw1a 1aa", "postcode_iso3166_alpha2=gb"), IsNil)
+	c.Assert(validator.Valid("K1A 0B1", "postcode_iso3166_alpha2=CA"), IsNil)
+	c.Assert(validator.Valid("1012 AB", "postcode_iso3166_alpha2=NL"), IsNil)
+	c.Assert(validator.Valid("6011", "postcode_iso3166_alpha2=NZ"), IsNil)
+	c.Assert(validator.Valid("6011", "postcode_iso3166_alpha2=ZZ"), HasError, validator.ErrBadParameter)
+}
+
+type address struct {
+	Country  string  `validate:"iso3166_alpha2"`
+	Postcode string  `validate:"postcode_for=Country"`
+	Previous *string `validate:"postcode_for=Country"`
+}
+
+func (ms *MySuite) TestPostcodeFor(c *C) {
+	c.Assert(validator.Validate(address{Country: "US", Postcode: "94105"}), IsNil)
+	c.Assert(validator.Validate(address{Country: "NZ", Postcode: "6011"}), IsNil)
+	c.Assert(validator.Validate(address{Country: "AQ", Postcode: "anything"}), IsNil)
+	c.Assert(validator.Validate(address{Postcode: "anything"}), IsNil)
+
+	prev := "60111"
+	errs, ok := validator.Validate(address{Country: "nz", Postcode: "94105", Previous: &prev}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Postcode"], HasError, validator.ErrPostcode("NZ"))
+	c.Assert(errs["Previous"], HasError, validator.ErrPostcode("NZ"))
+
+	type missing struct {
+		Postcode string `validate:"postcode_for=Country"`
+	}
+	errs, ok = validator.Validate(missing{"94105"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Postcode"], HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid("94105", "postcode_for=Country"), HasError, validator.ErrBadParameter)
+}
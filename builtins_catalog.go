@@ -0,0 +1,117 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+)
+
+var (
+	// ErrISBN10 is the error returned when a string is not a valid
+	// ISBN-10
+	ErrISBN10 = TextErr{coded(CodeISBN10, "Must be a valid ISBN-10")}
+	// ErrISBN13 is the error returned when a string is not a valid
+	// ISBN-13
+	ErrISBN13 = TextErr{coded(CodeISBN13, "Must be a valid ISBN-13")}
+	// ErrISSN is the error returned when a string is not a valid ISSN
+	ErrISSN = TextErr{coded(CodeISSN, "Must be a valid ISSN")}
+	// ErrEAN is the error returned when a string is not a valid EAN
+	ErrEAN = TextErr{coded(CodeEAN, "Must be a valid EAN")}
+)
+
+// stripHyphens removes the hyphens and spaces separating the groups
+// of digits of a code.
+func stripHyphens(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// mod11 tests whether the weighted sum of the digits of s is a multiple
+// of 11, the first digit weighing len(s). The last digit may be an X
+// standing for 10.
+func mod11(s string) bool {
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		d := int(s[i] - '0')
+		if i == len(s)-1 && (s[i] == 'X' || s[i] == 'x') {
+			d = 10
+		} else if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+		sum += d * (len(s) - i)
+	}
+	return sum%11 == 0
+}
+
+// eanChecksum tests whether the last digit of s is the check digit of
+// the digits before it, as computed for EAN and GTIN codes.
+func eanChecksum(s string) bool {
+	if !isDigits(s) {
+		return false
+	}
+	sum := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if (len(s)-1-i)%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// isbn10 tests whether a string is an ISBN-10, ignoring hyphens.
+var isbn10 = stringRule(func(s, param string) error {
+	s = stripHyphens(s)
+	if len(s) != 10 || !mod11(s) {
+		return ErrISBN10
+	}
+	return nil
+})
+
+// isbn13 tests whether a string is an ISBN-13, ignoring hyphens.
+var isbn13 = stringRule(func(s, param string) error {
+	s = stripHyphens(s)
+	if len(s) != 13 || !(strings.HasPrefix(s, "978") || strings.HasPrefix(s, "979")) || !eanChecksum(s) {
+		return ErrISBN13
+	}
+	return nil
+})
+
+// issn tests whether a string is an ISSN, with or without the hyphen
+// between its two groups of 4 digits.
+var issn = stringRule(func(s, param string) error {
+	if len(s) == 9 && s[4] == '-' {
+		s = s[:4] + s[5:]
+	}
+	if len(s) != 8 || !mod11(s) {
+		return ErrISSN
+	}
+	return nil
+})
+
+// ean tests whether a string is an EAN-8 or EAN-13 barcode number.
+var ean = stringRule(func(s, param string) error {
+	if (len(s) != 8 && len(s) != 13) || !eanChecksum(s) {
+		return ErrEAN
+	}
+	return nil
+})
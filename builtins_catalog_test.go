@@ -0,0 +1,57 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestISBN(c *C) {
+	for _, n := range []string{"0306406152", "0-306-40615-2", "080442957X", "0 8044 2957 x"} {
+		c.Assert(validator.Valid(n, "isbn10"), IsNil, Commentf(n))
+	}
+	for _, n := range []string{"0306406153", "030640615", "X306406152", "03064061a2"} {
+		c.Assert(validator.Valid(n, "isbn10"), HasError, validator.ErrISBN10, Commentf(n))
+	}
+	for _, n := range []string{"9780306406157", "978-0-306-40615-7", "9791090636071"} {
+		c.Assert(validator.Valid(n, "isbn13"), IsNil, Commentf(n))
+	}
+	for _, n := range []string{"9780306406158", "4006381333931", "978030640615"} {
+		c.Assert(validator.Valid(n, "isbn13"), HasError, validator.ErrISBN13, Commentf(n))
+	}
+}
+
+func (ms *MySuite) TestISSN(c *C) {
+	for _, n := range []string{"0378-5955", "03785955", "2434-561X"} {
+		c.Assert(validator.Valid(n, "issn"), IsNil, Commentf(n))
+	}
+	for _, n := range []string{"0378-5954", "0378 5955", "037-85955", "0378595"} {
+		c.Assert(validator.Valid(n, "issn"), HasError, validator.ErrISSN, Commentf(n))
+	}
+}
+
+func (ms *MySuite) TestEAN(c *C) {
+	for _, n := range []string{"4006381333931", "96385074", "9780306406157"} {
+		c.Assert(validator.Valid(n, "ean"), IsNil, Commentf(n))
+	}
+	for _, n := range []string{"4006381333932", "96385075", "400638133393", "40063813339a1"} {
+		c.Assert(validator.Valid(n, "ean"), HasError, validator.ErrEAN, Commentf(n))
+	}
+	c.Assert(validator.Valid(42, "ean"), HasError, validator.ErrUnsupported)
+}
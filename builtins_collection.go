@@ -0,0 +1,176 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"time"
+)
+
+var (
+	// ErrUnique is the error returned when a slice holds duplicates
+	ErrUnique = TextErr{coded(CodeUnique, "Must not contain duplicates")}
+	// ErrUniqueField is the error returned when several structs of a
+	// slice have the same value for the given field
+	ErrUniqueField = func(field string) TextErr {
+		return TextErr{codef(CodeUnique, "Must not contain duplicate %s", field)}
+	}
+	// ErrSorted is the error returned when a slice is not sorted in the
+	// given order
+	ErrSorted = func(order string) TextErr {
+		return TextErr{codef(CodeSorted, "Must be sorted in %s order", order)}
+	}
+)
+
+// unique tests whether the elements of a slice or an array, or the
+// values of a map, are all different. For structs, the field to
+// compare may be given as parameter, e.g. unique=SKU.
+func unique(v interface{}, param string) error {
+	st := reflect.ValueOf(v)
+	switch st.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+	case reflect.Ptr, reflect.Invalid:
+		return nil
+	default:
+		return ErrUnsupported
+	}
+	var values []reflect.Value
+	if st.Kind() == reflect.Map {
+		for _, k := range st.MapKeys() {
+			values = append(values, st.MapIndex(k))
+		}
+	} else {
+		for i := 0; i < st.Len(); i++ {
+			values = append(values, st.Index(i))
+		}
+	}
+
+	seen := make(map[interface{}]bool, len(values))
+	for _, e := range values {
+		for (e.Kind() == reflect.Ptr || e.Kind() == reflect.Interface) && !e.IsNil() {
+			e = e.Elem()
+		}
+		if param != "" {
+			if e.Kind() != reflect.Struct {
+				return ErrUnsupported
+			}
+			e = e.FieldByName(param)
+			if !e.IsValid() || !e.CanInterface() {
+				return ErrBadParameter
+			}
+			for e.Kind() == reflect.Ptr && !e.IsNil() {
+				e = e.Elem()
+			}
+		}
+		if !e.CanInterface() || !e.Type().Comparable() {
+			return ErrUnsupported
+		}
+		key := e.Interface()
+		if seen[key] {
+			if param != "" {
+				return ErrUniqueField(param)
+			}
+			return ErrUnique
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// sorted tests whether the elements of a slice or an array of numbers,
+// strings or times are sorted in ascending order, or descending order
+// when the parameter is desc. Equal elements are accepted.
+func sorted(v interface{}, param string) error {
+	order := "ascending"
+	switch param {
+	case "", "asc":
+	case "desc":
+		order = "descending"
+	default:
+		return ErrBadParameter
+	}
+	st := reflect.ValueOf(v)
+	switch st.Kind() {
+	case reflect.Slice, reflect.Array:
+	case reflect.Ptr, reflect.Invalid:
+		return nil
+	default:
+		return ErrUnsupported
+	}
+	for i := 1; i < st.Len(); i++ {
+		c, ok := compareValues(st.Index(i-1), st.Index(i))
+		if !ok {
+			return ErrUnsupported
+		}
+		if c > 0 && order == "ascending" || c < 0 && order == "descending" {
+			return ErrSorted(order)
+		}
+	}
+	return nil
+}
+
+// compareValues returns -1, 0 or 1 as a is lesser than, equal to or
+// greater than b, following pointers and interfaces. It returns false
+// if they are not numbers, strings or times of the same kind.
+func compareValues(a, b reflect.Value) (int, bool) {
+	for a.Kind() == reflect.Ptr || a.Kind() == reflect.Interface {
+		if a.IsNil() {
+			return 0, false
+		}
+		a = a.Elem()
+	}
+	for b.Kind() == reflect.Ptr || b.Kind() == reflect.Interface {
+		if b.IsNil() {
+			return 0, false
+		}
+		b = b.Elem()
+	}
+	if a.Kind() != b.Kind() {
+		return 0, false
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int() < b.Int(), a.Int() > b.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(a.Uint() < b.Uint(), a.Uint() > b.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(a.Float() < b.Float(), a.Float() > b.Float()), true
+	case reflect.String:
+		return compareOrdered(a.String() < b.String(), a.String() > b.String()), true
+	case reflect.Struct:
+		if !a.CanInterface() || !b.CanInterface() {
+			return 0, false
+		}
+		ta, oka := a.Interface().(time.Time)
+		tb, okb := b.Interface().(time.Time)
+		if !oka || !okb {
+			return 0, false
+		}
+		return compareOrdered(ta.Before(tb), ta.After(tb)), true
+	}
+	return 0, false
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
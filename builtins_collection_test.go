@@ -0,0 +1,84 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"time"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type lineItem struct {
+	SKU      string
+	Quantity int
+}
+
+func (ms *MySuite) TestUnique(c *C) {
+	a, b := "a", "b"
+	c.Assert(validator.Valid([]string{"a", "b"}, "unique"), IsNil)
+	c.Assert(validator.Valid([3]int{1, 2, 3}, "unique"), IsNil)
+	c.Assert(validator.Valid([]*string{&a, &b}, "unique"), IsNil)
+	c.Assert(validator.Valid(map[string]int{"a": 1, "b": 2}, "unique"), IsNil)
+	c.Assert(validator.Valid([]string(nil), "unique"), IsNil)
+
+	a2 := "a"
+	c.Assert(validator.Valid([]string{"a", "b", "a"}, "unique"), HasError, validator.ErrUnique)
+	c.Assert(validator.Valid([]*string{&a, &a2}, "unique"), HasError, validator.ErrUnique)
+	c.Assert(validator.Valid(map[string]int{"a": 1, "b": 1}, "unique"), HasError, validator.ErrUnique)
+	c.Assert(validator.Valid([]interface{}{1, "1", 1}, "unique"), HasError, validator.ErrUnique)
+
+	type order struct {
+		Items []lineItem  `validate:"unique=SKU"`
+		Refs  []*lineItem `validate:"unique=SKU"`
+	}
+	c.Assert(validator.Validate(order{Items: []lineItem{{"A", 1}, {"B", 1}}}), IsNil)
+	errs, ok := validator.Validate(order{
+		Items: []lineItem{{"A", 1}, {"B", 1}, {"A", 2}},
+		Refs:  []*lineItem{{"A", 1}, {"A", 2}},
+	}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Items"], HasError, validator.ErrUniqueField("SKU"))
+	c.Assert(errs["Refs"], HasError, validator.ErrUniqueField("SKU"))
+
+	c.Assert(validator.Valid([]lineItem{{"A", 1}}, "unique=Price"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid([]string{"a"}, "unique=SKU"), HasError, validator.ErrUnsupported)
+	c.Assert(validator.Valid([][]int{{1}}, "unique"), HasError, validator.ErrUnsupported)
+	c.Assert(validator.Valid("abc", "unique"), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestSorted(c *C) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	one, two := 1, 2
+	c.Assert(validator.Valid([]int{1, 2, 2, 3}, "sorted"), IsNil)
+	c.Assert(validator.Valid([]float64{3, 2.5, 2.5}, "sorted=desc"), IsNil)
+	c.Assert(validator.Valid([3]string{"a", "b", "c"}, "sorted=asc"), IsNil)
+	c.Assert(validator.Valid([]time.Time{t0, t0.Add(time.Hour)}, "sorted"), IsNil)
+	c.Assert(validator.Valid([]*int{&one, &two}, "sorted"), IsNil)
+	c.Assert(validator.Valid([]time.Duration{time.Second, time.Minute}, "sorted"), IsNil)
+	c.Assert(validator.Valid([]int{}, "sorted"), IsNil)
+
+	c.Assert(validator.Valid([]int{1, 3, 2}, "sorted"), HasError, validator.ErrSorted("ascending"))
+	c.Assert(validator.Valid([]uint{1, 2}, "sorted=desc"), HasError, validator.ErrSorted("descending"))
+	c.Assert(validator.Valid([]time.Time{t0, t0.Add(-time.Hour)}, "sorted"), HasError, validator.ErrSorted("ascending"))
+
+	c.Assert(validator.Valid([]int{1, 2}, "sorted=up"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid([]interface{}{1, "a"}, "sorted"), HasError, validator.ErrUnsupported)
+	c.Assert(validator.Valid([]bool{true, false}, "sorted"), HasError, validator.ErrUnsupported)
+	c.Assert(validator.Valid(42, "sorted"), HasError, validator.ErrUnsupported)
+}
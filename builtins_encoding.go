@@ -0,0 +1,244 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrEncoding is the error returned when a string is not valid in
+	// the given encoding
+	ErrEncoding = func(encoding string) TextErr {
+		return TextErr{codef(CodeEncoding, "Must be valid %s", encoding)}
+	}
+	// ErrDecodedLength is the error returned when a string does not
+	// decode to the given number of bytes
+	ErrDecodedLength = func(expected int64, actual int) TextErr {
+		return TextErr{codef(CodeDecodedLength, "Must decode to %v, was %d", plural(expected, "byte", "bytes"), actual)}
+	}
+	// ErrJWT is the error returned when a string is not a JSON Web Token
+	ErrJWT = TextErr{coded(CodeJWT, "Must be a valid JWT")}
+	// ErrDigest is the error returned when a string is not a hex
+	// encoded digest of the given algorithm
+	ErrDigest = func(algorithm string) TextErr {
+		return TextErr{codef(CodeDigest, "Must be a valid %s digest", algorithm)}
+	}
+	// ErrMediaType is the error returned when a string is not a media
+	// type
+	ErrMediaType = TextErr{coded(CodeMediaType, "Must be a valid media type")}
+	// ErrMediaTypeIn is the error returned when a media type is not one
+	// of those given
+	ErrMediaTypeIn = func(types string) TextErr {
+		return TextErr{codef(CodeMediaType, "Must be of type %s", strings.Replace(types, "|", " or ", -1))}
+	}
+	// ErrDataURI is the error returned when a string is not a data URI
+	ErrDataURI = TextErr{coded(CodeDataURI, "Must be a valid data URI")}
+	// ErrJSON is the error returned when a value is not valid JSON
+	ErrJSON = TextErr{coded(CodeJSON, "Must be valid JSON")}
+	// ErrJSONType is the error returned when a JSON document is not of
+	// the given type at its top level
+	ErrJSONType = func(kind string) TextErr {
+		return TextErr{codef(CodeJSON, "Must be a JSON %s", kind)}
+	}
+)
+
+// encodingRule returns a validation function testing whether a string
+// is decoded by one of decoders. The parameter optionally gives the
+// number of bytes it must decode to.
+func encodingRule(name string, decoders ...func(string) ([]byte, error)) ValidationFunc {
+	return stringRule(func(s, param string) error {
+		var n int64 = -1
+		if param != "" {
+			p, err := asInt(param)
+			if err != nil {
+				return ErrBadParameter
+			}
+			n = p
+		}
+		for _, decode := range decoders {
+			b, err := decode(s)
+			if err != nil {
+				continue
+			}
+			if n >= 0 && int64(len(b)) != n {
+				return ErrDecodedLength(n, len(b))
+			}
+			return nil
+		}
+		return ErrEncoding(name)
+	})
+}
+
+var (
+	hexadecimal = encodingRule("hexadecimal", hex.DecodeString)
+	base64Std   = encodingRule("base64", base64.StdEncoding.DecodeString)
+	base64URL   = encodingRule("base64url", base64.URLEncoding.DecodeString, base64.RawURLEncoding.DecodeString)
+	base32Std   = encodingRule("base32", base32.StdEncoding.DecodeString)
+)
+
+// jwt tests whether a string is structurally a JSON Web Token, made of
+// a header, a payload and a signature encoded in unpadded base64url and
+// separated by dots, whose header and payload are JSON objects. The
+// signature is not verified.
+var jwt = stringRule(func(s, param string) error {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return ErrJWT
+	}
+	for i, part := range parts {
+		b, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return ErrJWT
+		}
+		if i < 2 {
+			var obj map[string]interface{}
+			if len(b) == 0 || json.Unmarshal(b, &obj) != nil || obj == nil {
+				return ErrJWT
+			}
+		}
+	}
+	return nil
+})
+
+// digestRule returns a validation function testing whether a string
+// is a hex encoded digest of size bytes, as computed by algorithm.
+func digestRule(algorithm string, size int) ValidationFunc {
+	return stringRule(func(s, param string) error {
+		if len(s) != 2*size {
+			return ErrDigest(algorithm)
+		}
+		if _, err := hex.DecodeString(s); err != nil {
+			return ErrDigest(algorithm)
+		}
+		return nil
+	})
+}
+
+var (
+	md5Digest    = digestRule("MD5", 16)
+	sha1Digest   = digestRule("SHA-1", 20)
+	sha256Digest = digestRule("SHA-256", 32)
+	sha512Digest = digestRule("SHA-512", 64)
+)
+
+// checkMediaType tests whether s is a media type, with optional
+// parameters, among those given as parameter separated by |, where
+// image/* stands for all the image types.
+func checkMediaType(s, param string) error {
+	mt, _, err := mime.ParseMediaType(s)
+	if err != nil || !strings.Contains(mt, "/") {
+		return ErrMediaType
+	}
+	if param == "" {
+		return nil
+	}
+	for _, allowed := range strings.Split(param, "|") {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mt || strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mt, allowed[:len(allowed)-1]) {
+			return nil
+		}
+	}
+	return ErrMediaTypeIn(param)
+}
+
+// mediaType tests whether a string is a media type as parsed by
+// mime.ParseMediaType, e.g. text/html; charset=utf-8, optionally among
+// those given as parameter, e.g. image/png|image/jpeg or image/*.
+var mediaType = stringRule(checkMediaType)
+
+// dataURI tests whether a string is a data URI as defined by RFC 2397,
+// e.g. data:image/png;base64,iVBORw0KGgo=, whose data is valid base64
+// or URL escaped text. The media types accepted may be given as
+// parameter, as for mime.
+var dataURI = stringRule(func(s, param string) error {
+	if !strings.HasPrefix(s, "data:") {
+		return ErrDataURI
+	}
+	comma := strings.IndexByte(s, ',')
+	if comma < 0 {
+		return ErrDataURI
+	}
+	meta, data := s[len("data:"):comma], s[comma+1:]
+	encoded := strings.HasSuffix(meta, ";base64")
+	if encoded {
+		meta = strings.TrimSuffix(meta, ";base64")
+	}
+	if meta == "" || strings.HasPrefix(meta, ";") {
+		// the media type defaults to text/plain
+		meta = "text/plain" + meta
+	}
+	if err := checkMediaType(meta, param); err != nil {
+		if err == ErrMediaType {
+			return ErrDataURI
+		}
+		return err
+	}
+	if encoded {
+		if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+			return ErrDataURI
+		}
+	} else if _, err := url.PathUnescape(data); err != nil {
+		return ErrDataURI
+	}
+	return nil
+})
+
+// jsonRule tests whether a string or a byte slice, such as a
+// json.RawMessage, is valid JSON. The parameter optionally requires
+// the top level to be an object or an array.
+func jsonRule(v interface{}, param string) error {
+	if param != "" && param != "object" && param != "array" {
+		return ErrBadParameter
+	}
+	if isNil(v) {
+		return nil
+	}
+	var b []byte
+	st := reflect.ValueOf(v)
+	switch {
+	case st.Kind() == reflect.String:
+		b = []byte(st.String())
+	case st.Kind() == reflect.Slice && st.Type().Elem().Kind() == reflect.Uint8:
+		b = st.Bytes()
+	default:
+		return ErrUnsupported
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	// unmarshaling into a RawMessage only checks the syntax, as does
+	// json.Valid which is not available before Go 1.9
+	var raw json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return ErrJSON
+	}
+	switch {
+	case param == "object" && raw[0] != '{':
+		return ErrJSONType(param)
+	case param == "array" && raw[0] != '[':
+		return ErrJSONType(param)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestEncodings(c *C) {
+	valid := []struct{ v, tags string }{
+		{"deadBEEF", "hexadecimal"},
+		{"deadbeef", "hexadecimal=4"},
+		{"aGVsbG8/Pz8=", "base64"},
+		{"aGVsbG8/Pz8=", "base64=8"},
+		{"aGVsbG8_Pz8=", "base64url"},
+		{"aGVsbG8_Pz8", "base64url=8"},
+		{"NBSWY3DP", "base32"},
+		{"NBSWY3DP", "base32=5"},
+		{"", "base64=8"},
+	}
+	for _, t := range valid {
+		c.Assert(validator.Valid(t.v, t.tags), IsNil, Commentf("%s %s", t.v, t.tags))
+	}
+
+	c.Assert(validator.Valid("abc", "hexadecimal"), HasError, validator.ErrEncoding("hexadecimal"))
+	c.Assert(validator.Valid("0xff", "hexadecimal"), HasError, validator.ErrEncoding("hexadecimal"))
+	c.Assert(validator.Valid("aGVsbG8_Pz8=", "base64"), HasError, validator.ErrEncoding("base64"))
+	c.Assert(validator.Valid("aGVsbG8/Pz8=", "base64url"), HasError, validator.ErrEncoding("base64url"))
+	c.Assert(validator.Valid("nbswy3dp", "base32"), HasError, validator.ErrEncoding("base32"))
+	c.Assert(validator.Valid("deadbeef", "hexadecimal=32"), HasError, validator.ErrDecodedLength(32, 4))
+	c.Assert(validator.Valid("deadbeef", "hexadecimal=x"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid([]byte("deadbeef"), "hexadecimal"), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestJWT(c *C) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1234567890","name":"John Doe"}`))
+	sig := "SflKxwRJSMeKKF2QT4fwpMeJf36POk6yJV_adQssw5c"
+
+	c.Assert(validator.Valid(header+"."+payload+"."+sig, "jwt"), IsNil)
+	c.Assert(validator.Valid(header+"."+payload+".", "jwt"), IsNil)
+
+	notJSON := base64.RawURLEncoding.EncodeToString([]byte("hello"))
+	array := base64.RawURLEncoding.EncodeToString([]byte("[1]"))
+	for _, t := range []string{
+		header + "." + payload,
+		header + "." + payload + "." + sig + ".x",
+		header + "=." + payload + "." + sig,
+		header + "." + notJSON + "." + sig,
+		array + "." + payload + "." + sig,
+		"." + payload + "." + sig,
+		header + "." + payload + ".a+b",
+	} {
+		c.Assert(validator.Valid(t, "jwt"), HasError, validator.ErrJWT, Commentf(t))
+	}
+}
+
+func (ms *MySuite) TestDigests(c *C) {
+	data := []byte("hello")
+	md5sum := md5.Sum(data)
+	sha1sum := sha1.Sum(data)
+	sha256sum := sha256.Sum256(data)
+	sha512sum := sha512.Sum512(data)
+	digests := map[string]string{
+		"md5":    hex.EncodeToString(md5sum[:]),
+		"sha1":   hex.EncodeToString(sha1sum[:]),
+		"sha256": strings.ToUpper(hex.EncodeToString(sha256sum[:])),
+		"sha512": hex.EncodeToString(sha512sum[:]),
+	}
+	for rule, d := range digests {
+		c.Assert(validator.Valid(d, rule), IsNil, Commentf(rule))
+	}
+	c.Assert(validator.Valid(digests["md5"], "sha1"), HasError, validator.ErrDigest("SHA-1"))
+	c.Assert(validator.Valid(digests["sha1"]+"0", "sha1"), HasError, validator.ErrDigest("SHA-1"))
+	c.Assert(validator.Valid("g"+digests["md5"][1:], "md5"), HasError, validator.ErrDigest("MD5"))
+	c.Assert(validator.Valid(digests["sha256"][2:], "sha256"), HasError, validator.ErrDigest("SHA-256"))
+}
+
+func (ms *MySuite) TestMediaType(c *C) {
+	for _, s := range []string{"text/html", "text/html; charset=utf-8", "application/vnd.api+json", "IMAGE/PNG"} {
+		c.Assert(validator.Valid(s, "mime"), IsNil, Commentf(s))
+	}
+	for _, s := range []string{"text", "text/html; charset", "/html", "text/ html"} {
+		c.Assert(validator.Valid(s, "mime"), HasError, validator.ErrMediaType, Commentf(s))
+	}
+	c.Assert(validator.Valid("image/png", "mime=image/png|image/jpeg"), IsNil)
+	c.Assert(validator.Valid("image/webp", "mime=image/*"), IsNil)
+	c.Assert(validator.Valid("image/gif", "mime=image/png|image/jpeg"), HasError, validator.ErrMediaTypeIn("image/png|image/jpeg"))
+	c.Assert(validator.Valid("text/plain", "mime=image/*"), HasError, validator.ErrMediaTypeIn("image/*"))
+}
+
+func (ms *MySuite) TestDataURI(c *C) {
+	for _, s := range []string{
+		"data:,Hello%2C%20World%21",
+		"data:text/plain;base64,SGVsbG8sIFdvcmxkIQ==",
+		"data:image/png;base64,iVBORw0KGgo=",
+		"data:;charset=utf-8,hi",
+		"data:text/html;charset=utf-8,%3Ch1%3Ehi%3C%2Fh1%3E",
+	} {
+		c.Assert(validator.Valid(s, "datauri"), IsNil, Commentf(s))
+	}
+	for _, s := range []string{
+		"Hello",
+		"data:text/plain",
+		"data:text/plain;base64,SGVsbG8$",
+		"data:text,hi",
+		"data:,100%",
+	} {
+		c.Assert(validator.Valid(s, "datauri"), HasError, validator.ErrDataURI, Commentf(s))
+	}
+	c.Assert(validator.Valid("data:image/png;base64,iVBORw0KGgo=", "datauri=image/*"), IsNil)
+	c.Assert(validator.Valid("data:,hi", "datauri=image/*"), HasError, validator.ErrMediaTypeIn("image/*"))
+}
+
+func (ms *MySuite) TestJSON(c *C) {
+	type blob struct {
+		Settings string          `validate:"json=object"`
+		Items    json.RawMessage `validate:"json=array"`
+		Any      []byte          `validate:"json"`
+	}
+	c.Assert(validator.Validate(blob{`{"a": 1}`, json.RawMessage(` [1, 2]`), []byte("42")}), IsNil)
+	c.Assert(validator.Validate(blob{}), IsNil)
+
+	errs, ok := validator.Validate(blob{`[1]`, json.RawMessage(`{}`), []byte(`{"a":}`)}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Settings"], HasError, validator.ErrJSONType("object"))
+	c.Assert(errs["Items"], HasError, validator.ErrJSONType("array"))
+	c.Assert(errs["Any"], HasError, validator.ErrJSON)
+
+	c.Assert(validator.Valid(`{"a": 1} {}`, "json"), HasError, validator.ErrJSON)
+	c.Assert(validator.Valid(`{}`, "json=string"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(42, "json"), HasError, validator.ErrUnsupported)
+}
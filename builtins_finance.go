@@ -0,0 +1,185 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCreditCard is the error returned when a string is not a
+	// valid credit card number
+	ErrCreditCard = TextErr{coded(CodeCreditCard, "Must be a valid credit card number")}
+	// ErrCreditCardBrand is the error returned when a credit card
+	// number is not of one of the brands specified
+	ErrCreditCardBrand = func(brands string) TextErr {
+		return TextErr{codef(CodeCreditCard, "Must be a %s card number", strings.Replace(brands, "|", " or ", -1))}
+	}
+	// ErrIBAN is the error returned when a string is not a valid IBAN
+	ErrIBAN = TextErr{coded(CodeIBAN, "Must be a valid IBAN")}
+	// ErrBIC is the error returned when a string is not a valid BIC
+	ErrBIC = TextErr{coded(CodeBIC, "Must be a valid BIC")}
+)
+
+// cardBrand holds the IIN ranges and the lengths of the numbers of
+// a card brand.
+type cardBrand struct {
+	// prefixes holds ranges of prefixes, all of the same length.
+	prefixes [][2]int
+	lengths  [2]int
+}
+
+var cardBrands = map[string][]cardBrand{
+	"visa":       {{[][2]int{{4, 4}}, [2]int{13, 19}}},
+	"mastercard": {{[][2]int{{51, 55}}, [2]int{16, 16}}, {[][2]int{{2221, 2720}}, [2]int{16, 16}}},
+	"amex":       {{[][2]int{{34, 34}, {37, 37}}, [2]int{15, 15}}},
+	"discover":   {{[][2]int{{6011, 6011}}, [2]int{16, 19}}, {[][2]int{{644, 649}}, [2]int{16, 19}}, {[][2]int{{65, 65}}, [2]int{16, 19}}},
+	"dinersclub": {{[][2]int{{300, 305}}, [2]int{14, 19}}, {[][2]int{{36, 36}, {38, 39}}, [2]int{14, 19}}},
+	"jcb":        {{[][2]int{{3528, 3589}}, [2]int{16, 19}}},
+	"unionpay":   {{[][2]int{{62, 62}}, [2]int{16, 19}}},
+	"maestro":    {{[][2]int{{50, 50}, {56, 69}}, [2]int{12, 19}}},
+}
+
+// creditCard tests whether a string is a credit card number passing
+// the Luhn check. Spaces and dashes between digits are ignored. The
+// parameter optionally restricts the brands accepted, separated by |,
+// e.g. visa|mastercard.
+var creditCard = stringRule(func(s, param string) error {
+	digits := stripHyphens(s)
+	if len(digits) < 12 || len(digits) > 19 || !isDigits(digits) || !luhn(digits) {
+		return ErrCreditCard
+	}
+	if param == "" {
+		return nil
+	}
+	for _, name := range strings.Split(param, "|") {
+		brands, ok := cardBrands[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return ErrBadParameter
+		}
+		for _, b := range brands {
+			if b.matches(digits) {
+				return nil
+			}
+		}
+	}
+	return ErrCreditCardBrand(param)
+})
+
+// matches reports whether the card number digits is of brand b.
+func (b cardBrand) matches(digits string) bool {
+	if len(digits) < b.lengths[0] || len(digits) > b.lengths[1] {
+		return false
+	}
+	for _, r := range b.prefixes {
+		n := len(strconv.Itoa(r[0]))
+		p, _ := strconv.Atoi(digits[:n])
+		if p >= r[0] && p <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// luhn reports whether the digits pass the Luhn check.
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// isDigits reports whether s only holds ASCII digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ibanLengths holds the length of the IBANs of each country.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22,
+	"CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20,
+	"EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22,
+	"GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30,
+	"KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21,
+	"LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27,
+	"MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33,
+	"SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27,
+	"SO": 23, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26, "UA": 29,
+	"VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// iban tests whether a string is an IBAN of the length of its country
+// with a valid checksum. Spaces are ignored, as in the print format.
+var iban = stringRule(func(s, param string) error {
+	s = strings.ToUpper(strings.Replace(s, " ", "", -1))
+	if len(s) < 4 || ibanLengths[s[:2]] != len(s) || !isDigits(s[2:4]) {
+		return ErrIBAN
+	}
+	// move the country code and check digits to the end and compute
+	// the remainder of the number where letters stand for 10 to 35
+	rem := 0
+	for _, r := range s[4:] + s[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			rem = (rem*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			rem = (rem*100 + int(r-'A') + 10) % 97
+		default:
+			return ErrIBAN
+		}
+	}
+	if rem != 1 {
+		return ErrIBAN
+	}
+	return nil
+})
+
+// bic tests whether a string is a BIC, also known as SWIFT code, made
+// of a 4 letters institution code, a 2 letters country code, a 2
+// characters location code and an optional 3 characters branch code.
+var bic = stringRule(func(s, param string) error {
+	if len(s) != 8 && len(s) != 11 {
+		return ErrBIC
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		upper := c >= 'A' && c <= 'Z'
+		if !upper && (i < 6 || c < '0' || c > '9') {
+			return ErrBIC
+		}
+	}
+	return nil
+})
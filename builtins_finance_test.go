@@ -0,0 +1,84 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestCreditCard(c *C) {
+	for _, n := range []string{
+		"4111111111111111",
+		"4111 1111 1111 1111",
+		"5555-5555-5555-4444",
+		"2223003122003222",
+		"378282246310005",
+		"6011111111111117",
+		"3530111333300000",
+		"30569309025904",
+		"6200000000000005",
+	} {
+		c.Assert(validator.Valid(n, "credit_card"), IsNil, Commentf(n))
+	}
+	for _, n := range []string{"4111111111111112", "4111-1111-1111-111a", "41111", "12345678901234567890"} {
+		c.Assert(validator.Valid(n, "credit_card"), HasError, validator.ErrCreditCard, Commentf(n))
+	}
+	c.Assert(validator.Valid("", "credit_card"), IsNil)
+	c.Assert(validator.Valid(4111111111111111, "credit_card"), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestCreditCardBrand(c *C) {
+	c.Assert(validator.Valid("4111111111111111", "credit_card=visa"), IsNil)
+	c.Assert(validator.Valid("2223003122003222", "credit_card=visa|mastercard"), IsNil)
+	c.Assert(validator.Valid("378282246310005", "credit_card=Amex"), IsNil)
+	c.Assert(validator.Valid("378282246310005", "credit_card=visa|mastercard"),
+		HasError, validator.ErrCreditCardBrand("visa|mastercard"))
+	c.Assert(validator.ErrCreditCardBrand("visa|mastercard").Error(), Equals, "Must be a visa or mastercard card number")
+	c.Assert(validator.Valid("4111111111111111", "credit_card=foo"), HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestIBAN(c *C) {
+	for _, n := range []string{
+		"GB82WEST12345698765432",
+		"GB82 WEST 1234 5698 7654 32",
+		"DE89370400440532013000",
+		"fr1420041010050500013m02606",
+		"NO9386011117947",
+	} {
+		c.Assert(validator.Valid(n, "iban"), IsNil, Commentf(n))
+	}
+	for _, n := range []string{
+		"GB83WEST12345698765432",
+		"GB82WEST1234569876543",
+		"XX82WEST12345698765432",
+		"GB82WEST12345698765-32",
+		"GB",
+	} {
+		c.Assert(validator.Valid(n, "iban"), HasError, validator.ErrIBAN, Commentf(n))
+	}
+}
+
+func (ms *MySuite) TestBIC(c *C) {
+	for _, n := range []string{"DEUTDEFF", "DEUTDEFF500", "NEDSZAJJXXX"} {
+		c.Assert(validator.Valid(n, "bic"), IsNil, Commentf(n))
+	}
+	for _, n := range []string{"DEUTDEF", "DEUTDEFF50", "deutdeff", "DEU1DEFF", "DEUTDEFF-00"} {
+		c.Assert(validator.Valid(n, "bic"), HasError, validator.ErrBIC, Commentf(n))
+	}
+}
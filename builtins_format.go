@@ -0,0 +1,112 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrSemver is the error returned when a string is not a semantic
+	// version
+	ErrSemver = TextErr{coded(CodeSemver, "Must be a valid semantic version")}
+	// ErrColor is the error returned when a string is not a color in
+	// the given notation
+	ErrColor = func(notation string) TextErr {
+		return TextErr{codef(CodeColor, "Must be a valid %s color", notation)}
+	}
+)
+
+// semverPattern is the pattern of the versions of SemVer 2.0.0.
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// semver tests whether a string is a version following SemVer 2.0.0.
+// A leading v, as in git tags, is accepted when the parameter is v.
+var semver = stringRule(func(s, param string) error {
+	if param == "v" {
+		s = strings.TrimPrefix(s, "v")
+	} else if param != "" {
+		return ErrBadParameter
+	}
+	if !semverPattern.MatchString(s) {
+		return ErrSemver
+	}
+	return nil
+})
+
+// hexColorPattern is the pattern of CSS hex colors, with 3, 4, 6 or 8
+// digits.
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// hexColor tests whether a string is a CSS hex color, e.g. #1e90ff.
+var hexColor = stringRule(func(s, param string) error {
+	if !hexColorPattern.MatchString(s) {
+		return ErrColor("hex")
+	}
+	return nil
+})
+
+// colorComponent describes a component of a CSS color function: a
+// number between 0 and max, unless max is 0, and a percentage if percent
+// is set.
+type colorComponent struct {
+	max     float64
+	percent bool
+}
+
+// colorFunc returns a validation function testing whether a string is
+// a call of the CSS color function name, e.g. rgb(30, 144, 255), with
+// the given components.
+func colorFunc(name string, components ...colorComponent) ValidationFunc {
+	return stringRule(func(s, param string) error {
+		s = strings.TrimSpace(s)
+		if !strings.HasPrefix(s, name+"(") || !strings.HasSuffix(s, ")") {
+			return ErrColor(name)
+		}
+		args := strings.Split(s[len(name)+1:len(s)-1], ",")
+		if len(args) != len(components) {
+			return ErrColor(name)
+		}
+		for i, c := range components {
+			arg := strings.TrimSpace(args[i])
+			max := c.max
+			if strings.HasSuffix(arg, "%") {
+				if !c.percent {
+					return ErrColor(name)
+				}
+				arg, max = arg[:len(arg)-1], 100
+			} else if max == 0 {
+				return ErrColor(name)
+			}
+			f, err := strconv.ParseFloat(arg, 64)
+			if err != nil || !(f >= 0 && f <= max) {
+				return ErrColor(name)
+			}
+		}
+		return nil
+	})
+}
+
+var (
+	rgbColor  = colorFunc("rgb", colorComponent{255, true}, colorComponent{255, true}, colorComponent{255, true})
+	rgbaColor = colorFunc("rgba", colorComponent{255, true}, colorComponent{255, true}, colorComponent{255, true}, colorComponent{1, true})
+	hslColor  = colorFunc("hsl", colorComponent{360, false}, colorComponent{0, true}, colorComponent{0, true})
+)
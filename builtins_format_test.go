@@ -0,0 +1,74 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestSemver(c *C) {
+	for _, v := range []string{"0.0.4", "1.2.3", "10.20.30", "1.1.2-prerelease+meta", "1.0.0-alpha.beta.1", "1.0.0+21AF26D3----117B344092BD"} {
+		c.Assert(validator.Valid(v, "semver"), IsNil, Commentf(v))
+	}
+	for _, v := range []string{"1", "1.2", "01.1.1", "1.2.3-0123", "1.2.3-", "1.2.3+", "v1.2.3", "1.2.3.4"} {
+		c.Assert(validator.Valid(v, "semver"), HasError, validator.ErrSemver, Commentf(v))
+	}
+	c.Assert(validator.Valid("v1.2.3", "semver=v"), IsNil)
+	c.Assert(validator.Valid("1.2.3", "semver=v"), IsNil)
+	c.Assert(validator.Valid("vv1.2.3", "semver=v"), HasError, validator.ErrSemver)
+	c.Assert(validator.Valid("1.2.3", "semver=x"), HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestColors(c *C) {
+	valid := []struct{ v, rule string }{
+		{"#fff", "hexcolor"},
+		{"#1E90FF", "hexcolor"},
+		{"#1e90ff80", "hexcolor"},
+		{"rgb(30, 144, 255)", "rgb"},
+		{"rgb(10%,50%,100%)", "rgb"},
+		{"rgba(30, 144, 255, 0.5)", "rgba"},
+		{"rgba(30, 144, 255, 50%)", "rgba"},
+		{"hsl(210, 100%, 56%)", "hsl"},
+		{"hsl(0,0%,0%)", "hsl"},
+		{"hsl(360, 0.5%, 100%)", "hsl"},
+	}
+	for _, t := range valid {
+		c.Assert(validator.Valid(t.v, t.rule), IsNil, Commentf(t.v))
+	}
+	invalid := []struct{ v, rule, notation string }{
+		{"fff", "hexcolor", "hex"},
+		{"#ff", "hexcolor", "hex"},
+		{"#fffff", "hexcolor", "hex"},
+		{"#ggg", "hexcolor", "hex"},
+		{"rgb(256, 0, 0)", "rgb", "rgb"},
+		{"rgb(0, 0)", "rgb", "rgb"},
+		{"rgb(0, 0, 0, 1)", "rgb", "rgb"},
+		{"rgb(-1, 0, 0)", "rgb", "rgb"},
+		{"rgba(0, 0, 0, 1.5)", "rgba", "rgba"},
+		{"rgba(0, 0, 0)", "rgba", "rgba"},
+		{"hsl(210, 100, 56%)", "hsl", "hsl"},
+		{"hsl(210%, 100%, 56%)", "hsl", "hsl"},
+		{"hsl(361, 100%, 56%)", "hsl", "hsl"},
+		{"hsla(210, 100%, 56%)", "hsl", "hsl"},
+		{"hsl(0, 0, 0)", "hsl", "hsl"},
+	}
+	for _, t := range invalid {
+		c.Assert(validator.Valid(t.v, t.rule), HasError, validator.ErrColor(t.notation), Commentf(t.v))
+	}
+}
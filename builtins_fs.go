@@ -0,0 +1,80 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+var (
+	// ErrFile is the error returned when a path is not an existing
+	// file
+	ErrFile = TextErr{coded(CodeFile, "Must be an existing file")}
+	// ErrDir is the error returned when a path is not an existing
+	// directory
+	ErrDir = TextErr{coded(CodeDir, "Must be an existing directory")}
+	// ErrFilePath is the error returned when a string is not a valid
+	// path
+	ErrFilePath = TextErr{coded(CodeFilePath, "Must be a valid file path")}
+)
+
+// file tests whether a string is the path of an existing file, other
+// than a directory. It does I/O, stating the file.
+var file = stringRule(func(s, param string) error {
+	fi, err := os.Stat(s)
+	if err != nil || fi.IsDir() {
+		return ErrFile
+	}
+	return nil
+})
+
+// dir tests whether a string is the path of an existing directory. It
+// does I/O, stating the directory.
+var dir = stringRule(func(s, param string) error {
+	fi, err := os.Stat(s)
+	if err != nil || !fi.IsDir() {
+		return ErrDir
+	}
+	return nil
+})
+
+// filePath tests whether a string is a syntactically valid path for
+// the current operating system, whether it exists or not.
+var filePath = stringRule(func(s, param string) error {
+	if !isFilePath(s) {
+		return ErrFilePath
+	}
+	return nil
+})
+
+// isFilePath reports whether s is a syntactically valid path: paths may
+// not contain NUL bytes and, on Windows, the characters reserved in file
+// names, other than the colon of a drive letter.
+func isFilePath(s string) bool {
+	if strings.IndexByte(s, 0) >= 0 {
+		return false
+	}
+	if runtime.GOOS != "windows" {
+		return true
+	}
+	if len(s) >= 2 && s[1] == ':' && isASCIILetter(rune(s[0])) {
+		s = s[2:]
+	}
+	return !strings.ContainsAny(s, `<>:"|?*`)
+}
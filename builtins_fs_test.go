@@ -0,0 +1,47 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestFileAndDir(c *C) {
+	tmp := c.MkDir()
+	name := filepath.Join(tmp, "config.yaml")
+	c.Assert(ioutil.WriteFile(name, []byte("a: 1"), 0600), IsNil)
+	missing := filepath.Join(tmp, "missing")
+
+	c.Assert(validator.Valid(name, "file"), IsNil)
+	c.Assert(validator.Valid(tmp, "file"), HasError, validator.ErrFile)
+	c.Assert(validator.Valid(missing, "file"), HasError, validator.ErrFile)
+
+	c.Assert(validator.Valid(tmp, "dir"), IsNil)
+	c.Assert(validator.Valid(name, "dir"), HasError, validator.ErrDir)
+	c.Assert(validator.Valid(missing, "dir"), HasError, validator.ErrDir)
+
+	c.Assert(validator.Valid(missing, "filepath"), IsNil)
+	c.Assert(validator.Valid("relative/dir/file.txt", "filepath"), IsNil)
+	c.Assert(validator.Valid("bad\x00path", "filepath"), HasError, validator.ErrFilePath)
+	c.Assert(validator.Valid(os.PathSeparator, "filepath"), HasError, validator.ErrUnsupported)
+}
@@ -0,0 +1,76 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"strconv"
+)
+
+var (
+	// ErrLatitude is the error returned when a value is not a latitude
+	// between -90 and 90
+	ErrLatitude = TextErr{coded(CodeLatitude, "Must be a latitude between -90 and 90")}
+	// ErrLongitude is the error returned when a value is not a
+	// longitude between -180 and 180
+	ErrLongitude = TextErr{coded(CodeLongitude, "Must be a longitude between -180 and 180")}
+)
+
+// latitude tests whether a number, or a string holding a number, is
+// between -90 and 90.
+func latitude(v interface{}, param string) error {
+	return coordinate(v, 90, ErrLatitude)
+}
+
+// longitude tests whether a number, or a string holding a number, is
+// between -180 and 180.
+func longitude(v interface{}, param string) error {
+	return coordinate(v, 180, ErrLongitude)
+}
+
+// coordinate tests whether a number, or a string holding a number, is
+// between -limit and limit, returning err otherwise.
+func coordinate(v interface{}, limit float64, err error) error {
+	if isNil(v) {
+		return nil
+	}
+	var f float64
+	st := reflect.ValueOf(v)
+	switch st.Kind() {
+	case reflect.String:
+		if st.Len() == 0 {
+			return nil
+		}
+		var perr error
+		f, perr = strconv.ParseFloat(st.String(), 64)
+		if perr != nil {
+			return err
+		}
+	case reflect.Float32, reflect.Float64:
+		f = st.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(st.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f = float64(st.Uint())
+	default:
+		return ErrUnsupported
+	}
+	if !(f >= -limit && f <= limit) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"math"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestLatitudeLongitude(c *C) {
+	type place struct {
+		Lat    float64 `validate:"latitude"`
+		Lng    float32 `validate:"longitude"`
+		LatStr string  `validate:"latitude"`
+		LngStr *string `validate:"longitude"`
+	}
+	lng := "174.7633"
+	c.Assert(validator.Validate(place{-36.8485, 174.7633, "-90", &lng}), IsNil)
+	c.Assert(validator.Validate(place{90, -180, "", nil}), IsNil)
+
+	bad := "east"
+	errs, ok := validator.Validate(place{90.5, 180.1, "1e3", &bad}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Lat"], HasError, validator.ErrLatitude)
+	c.Assert(errs["Lng"], HasError, validator.ErrLongitude)
+	c.Assert(errs["LatStr"], HasError, validator.ErrLatitude)
+	c.Assert(errs["LngStr"], HasError, validator.ErrLongitude)
+
+	c.Assert(validator.Valid(math.NaN(), "latitude"), HasError, validator.ErrLatitude)
+	c.Assert(validator.Valid(45, "latitude"), IsNil)
+	c.Assert(validator.Valid(true, "longitude"), HasError, validator.ErrUnsupported)
+}
@@ -0,0 +1,283 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrCountryCode is the error returned when a string is not an
+	// ISO 3166-1 country code
+	ErrCountryCode = TextErr{coded(CodeCountryCode, "Must be a valid country code")}
+	// ErrCurrencyCode is the error returned when a string is not an
+	// ISO 4217 currency code
+	ErrCurrencyCode = TextErr{coded(CodeCurrencyCode, "Must be a valid currency code")}
+	// ErrLanguageTag is the error returned when a string is not a
+	// BCP 47 language tag
+	ErrLanguageTag = TextErr{coded(CodeLanguageTag, "Must be a valid language tag")}
+)
+
+// isoCodes holds the code tables used by the iso3166_alpha2,
+// iso3166_alpha3, iso4217 and bcp47 rules, all in upper case.
+var isoCodes = struct {
+	sync.RWMutex
+	alpha2     map[string]bool
+	alpha3     map[string]bool
+	currencies map[string]bool
+	languages  map[string]bool
+}{}
+
+func init() {
+	SetCountryCodes(nil)
+	SetCurrencyCodes()
+	SetLanguageCodes()
+}
+
+// SetCountryCodes replaces the ISO 3166-1 country codes known to the
+// iso3166_alpha2, iso3166_alpha3 and bcp47 rules, given as alpha-2 codes
+// mapped to the matching alpha-3 codes. The package embeds the codes
+// assigned when it was released, which a nil map restores.
+func SetCountryCodes(codes map[string]string) {
+	if codes == nil {
+		codes = map[string]string{}
+		fields := strings.Fields(countryCodes)
+		for i := 0; i+1 < len(fields); i += 2 {
+			codes[fields[i]] = fields[i+1]
+		}
+	}
+	alpha2 := make(map[string]bool, len(codes))
+	alpha3 := make(map[string]bool, len(codes))
+	for a2, a3 := range codes {
+		alpha2[strings.ToUpper(a2)] = true
+		if a3 != "" {
+			alpha3[strings.ToUpper(a3)] = true
+		}
+	}
+	isoCodes.Lock()
+	defer isoCodes.Unlock()
+	isoCodes.alpha2, isoCodes.alpha3 = alpha2, alpha3
+}
+
+// SetCurrencyCodes replaces the ISO 4217 currency codes known to the
+// iso4217 rule. Without codes, it restores the embedded ones.
+func SetCurrencyCodes(codes ...string) {
+	if len(codes) == 0 {
+		codes = strings.Fields(currencyCodes)
+	}
+	m := upperSet(codes)
+	isoCodes.Lock()
+	defer isoCodes.Unlock()
+	isoCodes.currencies = m
+}
+
+// SetLanguageCodes replaces the ISO 639-1 two letters language codes
+// known to the bcp47 rule. Longer language subtags are only checked
+// for their form. Without codes, it restores the embedded ones.
+func SetLanguageCodes(codes ...string) {
+	if len(codes) == 0 {
+		codes = strings.Fields(languageCodes)
+	}
+	m := upperSet(codes)
+	isoCodes.Lock()
+	defer isoCodes.Unlock()
+	isoCodes.languages = m
+}
+
+func upperSet(codes []string) map[string]bool {
+	m := make(map[string]bool, len(codes))
+	for _, c := range codes {
+		m[strings.ToUpper(c)] = true
+	}
+	return m
+}
+
+// isoCode returns a validation function testing whether a string is
+// one of the codes returned by table, regardless of case.
+func isoCode(table func() map[string]bool, err error) ValidationFunc {
+	return stringRule(func(s, param string) error {
+		isoCodes.RLock()
+		defer isoCodes.RUnlock()
+		if !table()[strings.ToUpper(s)] {
+			return err
+		}
+		return nil
+	})
+}
+
+var (
+	iso3166Alpha2 = isoCode(func() map[string]bool { return isoCodes.alpha2 }, ErrCountryCode)
+	iso3166Alpha3 = isoCode(func() map[string]bool { return isoCodes.alpha3 }, ErrCountryCode)
+	iso4217       = isoCode(func() map[string]bool { return isoCodes.currencies }, ErrCurrencyCode)
+)
+
+// bcp47 tests whether a string is a well-formed BCP 47 language tag,
+// such as en, en-NZ, zh-Hant-TW or es-419. Two letters languages and
+// regions must also be known ISO 639-1 and ISO 3166-1 codes.
+var bcp47 = stringRule(func(s, param string) error {
+	if !isLanguageTag(s) {
+		return ErrLanguageTag
+	}
+	return nil
+})
+
+// isLanguageTag tests whether s follows the langtag or privateuse
+// productions of RFC 5646.
+func isLanguageTag(s string) bool {
+	subtags := strings.Split(strings.ToUpper(s), "-")
+	for _, st := range subtags {
+		if len(st) == 0 || len(st) > 8 || !isUpperAlphanum(st) {
+			return false
+		}
+	}
+	if subtags[0] == "X" {
+		return len(subtags) > 1
+	}
+
+	isoCodes.RLock()
+	defer isoCodes.RUnlock()
+	lang := subtags[0]
+	switch {
+	case len(lang) == 2 && isUpperAlpha(lang):
+		if !isoCodes.languages[lang] {
+			return false
+		}
+	case len(lang) >= 3 && isUpperAlpha(lang):
+	default:
+		return false
+	}
+	i := 1
+	// extended language subtags
+	for n := 0; n < 3 && len(lang) <= 3 && i < len(subtags) && len(subtags[i]) == 3 && isUpperAlpha(subtags[i]); n++ {
+		i++
+	}
+	// script
+	if i < len(subtags) && len(subtags[i]) == 4 && isUpperAlpha(subtags[i]) {
+		i++
+	}
+	// region
+	if i < len(subtags) {
+		region := subtags[i]
+		if len(region) == 2 && isUpperAlpha(region) {
+			if !isoCodes.alpha2[region] {
+				return false
+			}
+			i++
+		} else if len(region) == 3 && isDigits(region) {
+			i++
+		}
+	}
+	// variants
+	for i < len(subtags) && (len(subtags[i]) >= 5 || len(subtags[i]) == 4 && subtags[i][0] >= '0' && subtags[i][0] <= '9') {
+		i++
+	}
+	// extensions and private use
+	for i < len(subtags) {
+		singleton := subtags[i]
+		if len(singleton) != 1 {
+			return false
+		}
+		i++
+		n := 0
+		for ; i < len(subtags) && (singleton == "X" || len(subtags[i]) >= 2); i++ {
+			n++
+		}
+		if n == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isUpperAlpha tests whether s only holds upper case ASCII letters.
+func isUpperAlpha(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// isUpperAlphanum tests whether s only holds upper case ASCII letters
+// and digits.
+func isUpperAlphanum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < 'A' || s[i] > 'Z') && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// countryCodes holds the ISO 3166-1 alpha-2 codes, each followed by
+// the matching alpha-3 code.
+const countryCodes = `
+AD AND AE ARE AF AFG AG ATG AI AIA AL ALB AM ARM AO AGO AQ ATA AR ARG
+AS ASM AT AUT AU AUS AW ABW AX ALA AZ AZE BA BIH BB BRB BD BGD BE BEL
+BF BFA BG BGR BH BHR BI BDI BJ BEN BL BLM BM BMU BN BRN BO BOL BQ BES
+BR BRA BS BHS BT BTN BV BVT BW BWA BY BLR BZ BLZ CA CAN CC CCK CD COD
+CF CAF CG COG CH CHE CI CIV CK COK CL CHL CM CMR CN CHN CO COL CR CRI
+CU CUB CV CPV CW CUW CX CXR CY CYP CZ CZE DE DEU DJ DJI DK DNK DM DMA
+DO DOM DZ DZA EC ECU EE EST EG EGY EH ESH ER ERI ES ESP ET ETH FI FIN
+FJ FJI FK FLK FM FSM FO FRO FR FRA GA GAB GB GBR GD GRD GE GEO GF GUF
+GG GGY GH GHA GI GIB GL GRL GM GMB GN GIN GP GLP GQ GNQ GR GRC GS SGS
+GT GTM GU GUM GW GNB GY GUY HK HKG HM HMD HN HND HR HRV HT HTI HU HUN
+ID IDN IE IRL IL ISR IM IMN IN IND IO IOT IQ IRQ IR IRN IS ISL IT ITA
+JE JEY JM JAM JO JOR JP JPN KE KEN KG KGZ KH KHM KI KIR KM COM KN KNA
+KP PRK KR KOR KW KWT KY CYM KZ KAZ LA LAO LB LBN LC LCA LI LIE LK LKA
+LR LBR LS LSO LT LTU LU LUX LV LVA LY LBY MA MAR MC MCO MD MDA ME MNE
+MF MAF MG MDG MH MHL MK MKD ML MLI MM MMR MN MNG MO MAC MP MNP MQ MTQ
+MR MRT MS MSR MT MLT MU MUS MV MDV MW MWI MX MEX MY MYS MZ MOZ NA NAM
+NC NCL NE NER NF NFK NG NGA NI NIC NL NLD NO NOR NP NPL NR NRU NU NIU
+NZ NZL OM OMN PA PAN PE PER PF PYF PG PNG PH PHL PK PAK PL POL PM SPM
+PN PCN PR PRI PS PSE PT PRT PW PLW PY PRY QA QAT RE REU RO ROU RS SRB
+RU RUS RW RWA SA SAU SB SLB SC SYC SD SDN SE SWE SG SGP SH SHN SI SVN
+SJ SJM SK SVK SL SLE SM SMR SN SEN SO SOM SR SUR SS SSD ST STP SV SLV
+SX SXM SY SYR SZ SWZ TC TCA TD TCD TF ATF TG TGO TH THA TJ TJK TK TKL
+TL TLS TM TKM TN TUN TO TON TR TUR TT TTO TV TUV TW TWN TZ TZA UA UKR
+UG UGA UM UMI US USA UY URY UZ UZB VA VAT VC VCT VE VEN VG VGB VI VIR
+VN VNM VU VUT WF WLF WS WSM YE YEM YT MYT ZA ZAF ZM ZMB ZW ZWE
+`
+
+// currencyCodes holds the ISO 4217 currency codes.
+const currencyCodes = `
+AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND
+BOB BOV BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CLF CLP CNY COP COU
+CRC CUC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS
+GIP GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY
+KES KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA
+MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD
+OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK
+SGD SHP SLE SLL SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD
+TWD TZS UAH UGX USD USN UYI UYU UYW UZS VED VES VND VUV WST XAF XAG XAU
+XBA XBB XBC XBD XCD XCG XDR XOF XPD XPF XPT XSU XTS XUA XXX YER ZAR ZMW
+ZWG ZWL
+`
+
+// languageCodes holds the ISO 639-1 language codes.
+const languageCodes = `
+aa ab ae af ak am an ar as av ay az ba be bg bi bm bn bo br bs ca ce ch
+co cr cs cu cv cy da de dv dz ee el en eo es et eu fa ff fi fj fo fr fy
+ga gd gl gn gu gv ha he hi ho hr ht hu hy hz ia id ie ig ii ik io is it
+iu ja jv ka kg ki kj kk kl km kn ko kr ks ku kv kw ky la lb lg li ln lo
+lt lu lv mg mh mi mk ml mn mr ms mt my na nb nd ne ng nl nn no nr nv ny
+oc oj om or os pa pi pl ps pt qu rm rn ro ru rw sa sc sd se sg si sk sl
+sm sn so sq sr ss st su sv sw ta te tg th ti tk tl tn to tr ts tt tw ty
+ug uk ur uz ve vi vo wa wo xh yi yo za zh zu
+`
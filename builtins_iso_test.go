@@ -0,0 +1,70 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestISOCodes(c *C) {
+	type account struct {
+		Country  string `validate:"iso3166_alpha2"`
+		Country3 string `validate:"iso3166_alpha3"`
+		Currency string `validate:"iso4217"`
+	}
+	c.Assert(validator.Validate(account{"NZ", "NZL", "NZD"}), IsNil)
+	c.Assert(validator.Validate(account{"nz", "nzl", "nzd"}), IsNil)
+	c.Assert(validator.Validate(account{}), IsNil)
+
+	errs, ok := validator.Validate(account{"ZZ", "NZ", "XYZ"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Country"], HasError, validator.ErrCountryCode)
+	c.Assert(errs["Country3"], HasError, validator.ErrCountryCode)
+	c.Assert(errs["Currency"], HasError, validator.ErrCurrencyCode)
+}
+
+func (ms *MySuite) TestBCP47(c *C) {
+	for _, t := range []string{
+		"en", "en-NZ", "mi-NZ", "zh-Hant-TW", "es-419", "sr-Latn-RS",
+		"de-CH-1901", "zh-yue-HK", "ast", "en-US-u-ca-gregory", "en-x-private", "x-whatever",
+	} {
+		c.Assert(validator.Valid(t, "bcp47"), IsNil, Commentf(t))
+	}
+	for _, t := range []string{
+		"e", "qq", "en-ZZ", "en_US", "en-", "-en", "en-US-u", "en-a-b", "x", "toolonglang1", "en-x-toolongsubtag",
+	} {
+		c.Assert(validator.Valid(t, "bcp47"), HasError, validator.ErrLanguageTag, Commentf(t))
+	}
+}
+
+func (ms *MySuite) TestSetISOCodes(c *C) {
+	validator.SetCountryCodes(map[string]string{"NZ": "NZL", "AU": "AUS", "XK": "XKX"})
+	validator.SetCurrencyCodes("NZD", "AUD")
+	c.Assert(validator.Valid("XK", "iso3166_alpha2"), IsNil)
+	c.Assert(validator.Valid("XKX", "iso3166_alpha3"), IsNil)
+	c.Assert(validator.Valid("US", "iso3166_alpha2"), HasError, validator.ErrCountryCode)
+	c.Assert(validator.Valid("USD", "iso4217"), HasError, validator.ErrCurrencyCode)
+	c.Assert(validator.Valid("en-XK", "bcp47"), IsNil)
+
+	validator.SetCountryCodes(nil)
+	validator.SetCurrencyCodes()
+	c.Assert(validator.Valid("US", "iso3166_alpha2"), IsNil)
+	c.Assert(validator.Valid("USD", "iso4217"), IsNil)
+	c.Assert(validator.Valid("XK", "iso3166_alpha2"), HasError, validator.ErrCountryCode)
+}
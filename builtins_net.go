@@ -0,0 +1,182 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrHostname is the error returned when a string is not a
+	// hostname
+	ErrHostname = TextErr{coded(CodeHostname, "Must be a valid hostname")}
+	// ErrFQDN is the error returned when a string is not a fully
+	// qualified domain name
+	ErrFQDN = TextErr{coded(CodeFQDN, "Must be a fully qualified domain name")}
+	// ErrDNSLabel is the error returned when a string is not a DNS
+	// label
+	ErrDNSLabel = TextErr{coded(CodeDNSLabel, "Must be a valid DNS label")}
+	// ErrPort is the error returned when a value is not a port number
+	ErrPort = TextErr{coded(CodePort, "Must be a valid port number")}
+	// ErrHostPort is the error returned when a string is not a host and
+	// port pair
+	ErrHostPort = TextErr{coded(CodeHostPort, "Must be a valid host and port")}
+)
+
+// isLabel reports whether s is a label of a hostname as defined by
+// RFC 1123: 1 to 63 letters, digits and hyphens, not starting or
+// ending with a hyphen. Upper case letters are only accepted if upper
+// is set.
+func isLabel(s string, upper bool) bool {
+	if len(s) == 0 || len(s) > 63 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || upper && c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// isHostname reports whether s is a hostname as defined by RFC 1123,
+// with at least the given number of labels.
+func isHostname(s string, labels int) bool {
+	if len(s) > 253 {
+		return false
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) < labels {
+		return false
+	}
+	for _, p := range parts {
+		if !isLabel(p, true) {
+			return false
+		}
+	}
+	return true
+}
+
+// hostname tests whether a string is a hostname as defined by RFC 1123.
+var hostname = stringRule(func(s, param string) error {
+	if !isHostname(s, 1) {
+		return ErrHostname
+	}
+	return nil
+})
+
+// fqdn tests whether a string is a fully qualified domain name, a
+// hostname of at least two labels with a top-level domain that is not
+// numeric. A trailing dot is accepted.
+var fqdn = stringRule(func(s, param string) error {
+	s = strings.TrimSuffix(s, ".")
+	if !isHostname(s, 2) || isDigits(s[strings.LastIndex(s, ".")+1:]) {
+		return ErrFQDN
+	}
+	return nil
+})
+
+// dnsLabel tests whether a string is a lower case DNS label as defined
+// by RFC 1123, as used for the names of Kubernetes resources.
+var dnsLabel = stringRule(func(s, param string) error {
+	if !isLabel(s, false) {
+		return ErrDNSLabel
+	}
+	return nil
+})
+
+// isPort reports whether p is a port number, between 1 and 65535, or
+// 0 if allowAny is set.
+func isPort(p int64, allowAny bool) bool {
+	return p >= 1 && p <= 65535 || allowAny && p == 0
+}
+
+// portParam returns whether the parameter of port and hostport allows
+// port 0, standing for any port.
+func portParam(param string) (bool, error) {
+	switch param {
+	case "":
+		return false, nil
+	case "any":
+		return true, nil
+	}
+	return false, ErrBadParameter
+}
+
+// port tests whether an integer, or a string holding one, is a TCP or
+// UDP port number between 1 and 65535. Port 0 is also accepted when
+// the parameter is any.
+func port(v interface{}, param string) error {
+	allowAny, err := portParam(param)
+	if err != nil {
+		return err
+	}
+	if isNil(v) {
+		return nil
+	}
+	var p int64
+	st := reflect.ValueOf(v)
+	switch st.Kind() {
+	case reflect.String:
+		if st.Len() == 0 {
+			return nil
+		}
+		var perr error
+		if p, perr = strconv.ParseInt(st.String(), 10, 32); perr != nil {
+			return ErrPort
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p = st.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if st.Uint() > 65535 {
+			return ErrPort
+		}
+		p = int64(st.Uint())
+	default:
+		return ErrUnsupported
+	}
+	if !isPort(p, allowAny) {
+		return ErrPort
+	}
+	return nil
+}
+
+// hostPort tests whether a string is a host and port pair, as split by
+// net.SplitHostPort, whose host is a hostname or an IP address and port
+// is a port number as for port, e.g. example.com:443 or [::1]:8080.
+var hostPort = stringRule(func(s, param string) error {
+	allowAny, err := portParam(param)
+	if err != nil {
+		return err
+	}
+	host, ps, err := net.SplitHostPort(s)
+	if err != nil {
+		return ErrHostPort
+	}
+	p, err := strconv.ParseInt(ps, 10, 32)
+	if err != nil || !isPort(p, allowAny) {
+		return ErrHostPort
+	}
+	if net.ParseIP(host) == nil && !isHostname(host, 1) {
+		return ErrHostPort
+	}
+	return nil
+})
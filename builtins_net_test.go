@@ -0,0 +1,81 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"strings"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestHostname(c *C) {
+	for _, h := range []string{"localhost", "api.example.com", "API-1.Example.com", "1and1.com", "10.0.0.1"} {
+		c.Assert(validator.Valid(h, "hostname"), IsNil, Commentf(h))
+	}
+	for _, h := range []string{"-api.example.com", "api-.example.com", "api..example.com", "api.example.com.", "under_score.com", strings.Repeat("a", 64) + ".com"} {
+		c.Assert(validator.Valid(h, "hostname"), HasError, validator.ErrHostname, Commentf(h))
+	}
+	c.Assert(validator.Valid(strings.Repeat("a.", 127)+"a", "hostname"), HasError, validator.ErrHostname)
+}
+
+func (ms *MySuite) TestFQDN(c *C) {
+	for _, h := range []string{"example.com", "api.example.com.", "xn--bcher-kva.example"} {
+		c.Assert(validator.Valid(h, "fqdn"), IsNil, Commentf(h))
+	}
+	for _, h := range []string{"localhost", "10.0.0.1", "example.com..", ".com"} {
+		c.Assert(validator.Valid(h, "fqdn"), HasError, validator.ErrFQDN, Commentf(h))
+	}
+}
+
+func (ms *MySuite) TestDNSLabel(c *C) {
+	for _, l := range []string{"my-app", "a", "app1", strings.Repeat("a", 63)} {
+		c.Assert(validator.Valid(l, "dns_label"), IsNil, Commentf(l))
+	}
+	for _, l := range []string{"My-App", "my.app", "-app", "app-", strings.Repeat("a", 64)} {
+		c.Assert(validator.Valid(l, "dns_label"), HasError, validator.ErrDNSLabel, Commentf(l))
+	}
+}
+
+func (ms *MySuite) TestPort(c *C) {
+	type listener struct {
+		Port   int    `validate:"port"`
+		Admin  string `validate:"port"`
+		Debug  uint16 `validate:"port=any"`
+		Remote string `validate:"hostport"`
+	}
+	c.Assert(validator.Validate(listener{443, "8080", 0, "example.com:443"}), IsNil)
+	c.Assert(validator.Validate(listener{65535, "", 0, "[::1]:8080"}), IsNil)
+	c.Assert(validator.Valid("10.0.0.1:5432", "hostport"), IsNil)
+	c.Assert(validator.Valid("example.com:0", "hostport=any"), IsNil)
+
+	errs, ok := validator.Validate(listener{0, "http", 0, "example.com"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Port"], HasError, validator.ErrPort)
+	c.Assert(errs["Admin"], HasError, validator.ErrPort)
+	c.Assert(errs["Remote"], HasError, validator.ErrHostPort)
+
+	for _, v := range []interface{}{65536, -1, "99999", uint64(1 << 40)} {
+		c.Assert(validator.Valid(v, "port"), HasError, validator.ErrPort, Commentf("%v", v))
+	}
+	for _, s := range []string{":8080", "example.com:0", "example.com:http", "exa_mple.com:80", "::1:80"} {
+		c.Assert(validator.Valid(s, "hostport"), HasError, validator.ErrHostPort, Commentf(s))
+	}
+	c.Assert(validator.Valid(80, "port=all"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(80.0, "port"), HasError, validator.ErrUnsupported)
+}
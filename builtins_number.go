@@ -0,0 +1,287 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Ratter is implemented by decimal types, such as shopspring's
+// decimal.Decimal, to be compared by min, max, gt and lt.
+type Ratter interface {
+	Rat() *big.Rat
+}
+
+// asRat returns v as a rational number if it is a big number, i.e. a
+// big.Int, big.Rat, big.Float or Ratter, along with its text.
+func asRat(v interface{}) (*big.Rat, string, bool) {
+	switch x := v.(type) {
+	case big.Int:
+		return new(big.Rat).SetInt(&x), x.String(), true
+	case big.Rat:
+		return &x, x.RatString(), true
+	case big.Float:
+		if x.IsInf() {
+			return nil, "", false
+		}
+		r, _ := x.Rat(nil)
+		return r, x.Text('g', -1), true
+	case Ratter:
+		r := x.Rat()
+		if r == nil {
+			return nil, "", false
+		}
+		return r, bigString(v, r), true
+	}
+	return nil, "", false
+}
+
+// asNumber returns v as a rational number if it is a number, along
+// with its text.
+func asNumber(v interface{}) (*big.Rat, string, bool) {
+	if r, s, ok := asRat(v); ok {
+		return r, s, true
+	}
+	if d, ok := v.(time.Duration); ok {
+		return new(big.Rat).SetInt64(int64(d)), d.String(), true
+	}
+	st := reflect.ValueOf(v)
+	switch st.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		r := new(big.Rat).SetInt64(st.Int())
+		return r, r.RatString(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		r := new(big.Rat).SetInt(new(big.Int).SetUint64(st.Uint()))
+		return r, r.RatString(), true
+	case reflect.Float32, reflect.Float64:
+		r := new(big.Rat)
+		if r.SetFloat64(st.Float()) == nil {
+			return nil, "", false
+		}
+		return r, big.NewFloat(st.Float()).Text('g', -1), true
+	}
+	return nil, "", false
+}
+
+// bigString returns the text of the big number v of value r.
+func bigString(v interface{}, r *big.Rat) string {
+	if s, ok := v.(interface {
+		String() string
+	}); ok {
+		return s.String()
+	}
+	return r.RatString()
+}
+
+// asBigParam returns the parameter as a rational number, given as an
+// integer, a decimal or a fraction.
+func asBigParam(param string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(param)
+	if !ok {
+		return nil, ErrBadParameter
+	}
+	return r, nil
+}
+
+// minBig tests whether the big number r is larger or equal to param.
+func minBig(r *big.Rat, text, param string) error {
+	p, err := asBigParam(param)
+	if err != nil {
+		return err
+	}
+	if r.Cmp(p) < 0 {
+		return ErrMinNumber(param, text)
+	}
+	return nil
+}
+
+// maxBig tests whether the big number r is lesser or equal to param.
+func maxBig(r *big.Rat, text, param string) error {
+	p, err := asBigParam(param)
+	if err != nil {
+		return err
+	}
+	if r.Cmp(p) > 0 {
+		return ErrMaxNumber(param, text)
+	}
+	return nil
+}
+
+// gt tests whether a number is greater than the parameter. Numbers may
+// be big numbers or decimals, compared exactly.
+func gt(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	c, text, err := compareNumber(v, param)
+	if err != nil {
+		return err
+	}
+	if c <= 0 {
+		return ErrGreaterThan(param, text)
+	}
+	return nil
+}
+
+// lt tests whether a number is lesser than the parameter. Numbers may
+// be big numbers or decimals, compared exactly.
+func lt(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	c, text, err := compareNumber(v, param)
+	if err != nil {
+		return err
+	}
+	if c >= 0 {
+		return ErrLessThan(param, text)
+	}
+	return nil
+}
+
+// gte tests whether a number is greater than or equal to the parameter.
+// Unlike min, it never applies to lengths.
+func gte(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	c, text, err := compareNumber(v, param)
+	if err != nil {
+		return err
+	}
+	if c < 0 {
+		return ErrMinNumber(param, text)
+	}
+	return nil
+}
+
+// lte tests whether a number is lesser than or equal to the parameter.
+// Unlike max, it never applies to lengths.
+func lte(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	c, text, err := compareNumber(v, param)
+	if err != nil {
+		return err
+	}
+	if c > 0 {
+		return ErrMaxNumber(param, text)
+	}
+	return nil
+}
+
+// eq tests whether a number is equal to the parameter.
+func eq(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	c, text, err := compareNumber(v, param)
+	if err != nil {
+		return err
+	}
+	if c != 0 {
+		return ErrEqual(param, text)
+	}
+	return nil
+}
+
+// ne tests whether a number is different from the parameter.
+func ne(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	c, _, err := compareNumber(v, param)
+	if err != nil {
+		return err
+	}
+	if c == 0 {
+		return ErrNotEqual(param)
+	}
+	return nil
+}
+
+// compareNumber compares the number v with the parameter, returning
+// -1, 0 or +1 as v is lesser, equal or greater, along with its text.
+// The parameter of durations may be given as a duration string.
+func compareNumber(v interface{}, param string) (int, string, error) {
+	r, text, ok := asNumber(v)
+	if !ok {
+		return 0, "", ErrUnsupported
+	}
+	p, err := numberParam(v, param)
+	if err != nil {
+		return 0, "", err
+	}
+	return r.Cmp(p), text, nil
+}
+
+// numberParam returns the parameter of a rule applied to the number v
+// as a rational number. The parameter of durations may be given as a
+// duration string.
+func numberParam(v interface{}, param string) (*big.Rat, error) {
+	if _, ok := v.(time.Duration); ok {
+		d, err := asDuration(param)
+		if err != nil {
+			return nil, ErrBadParameter
+		}
+		return new(big.Rat).SetInt64(int64(d)), nil
+	}
+	return asBigParam(param)
+}
+
+// multipleOf tests whether a number is a multiple of the parameter,
+// e.g. multipleof=0.05. Floats are taken as the shortest decimal
+// representing them, so that 0.15 is a multiple of 0.05 although
+// neither is exactly representable in binary.
+func multipleOf(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	var r *big.Rat
+	st := reflect.ValueOf(v)
+	switch st.Kind() {
+	case reflect.Float32, reflect.Float64:
+		var ok bool
+		if r, ok = floatRat(st.Float(), st.Type().Bits()); !ok {
+			// NaN and infinities are multiples of nothing
+			return ErrMultipleOf(param)
+		}
+	default:
+		var ok bool
+		if r, _, ok = asNumber(v); !ok {
+			return ErrUnsupported
+		}
+	}
+	p, err := numberParam(v, param)
+	if err != nil || p.Sign() == 0 {
+		return ErrBadParameter
+	}
+	if !r.Quo(r, p).IsInt() {
+		return ErrMultipleOf(param)
+	}
+	return nil
+}
+
+// floatRat returns the shortest decimal representing the float f of the
+// given size in bits as a rational number, false for NaN and infinities.
+func floatRat(f float64, bits int) (*big.Rat, bool) {
+	return new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, bits))
+}
@@ -0,0 +1,139 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type decimal struct {
+	value *big.Rat
+}
+
+func (d decimal) Rat() *big.Rat {
+	return d.value
+}
+
+func (d decimal) String() string {
+	return d.value.FloatString(2)
+}
+
+type invoice struct {
+	Total    *big.Int   `validate:"nonzero,min=1,max=1000000000000000000000"`
+	Rate     *big.Rat   `validate:"gt=0,lt=1"`
+	Ratio    *big.Float `validate:"max=0.5"`
+	Discount decimal    `validate:"min=0,max=99.99"`
+	Count    int        `validate:"gt=0,lt=10"`
+	Weight   float64    `validate:"gt=0.5"`
+}
+
+func (ms *MySuite) TestBigNumbers(c *C) {
+	total, _ := new(big.Int).SetString("123456789012345678901", 10)
+	c.Assert(validator.Validate(invoice{
+		Total:    total,
+		Rate:     big.NewRat(1, 3),
+		Ratio:    big.NewFloat(0.25),
+		Discount: decimal{big.NewRat(1050, 100)},
+		Count:    3,
+		Weight:   0.75,
+	}), IsNil)
+
+	total, _ = new(big.Int).SetString("1000000000000000000001", 10)
+	errs, ok := validator.Validate(invoice{
+		Total:    total,
+		Rate:     big.NewRat(1, 1),
+		Ratio:    big.NewFloat(0.75),
+		Discount: decimal{big.NewRat(10000, 100)},
+		Count:    10,
+		Weight:   0.5,
+	}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Total"], HasError, validator.ErrMaxNumber("1000000000000000000000", "1000000000000000000001"))
+	c.Assert(errs["Rate"], HasError, validator.ErrLessThan("1", "1"))
+	c.Assert(errs["Ratio"], HasError, validator.ErrMaxNumber("0.5", "0.75"))
+	c.Assert(errs["Discount"], HasError, validator.ErrMaxNumber("99.99", "100.00"))
+	c.Assert(errs["Count"], HasError, validator.ErrLessThan("10", "10"))
+	c.Assert(errs["Weight"], HasError, validator.ErrGreaterThan("0.5", "0.5"))
+
+	c.Assert(validator.Valid(new(big.Int), "nonzero"), HasError, validator.ErrZeroValueNumber)
+	c.Assert(validator.Valid(big.NewInt(-3), "gt=-3"), HasError, validator.ErrGreaterThan("-3", "-3"))
+	c.Assert(validator.Valid(big.NewInt(-3), "gt=-7/2"), IsNil)
+	c.Assert(validator.Valid(big.NewInt(3), "min=x"), HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestGtLt(c *C) {
+	c.Assert(validator.Valid(uint8(3), "gt=2,lt=4"), IsNil)
+	c.Assert(validator.Valid(int64(-1), "gt=0"), HasError, validator.ErrGreaterThan("0", "-1"))
+	c.Assert(validator.Valid(time.Second, "gt=500ms,lt=1m"), IsNil)
+	c.Assert(validator.Valid(time.Second, "lt=1s"), HasError, validator.ErrLessThan("1s", "1s"))
+	c.Assert(validator.Valid("abc", "gt=1"), HasError, validator.ErrUnsupported)
+	c.Assert(validator.Valid(3, "lt=abc"), HasError, validator.ErrBadParameter)
+	var p *int
+	c.Assert(validator.Valid(p, "gt=1"), IsNil)
+}
+
+func (ms *MySuite) TestMultipleOf(c *C) {
+	c.Assert(validator.Valid(12, "multipleof=6"), IsNil)
+	c.Assert(validator.Valid(uint8(0), "multipleof=6"), IsNil)
+	c.Assert(validator.Valid(-18, "multipleof=6"), IsNil)
+	c.Assert(validator.Valid(13, "multipleof=6"), HasError, validator.ErrMultipleOf("6"))
+
+	c.Assert(validator.Valid(0.15, "multipleof=0.05"), IsNil)
+	c.Assert(validator.Valid(19.95, "multipleof=0.05"), IsNil)
+	c.Assert(validator.Valid(float32(0.3), "multipleof=0.1"), IsNil)
+	c.Assert(validator.Valid(1.5, "multipleof=1/2"), IsNil)
+	c.Assert(validator.Valid(0.16, "multipleof=0.05"), HasError, validator.ErrMultipleOf("0.05"))
+	c.Assert(validator.Valid(math.Inf(1), "multipleof=0.05"), HasError, validator.ErrMultipleOf("0.05"))
+
+	c.Assert(validator.Valid(*big.NewInt(100), "multipleof=25"), IsNil)
+	c.Assert(validator.Valid(90*time.Minute, "multipleof=15m"), IsNil)
+	c.Assert(validator.Valid(100*time.Minute, "multipleof=15m"), HasError, validator.ErrMultipleOf("15m"))
+
+	c.Assert(validator.Valid(12, "multipleof=0"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(12, "multipleof=x"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid("12", "multipleof=6"), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestComparisons(c *C) {
+	type product struct {
+		Price    float64 `validate:"gt=0"`
+		Discount float64 `validate:"gte=0,lte=1"`
+		Stock    int     `validate:"ne=0"`
+		Version  uint    `validate:"eq=2"`
+		Name     string  `validate:"gte=3"`
+	}
+	errs, ok := validator.Validate(product{0, 1.5, 0, 1, ""}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Price"], HasError, validator.ErrGreaterThan("0", "0"))
+	c.Assert(errs["Discount"], HasError, validator.ErrMaxNumber("1", "1.5"))
+	c.Assert(errs["Stock"], HasError, validator.ErrNotEqual("0"))
+	c.Assert(errs["Version"], HasError, validator.ErrEqual("2", "1"))
+	c.Assert(errs["Name"], HasError, validator.ErrUnsupported)
+
+	c.Assert(validator.Valid(0.0, "gte=0,lte=1"), IsNil)
+	c.Assert(validator.Valid(1, "gte=0,lte=1"), IsNil)
+	c.Assert(validator.Valid(-0.5, "gte=0"), HasError, validator.ErrMinNumber("0", "-0.5"))
+	c.Assert(validator.Valid(*big.NewRat(1, 3), "eq=1/3"), IsNil)
+	c.Assert(validator.Valid(time.Minute, "eq=60s,ne=0s,gte=1m,lte=1m"), IsNil)
+	c.Assert(validator.Valid(2, "eq=x"), HasError, validator.ErrBadParameter)
+}
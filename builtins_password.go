@@ -0,0 +1,151 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrPasswordClass is the error returned when a password does not
+	// contain a character of the given class
+	ErrPasswordClass = func(class string) TextErr {
+		return TextErr{codef(CodePassword, "Must contain at least one %s", class)}
+	}
+	// ErrPasswordEntropy is the error returned when the estimated
+	// entropy of a password is below the given number of bits
+	ErrPasswordEntropy = func(bits int64) TextErr {
+		return TextErr{codef(CodePassword, "Must be harder to guess, with at least %d bits of entropy", bits)}
+	}
+)
+
+// passwordClass is a class of characters passwords may be required
+// to contain.
+type passwordClass struct {
+	name string
+	in   func(r rune) bool
+	// size is the number of characters of the class, used to estimate
+	// the entropy of passwords.
+	size int
+}
+
+var passwordClasses = map[string]passwordClass{
+	"lower":  {"lower case letter", func(r rune) bool { return r >= 'a' && r <= 'z' }, 26},
+	"upper":  {"upper case letter", func(r rune) bool { return r >= 'A' && r <= 'Z' }, 26},
+	"digit":  {"digit", isASCIIDigit, 10},
+	"symbol": {"symbol", isSymbol, 33},
+}
+
+// isSymbol reports whether r is a printable ASCII character other than
+// a letter or a digit, including space.
+func isSymbol(r rune) bool {
+	return r >= ' ' && r <= '~' && !isASCIILetter(r) && !isASCIIDigit(r)
+}
+
+// password tests whether a string is a password strong enough for the
+// options given as parameter, separated by semicolons: min:n and max:n
+// bound its number of characters, 8 at least by default, lower, upper,
+// digit and symbol require a character of each class and entropy:n
+// requires an estimated entropy of n bits, e.g.
+// password=min:12;upper;digit;symbol.
+func password(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	st := reflect.ValueOf(v)
+	if st.Kind() != reflect.String {
+		return ErrUnsupported
+	}
+	s := st.String()
+
+	var min, max, bits int64 = 8, -1, -1
+	var classes []passwordClass
+	for _, opt := range strings.Split(param, ";") {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+		kv := strings.SplitN(opt, ":", 2)
+		if c, ok := passwordClasses[kv[0]]; ok && len(kv) == 1 {
+			classes = append(classes, c)
+			continue
+		}
+		if len(kv) != 2 {
+			return ErrBadParameter
+		}
+		n, err := asInt(kv[1])
+		if err != nil {
+			return ErrBadParameter
+		}
+		switch kv[0] {
+		case "min":
+			min = n
+		case "max":
+			max = n
+		case "entropy":
+			bits = n
+		default:
+			return ErrBadParameter
+		}
+	}
+
+	n := utf8.RuneCountInString(s)
+	if int64(n) < min {
+		return ErrMinString(min, n)
+	}
+	if max >= 0 && int64(n) > max {
+		return ErrMaxString(max, n)
+	}
+	for _, c := range classes {
+		if strings.IndexFunc(s, c.in) < 0 {
+			return ErrPasswordClass(c.name)
+		}
+	}
+	if bits >= 0 && passwordEntropy(s) < float64(bits) {
+		return ErrPasswordEntropy(bits)
+	}
+	return nil
+}
+
+// passwordEntropy estimates the entropy of a password in bits, as the
+// entropy of a random string of the same length drawn from the classes
+// of characters it contains. Repeated characters are only counted
+// once, so that aaaaaaaa is not deemed strong.
+func passwordEntropy(s string) float64 {
+	pool := 0
+	for _, name := range []string{"lower", "upper", "digit", "symbol"} {
+		c := passwordClasses[name]
+		if strings.IndexFunc(s, c.in) >= 0 {
+			pool += c.size
+		}
+	}
+	if strings.IndexFunc(s, func(r rune) bool { return r > unicode.MaxASCII }) >= 0 {
+		pool += 100
+	}
+	seen := map[rune]bool{}
+	for _, r := range s {
+		seen[r] = true
+	}
+	if pool == 0 {
+		return 0
+	}
+	return float64(len(seen)) * math.Log2(float64(pool))
+}
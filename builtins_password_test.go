@@ -0,0 +1,46 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestPassword(c *C) {
+	c.Assert(validator.Valid("12345678", "password"), IsNil)
+	c.Assert(validator.Valid("1234567", "password"), HasError, validator.ErrMinString(8, 7))
+
+	rule := "password=min:12;upper;digit;symbol"
+	c.Assert(validator.Valid("Correct-horse-1", rule), IsNil)
+	c.Assert(validator.Valid("Short-1", rule), HasError, validator.ErrMinString(12, 7))
+	c.Assert(validator.Valid("correct-horse-1", rule), HasError, validator.ErrPasswordClass("upper case letter"))
+	c.Assert(validator.Valid("Correct-horse-", rule), HasError, validator.ErrPasswordClass("digit"))
+	c.Assert(validator.Valid("CorrectHorse1", rule), HasError, validator.ErrPasswordClass("symbol"))
+	c.Assert(validator.Valid("Correct horse 1", "password=lower;symbol"), IsNil)
+	c.Assert(validator.Valid("abcdefghijk", "password=max:10"), HasError, validator.ErrMaxString(10, 11))
+	c.Assert(validator.Valid("", "password=min:0;lower"), HasError, validator.ErrPasswordClass("lower case letter"))
+
+	c.Assert(validator.Valid("Tr0ub4dor&3xyz", "password=entropy:60"), IsNil)
+	c.Assert(validator.Valid("aaaaaaaaaaaaaaaa", "password=entropy:60"), HasError, validator.ErrPasswordEntropy(60))
+	c.Assert(validator.Valid("password", "password=entropy:60"), HasError, validator.ErrPasswordEntropy(60))
+
+	c.Assert(validator.Valid("x", "password=special"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid("x", "password=min:x"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(12345678, "password"), HasError, validator.ErrUnsupported)
+}
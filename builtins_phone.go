@@ -0,0 +1,33 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+// ErrE164 is the error returned when a string is not a phone number
+// in E.164 format
+var ErrE164 = TextErr{coded(CodeE164, "Must be a phone number in E.164 format")}
+
+// e164 tests whether a string is an international phone number in
+// E.164 format, a + followed by the country code and the number, 7 to
+// 15 digits in total without any separator. It only checks the format,
+// numbers can be checked against the numbering plans of each country
+// by replacing it, e.g. with a function using libphonenumber.
+var e164 = stringRule(func(s, param string) error {
+	if len(s) < 8 || len(s) > 16 || s[0] != '+' || s[1] == '0' || !isDigits(s[1:]) {
+		return ErrE164
+	}
+	return nil
+})
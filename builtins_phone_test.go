@@ -0,0 +1,48 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"errors"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestE164(c *C) {
+	for _, n := range []string{"+6494461709", "+14155552671", "+6834002", "+123456789012345"} {
+		c.Assert(validator.Valid(n, "e164"), IsNil, Commentf(n))
+	}
+	for _, n := range []string{"6494461709", "+64 9 446 1709", "+0494461709", "+683400", "+1234567890123456", "+"} {
+		c.Assert(validator.Valid(n, "e164"), HasError, validator.ErrE164, Commentf(n))
+	}
+	c.Assert(validator.Valid("", "e164"), IsNil)
+}
+
+func (ms *MySuite) TestE164Override(c *C) {
+	errNotMobile := errors.New("not a mobile number")
+	v := validator.New()
+	v.SetValidationFunc("e164", func(val interface{}, param string) error {
+		if s, _ := val.(string); s != "+6421000000" {
+			return errNotMobile
+		}
+		return nil
+	})
+	c.Assert(v.Valid("+6421000000", "e164"), IsNil)
+	c.Assert(v.Valid("+6494461709", "e164"), HasError, errNotMobile)
+}
@@ -0,0 +1,80 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type sqlRow struct {
+	Name   sql.NullString   `validate:"nonzero,max=5"`
+	Nick   sql.NullString   `validate:"max=5"`
+	Age    sql.NullInt64    `validate:"min=18"`
+	Score  *sql.NullFloat64 `validate:"max=10"`
+	Active sql.NullBool     `validate:"nonzero"`
+}
+
+func (ms *MySuite) TestDriverValuer(c *C) {
+	row := sqlRow{
+		Name:   sql.NullString{String: "Joe", Valid: true},
+		Age:    sql.NullInt64{Int64: 20, Valid: true},
+		Score:  &sql.NullFloat64{Float64: 5, Valid: true},
+		Active: sql.NullBool{Bool: true, Valid: true},
+	}
+	c.Assert(validator.Validate(row), IsNil)
+
+	row = sqlRow{
+		Nick:  sql.NullString{String: "Joe the third", Valid: true},
+		Age:   sql.NullInt64{Int64: 12, Valid: true},
+		Score: &sql.NullFloat64{},
+	}
+	errs, ok := validator.Validate(row).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["Name"], HasLen, 1)
+	c.Assert(errs["Nick"], HasError, validator.ErrMaxString(5, 13))
+	c.Assert(errs["Age"], HasError, validator.ErrMinInt(18, 12))
+	c.Assert(errs["Active"], HasError, validator.ErrZeroValue)
+	c.Assert(errs, HasLen, 4)
+
+	c.Assert(validator.Valid(nullTime{time.Now(), true}, "before=now+1h"), IsNil)
+	c.Assert(validator.Valid(nullTime{}, "nonzero"), HasError, validator.ErrZeroValue)
+	c.Assert(validator.Valid(&nullTime{}, "nonzero"), HasError, validator.ErrZeroValue)
+}
+
+type nullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (n nullTime) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Time, nil
+}
+
+func (ms *MySuite) TestDriverValuerNull(c *C) {
+	c.Assert(validator.Valid(sql.NullString{}, "regexp=^a,datetime=date,min=1"), IsNil)
+	c.Assert(validator.Valid(nullTime{}, "before=now,after=now,between=now now"), IsNil)
+}
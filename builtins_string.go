@@ -0,0 +1,316 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrCharacters is the error returned when a string holds characters
+	// other than those of the given class
+	ErrCharacters = func(class string) TextErr {
+		return TextErr{codef(CodeCharacters, "Must only contain %s", class)}
+	}
+	// ErrLowercase is the error returned when a string holds upper
+	// case characters
+	ErrLowercase = TextErr{coded(CodeLowercase, "Must be lower case")}
+	// ErrUppercase is the error returned when a string holds lower
+	// case characters
+	ErrUppercase = TextErr{coded(CodeUppercase, "Must be upper case")}
+	// ErrStartsWith is the error returned when a string does not start
+	// with the given prefix
+	ErrStartsWith = func(prefix string) TextErr {
+		return TextErr{codef(CodeStartsWith, "Must start with %q", prefix)}
+	}
+	// ErrEndsWith is the error returned when a string does not end with
+	// the given suffix
+	ErrEndsWith = func(suffix string) TextErr {
+		return TextErr{codef(CodeEndsWith, "Must end with %q", suffix)}
+	}
+	// ErrContains is the error returned when a string does not contain
+	// the given substring
+	ErrContains = func(substr string) TextErr {
+		return TextErr{codef(CodeContains, "Must contain %q", substr)}
+	}
+	// ErrExcludes is the error returned when a string contains the given
+	// substring
+	ErrExcludes = func(substr string) TextErr {
+		return TextErr{codef(CodeExcludes, "Must not contain %q", substr)}
+	}
+	// ErrExcludesAll is the error returned when a string contains any
+	// of the given characters
+	ErrExcludesAll = func(chars string) TextErr {
+		return TextErr{codef(CodeExcludesAll, "Must not contain any of %q", chars)}
+	}
+	// ErrNumeric is the error returned when a string is not a number
+	ErrNumeric = TextErr{coded(CodeNumeric, "Must be a number")}
+	// ErrDecimals is the error returned when a number has more decimal
+	// places than allowed
+	ErrDecimals = func(places int64) TextErr {
+		return TextErr{codef(CodeDecimals, "Must have at most %v", plural(places, "decimal place", "decimal places"))}
+	}
+	// ErrBoolean is the error returned when a string is not a boolean
+	ErrBoolean = TextErr{coded(CodeBoolean, "Must be a boolean")}
+	// ErrInvalidUTF8 is the error returned when a string is not valid
+	// UTF-8
+	ErrInvalidUTF8 = TextErr{coded(CodeUTF8, "Must be valid UTF-8")}
+	// ErrControl is the error returned when a string holds control
+	// characters
+	ErrControl = TextErr{coded(CodeControl, "Must not contain control characters")}
+	// ErrHTML is the error returned when a string contains HTML markup
+	ErrHTML = TextErr{coded(CodeHTML, "Must not contain HTML")}
+	// ErrMinBytes is the error returned when a string is shorter than
+	// the given number of bytes
+	ErrMinBytes = func(min int64, actual int) TextErr {
+		return TextErr{codef(CodeMinBytes, "Must be at least %v long, only had %v", plural(min, "byte", "bytes"), plural(int64(actual), "byte", "bytes"))}
+	}
+	// ErrMaxBytes is the error returned when a string is longer than
+	// the given number of bytes
+	ErrMaxBytes = func(max int64, actual int) TextErr {
+		return TextErr{codef(CodeMaxBytes, "Must not have more than %v, had %v", plural(max, "byte", "bytes"), plural(int64(actual), "byte", "bytes"))}
+	}
+	// ErrLenBytes is the error returned when a string is not of the
+	// given number of bytes
+	ErrLenBytes = func(len int64, actual int) TextErr {
+		return TextErr{codef(CodeLenBytes, "Must have exactly %v, was %v", plural(len, "byte", "bytes"), plural(int64(actual), "byte", "bytes"))}
+	}
+)
+
+// charsRule returns a validation function testing whether all the
+// characters of a string belong to class, according to in.
+func charsRule(class string, in func(r rune) bool) ValidationFunc {
+	return stringRule(func(s, param string) error {
+		for _, r := range s {
+			if !in(r) {
+				return ErrCharacters(class)
+			}
+		}
+		return nil
+	})
+}
+
+func isASCIILetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+var (
+	alpha    = charsRule("letters", isASCIILetter)
+	alphanum = charsRule("letters and digits", func(r rune) bool {
+		return isASCIILetter(r) || isASCIIDigit(r)
+	})
+	alphaUnicode    = charsRule("letters", unicode.IsLetter)
+	alphanumUnicode = charsRule("letters and digits", func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	})
+	ascii = charsRule("ASCII characters", func(r rune) bool {
+		return r <= unicode.MaxASCII
+	})
+	printASCII = charsRule("printable ASCII characters", func(r rune) bool {
+		return r >= ' ' && r <= '~'
+	})
+)
+
+// lowercase tests whether a string holds no upper or title case
+// characters. Characters without case, such as digits, are accepted.
+var lowercase = stringRule(func(s, param string) error {
+	for _, r := range s {
+		if unicode.IsUpper(r) || unicode.IsTitle(r) {
+			return ErrLowercase
+		}
+	}
+	return nil
+})
+
+// uppercase tests whether a string holds no lower or title case
+// characters. Characters without case, such as digits, are accepted.
+var uppercase = stringRule(func(s, param string) error {
+	for _, r := range s {
+		if unicode.IsLower(r) || unicode.IsTitle(r) {
+			return ErrUppercase
+		}
+	}
+	return nil
+})
+
+// substringRule returns a validation function testing a string against
+// the non-empty parameter with match, returning the error made by fail
+// when it does not match.
+func substringRule(match func(s, param string) bool, fail func(string) TextErr) ValidationFunc {
+	return stringRule(func(s, param string) error {
+		if param == "" {
+			return ErrBadParameter
+		}
+		if !match(s, param) {
+			return fail(param)
+		}
+		return nil
+	})
+}
+
+var (
+	startsWith = substringRule(strings.HasPrefix, ErrStartsWith)
+	endsWith   = substringRule(strings.HasSuffix, ErrEndsWith)
+	contains   = substringRule(strings.Contains, ErrContains)
+	excludes   = substringRule(func(s, param string) bool {
+		return !strings.Contains(s, param)
+	}, ErrExcludes)
+	excludesAll = substringRule(func(s, param string) bool {
+		return !strings.ContainsAny(s, param)
+	}, ErrExcludesAll)
+)
+
+// numericPattern is the pattern of decimal numbers, capturing their
+// fractional part and exponent.
+var numericPattern = regexp.MustCompile(`^[+-]?(?:\d+(?:\.(\d*))?|\.(\d+))(?:[eE]([+-]?\d+))?$`)
+
+// numeric tests whether a string is a decimal number, optionally with
+// at most the number of decimal places given as parameter.
+var numeric = stringRule(func(s, param string) error {
+	var places int64 = -1
+	if param != "" {
+		p, err := asInt(param)
+		if err != nil || p < 0 {
+			return ErrBadParameter
+		}
+		places = p
+	}
+	m := numericPattern.FindStringSubmatch(s)
+	if m == nil {
+		return ErrNumeric
+	}
+	if places < 0 {
+		return nil
+	}
+	frac := strings.TrimRight(m[1]+m[2], "0")
+	decimals := int64(len(frac))
+	if m[3] != "" {
+		exp, err := strconv.ParseInt(m[3], 10, 64)
+		if err != nil {
+			return ErrNumeric
+		}
+		decimals -= exp
+	}
+	if decimals > places {
+		return ErrDecimals(places)
+	}
+	return nil
+})
+
+// boolean tests whether a string is a boolean as parsed by
+// strconv.ParseBool, e.g. true, false, 1 or 0.
+var boolean = stringRule(func(s, param string) error {
+	if _, err := strconv.ParseBool(s); err != nil {
+		return ErrBoolean
+	}
+	return nil
+})
+
+// validUTF8 tests whether a string or a byte slice is valid UTF-8.
+func validUTF8(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	st := reflect.ValueOf(v)
+	var ok bool
+	switch {
+	case st.Kind() == reflect.String:
+		ok = utf8.ValidString(st.String())
+	case st.Kind() == reflect.Slice && st.Type().Elem().Kind() == reflect.Uint8:
+		ok = utf8.Valid(st.Bytes())
+	default:
+		return ErrUnsupported
+	}
+	if !ok {
+		return ErrInvalidUTF8
+	}
+	return nil
+}
+
+// noControl tests whether a string holds no control characters, such
+// as NUL or escape, other than tabs and line breaks.
+var noControl = stringRule(func(s, param string) error {
+	for _, r := range s {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			return ErrControl
+		}
+	}
+	return nil
+})
+
+// bytesRule returns a validation function comparing the number of
+// bytes of a string, as encoded in UTF-8, to the parameter with check.
+// Unlike the format rules, it also applies to empty strings.
+func bytesRule(check func(n int64, actual int) error) ValidationFunc {
+	return func(v interface{}, param string) error {
+		if isNil(v) {
+			return nil
+		}
+		st := reflect.ValueOf(v)
+		if st.Kind() != reflect.String {
+			return ErrUnsupported
+		}
+		p, err := asInt(param)
+		if err != nil {
+			return ErrBadParameter
+		}
+		return check(p, st.Len())
+	}
+}
+
+var (
+	minBytes = bytesRule(func(min int64, actual int) error {
+		if int64(actual) < min {
+			return ErrMinBytes(min, actual)
+		}
+		return nil
+	})
+	maxBytes = bytesRule(func(max int64, actual int) error {
+		if int64(actual) > max {
+			return ErrMaxBytes(max, actual)
+		}
+		return nil
+	})
+	lenBytes = bytesRule(func(len int64, actual int) error {
+		if int64(actual) != len {
+			return ErrLenBytes(len, actual)
+		}
+		return nil
+	})
+)
+
+// htmlPattern matches the start of HTML tags, closing tags, comments
+// and declarations, but not a lone < as in a < b.
+var htmlPattern = regexp.MustCompile(`<[a-zA-Z!/?]`)
+
+// noHTML tests whether a string contains no HTML markup, such as tags
+// or comments, e.g. for display names.
+var noHTML = stringRule(func(s, param string) error {
+	if htmlPattern.MatchString(s) {
+		return ErrHTML
+	}
+	return nil
+})
@@ -0,0 +1,152 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestCharacterClasses(c *C) {
+	valid := []struct{ v, rule string }{
+		{"abcXYZ", "alpha"},
+		{"abc123", "alphanum"},
+		{"Ñandú", "alphaunicode"},
+		{"Ñandú42", "alphanumunicode"},
+		{"٣٤", "alphanumunicode"},
+		{"a b\t~\x00", "ascii"},
+		{"a b~!", "printascii"},
+	}
+	for _, t := range valid {
+		c.Assert(validator.Valid(t.v, t.rule), IsNil, Commentf(t.v))
+	}
+	invalid := []struct{ v, rule, class string }{
+		{"abc1", "alpha", "letters"},
+		{"héllo", "alpha", "letters"},
+		{"abc-123", "alphanum", "letters and digits"},
+		{"Ñandú42", "alphaunicode", "letters"},
+		{"Ñandú 42", "alphanumunicode", "letters and digits"},
+		{"héllo", "ascii", "ASCII characters"},
+		{"a\tb", "printascii", "printable ASCII characters"},
+	}
+	for _, t := range invalid {
+		c.Assert(validator.Valid(t.v, t.rule), HasError, validator.ErrCharacters(t.class), Commentf(t.v))
+	}
+}
+
+func (ms *MySuite) TestCase(c *C) {
+	for _, s := range []string{"abc", "straße", "abc-123", "ǆ"} {
+		c.Assert(validator.Valid(s, "lowercase"), IsNil, Commentf(s))
+	}
+	for _, s := range []string{"aBc", "ÀB", "ǅ"} {
+		c.Assert(validator.Valid(s, "lowercase"), HasError, validator.ErrLowercase, Commentf(s))
+	}
+	for _, s := range []string{"NZD", "ÉTÉ", "A-123"} {
+		c.Assert(validator.Valid(s, "uppercase"), IsNil, Commentf(s))
+	}
+	for _, s := range []string{"Nzd", "ÉTé", "ǅ"} {
+		c.Assert(validator.Valid(s, "uppercase"), HasError, validator.ErrUppercase, Commentf(s))
+	}
+
+	type price struct {
+		Currency string `validate:"iso4217,uppercase"`
+	}
+	errs, ok := validator.Validate(price{"nzd"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Currency"], DeepEquals, validator.ErrorArray{validator.ErrUppercase})
+}
+
+func (ms *MySuite) TestSubstrings(c *C) {
+	type apiKey struct {
+		Key  string `validate:"startswith=sk_,excludesall=	<>"`
+		Host string `validate:"endswith=.example.com,excludes=..,contains=api"`
+	}
+	c.Assert(validator.Validate(apiKey{"sk_live_123", "api.example.com"}), IsNil)
+	c.Assert(validator.Validate(apiKey{}), IsNil)
+
+	errs, ok := validator.Validate(apiKey{"pk_<live>", "www..example.org"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Key"], HasError, validator.ErrStartsWith("sk_"))
+	c.Assert(errs["Key"], HasError, validator.ErrExcludesAll("\t<>"))
+	c.Assert(errs["Host"], HasError, validator.ErrEndsWith(".example.com"))
+	c.Assert(errs["Host"], HasError, validator.ErrExcludes(".."))
+	c.Assert(errs["Host"], HasError, validator.ErrContains("api"))
+
+	c.Assert(validator.Valid("sk_\t", "excludesall=\t"), HasError, validator.ErrExcludesAll("\t"))
+	c.Assert(validator.Valid("abc", "startswith="), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(42, "contains=4"), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestNumeric(c *C) {
+	for _, s := range []string{"42", "-42", "+3.14", ".5", "5.", "1e3", "1.5E-3"} {
+		c.Assert(validator.Valid(s, "numeric"), IsNil, Commentf(s))
+	}
+	for _, s := range []string{"abc", "1.2.3", "0x10", "NaN", "Inf", "1_000", "1e", "."} {
+		c.Assert(validator.Valid(s, "numeric"), HasError, validator.ErrNumeric, Commentf(s))
+	}
+	for _, s := range []string{"19.99", "19.9", "20", "19.990", "1.5e1", "1234e-2"} {
+		c.Assert(validator.Valid(s, "numeric=2"), IsNil, Commentf(s))
+	}
+	for _, s := range []string{"19.999", "1e-3", "0.001"} {
+		c.Assert(validator.Valid(s, "numeric=2"), HasError, validator.ErrDecimals(2), Commentf(s))
+	}
+	c.Assert(validator.Valid("1", "numeric=-1"), HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestBoolean(c *C) {
+	for _, s := range []string{"true", "false", "1", "0", "T", "FALSE"} {
+		c.Assert(validator.Valid(s, "boolean"), IsNil, Commentf(s))
+	}
+	for _, s := range []string{"yes", "on", "2"} {
+		c.Assert(validator.Valid(s, "boolean"), HasError, validator.ErrBoolean, Commentf(s))
+	}
+}
+
+func (ms *MySuite) TestValidUTF8(c *C) {
+	c.Assert(validator.Valid("héllo", "validutf8"), IsNil)
+	c.Assert(validator.Valid([]byte("héllo"), "validutf8"), IsNil)
+	c.Assert(validator.Valid("h\xffllo", "validutf8"), HasError, validator.ErrInvalidUTF8)
+	c.Assert(validator.Valid([]byte("h\xc3"), "validutf8"), HasError, validator.ErrInvalidUTF8)
+	c.Assert(validator.Valid(42, "validutf8"), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestNoControl(c *C) {
+	c.Assert(validator.Valid("line one\n\tline two\r\n", "nocontrol"), IsNil)
+	for _, s := range []string{"a\x00b", "\x1b[31mred", "a\u0085b", "\x7f"} {
+		c.Assert(validator.Valid(s, "nocontrol"), HasError, validator.ErrControl, Commentf("%q", s))
+	}
+}
+
+func (ms *MySuite) TestBytes(c *C) {
+	c.Assert(validator.Valid("héllo", "max=5,maxbytes=6,minbytes=6,lenbytes=6"), IsNil)
+	c.Assert(validator.Valid("héllo", "maxbytes=5"), HasError, validator.ErrMaxBytes(5, 6))
+	c.Assert(validator.Valid("日本", "minbytes=8"), HasError, validator.ErrMinBytes(8, 6))
+	c.Assert(validator.Valid("", "minbytes=1"), HasError, validator.ErrMinBytes(1, 0))
+	c.Assert(validator.Valid("abc", "lenbytes=2"), HasError, validator.ErrLenBytes(2, 3))
+	c.Assert(validator.Valid("abc", "maxbytes=x"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid([]byte("abc"), "maxbytes=2"), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestNoHTML(c *C) {
+	for _, s := range []string{"Jane Doe", "a < b && b > c", "1<2", "x <= y", "AT&T"} {
+		c.Assert(validator.Valid(s, "nohtml"), IsNil, Commentf(s))
+	}
+	for _, s := range []string{"<b>Jane</b>", "Jane<script>alert(1)</script>", "</div>", "<!-- hi -->", "<img src=x onerror=alert(1)>", "<?php"} {
+		c.Assert(validator.Valid(s, "nohtml"), HasError, validator.ErrHTML, Commentf(s))
+	}
+}
@@ -0,0 +1,161 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// isNil reports whether v is nil or a nil pointer, which the rules
+// other than nonzero leave to it.
+func isNil(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return !rv.IsValid() || rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// before tests whether a time is before the time given as parameter.
+func before(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	t, ok := v.(time.Time)
+	if !ok {
+		return ErrUnsupported
+	}
+	p, err := asTime(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if !t.Before(p) {
+		return ErrBefore(p)
+	}
+	return nil
+}
+
+// after tests whether a time is after the time given as parameter.
+func after(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	t, ok := v.(time.Time)
+	if !ok {
+		return ErrUnsupported
+	}
+	p, err := asTime(param)
+	if err != nil {
+		return ErrBadParameter
+	}
+	if !t.After(p) {
+		return ErrAfter(p)
+	}
+	return nil
+}
+
+// betweenTimes tests whether the time t is within the two times given
+// as bounds, bounds included.
+func betweenTimes(t time.Time, bounds []string) error {
+	start, err := asTime(bounds[0])
+	if err != nil {
+		return ErrBadParameter
+	}
+	end, err := asTime(bounds[1])
+	if err != nil {
+		return ErrBadParameter
+	}
+	if t.Before(start) {
+		return ErrAfter(start)
+	}
+	if t.After(end) {
+		return ErrBefore(end)
+	}
+	return nil
+}
+
+// asTime returns the parameter as a time. The parameter is either a
+// RFC 3339 time or now, optionally followed by a duration to add to
+// the current time, e.g. now+24h or now-1h30m.
+func asTime(param string) (time.Time, error) {
+	if !strings.HasPrefix(param, "now") {
+		return time.Parse(time.RFC3339, param)
+	}
+	now := time.Now()
+	if param == "now" {
+		return now, nil
+	}
+	if param[3] != '+' && param[3] != '-' {
+		return time.Time{}, ErrBadParameter
+	}
+	d, err := time.ParseDuration(param[3:])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(d), nil
+}
+
+// asDuration returns the parameter as a duration, given either as a
+// duration string, e.g. 1m30s, or as a number of nanoseconds.
+func asDuration(param string) (time.Duration, error) {
+	if d, err := time.ParseDuration(param); err == nil {
+		return d, nil
+	}
+	i, err := asInt(param)
+	return time.Duration(i), err
+}
+
+// datetimeLayouts holds the layouts of the datetime builtin which can
+// be given by name.
+var datetimeLayouts = map[string]string{
+	"ansic":       time.ANSIC,
+	"unixdate":    time.UnixDate,
+	"rfc822":      time.RFC822,
+	"rfc822z":     time.RFC822Z,
+	"rfc850":      time.RFC850,
+	"rfc1123":     time.RFC1123,
+	"rfc1123z":    time.RFC1123Z,
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"kitchen":     time.Kitchen,
+	"date":        "2006-01-02",
+	"time":        "15:04:05",
+	"datetime":    "2006-01-02 15:04:05",
+}
+
+// datetime tests whether a string is a time formatted with the layout
+// given as parameter, either a Go reference layout or the name of a
+// common one, e.g. rfc3339.
+func datetime(v interface{}, param string) error {
+	if isNil(v) {
+		return nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return ErrUnsupported
+	}
+	if param == "" {
+		return ErrBadParameter
+	}
+	layout, ok := datetimeLayouts[strings.ToLower(param)]
+	if !ok {
+		layout = param
+	}
+	if _, err := time.Parse(layout, s); err != nil {
+		return ErrDatetime(param)
+	}
+	return nil
+}
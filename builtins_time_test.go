@@ -0,0 +1,93 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"time"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestTimeNonzero(c *C) {
+	c.Assert(validator.Valid(time.Time{}, "nonzero"), HasError, validator.ErrZeroValueEmpty)
+	c.Assert(validator.Valid(time.Now(), "nonzero"), IsNil)
+
+	type T struct {
+		Created time.Time  `validate:"nonzero"`
+		Updated *time.Time `validate:"nonzero"`
+	}
+	errs, ok := validator.Validate(T{Updated: &time.Time{}}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Created"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Updated"], HasError, validator.ErrZeroValueEmpty)
+}
+
+func (ms *MySuite) TestTimeBeforeAfter(c *C) {
+	y2k := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.Assert(validator.Valid(y2k, "before=2000-01-02T00:00:00Z"), IsNil)
+	c.Assert(validator.Valid(y2k, "before=2000-01-01T00:00:00Z"), HasError, validator.ErrBefore(y2k))
+	c.Assert(validator.Valid(y2k, "after=1999-12-31T23:00:00-01:00"), HasError, validator.ErrAfter(y2k.In(time.FixedZone("", -3600))))
+	c.Assert(validator.Valid(y2k, "after=1999-12-31T00:00:00Z"), IsNil)
+
+	c.Assert(validator.Valid(time.Now().Add(time.Hour), "after=now"), IsNil)
+	c.Assert(validator.Valid(time.Now().Add(-time.Hour), "after=now"), NotNil)
+	c.Assert(validator.Valid(time.Now().Add(time.Hour), "before=now+2h"), IsNil)
+	c.Assert(validator.Valid(time.Now().Add(-time.Hour), "before=now-2h"), NotNil)
+
+	c.Assert(validator.Valid(y2k, "before=tomorrow"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(y2k, "before=now*2h"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid("2000-01-01", "before=now"), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestTimeBetween(c *C) {
+	y2k := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	tag := "between=2000-01-01T00:00:00Z 2000-12-31T00:00:00Z"
+	c.Assert(validator.Valid(y2k, tag), IsNil)
+	c.Assert(validator.Valid(y2k.Add(-time.Second), tag), HasError, validator.ErrAfter(y2k))
+	c.Assert(validator.Valid(y2k.AddDate(1, 0, 0), tag), HasError, validator.ErrBefore(y2k.AddDate(0, 0, 365)))
+	c.Assert(validator.Valid(time.Now(), "between=now-1m now+1m"), IsNil)
+	c.Assert(validator.Valid(y2k, "between=now"), HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestDurationMinMax(c *C) {
+	type T struct {
+		Timeout time.Duration `validate:"min=100ms,max=30s"`
+		Legacy  time.Duration `validate:"max=1000"`
+	}
+	c.Assert(validator.Validate(T{Timeout: time.Second}), IsNil)
+	errs, ok := validator.Validate(T{Timeout: time.Millisecond, Legacy: time.Microsecond + 1}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Timeout"], HasError, validator.ErrMinDuration(100*time.Millisecond, time.Millisecond))
+	c.Assert(errs["Legacy"], HasError, validator.ErrMaxDuration(time.Microsecond, time.Microsecond+1))
+	c.Assert(validator.Valid(time.Minute, "max=30s"), HasError, validator.ErrMaxDuration(30*time.Second, time.Minute))
+	c.Assert(validator.Valid(time.Minute, "max=1x"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.ErrMaxDuration(30*time.Second, time.Minute).Error(), Equals, "Must not be greater than 30s, was 1m0s")
+}
+
+func (ms *MySuite) TestDatetime(c *C) {
+	c.Assert(validator.Valid("2017-03-04", "datetime=2006-01-02"), IsNil)
+	c.Assert(validator.Valid("2017-03-04", "datetime=date"), IsNil)
+	c.Assert(validator.Valid("2017-03-04T10:00:00Z", "datetime=rfc3339"), IsNil)
+	c.Assert(validator.Valid("2017-03-04T10:00:00Z", "datetime=RFC3339"), IsNil)
+	c.Assert(validator.Valid("2017-03-04 10:00:00", "datetime=2006-01-02 15:04:05"), IsNil)
+	c.Assert(validator.Valid("04/03/2017", "datetime=2006-01-02"), HasError, validator.ErrDatetime("2006-01-02"))
+	c.Assert(validator.Valid("2017-03-04", "datetime=rfc3339"), HasError, validator.ErrDatetime("rfc3339"))
+	c.Assert(validator.Valid("2017-03-04", "datetime"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(42, "datetime=date"), HasError, validator.ErrUnsupported)
+}
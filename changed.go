@@ -0,0 +1,121 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValidateChanged validates the fields of the struct new whose value
+// differs from the one they have in old, a struct of the same type, e.g.
+// the stored version of a resource being updated, so that the values the
+// update leaves untouched are not validated again. Every field is
+// validated when old is nil. The fields of nested
+// structs and the elements of slices of structs are compared one by one,
+// unless the field holding them has rules of its own, in which case it is
+// validated as a whole when any of them changed.
+func ValidateChanged(old, new interface{}) error {
+	return defaultValidator.ValidateChanged(old, new)
+}
+
+// ValidateChanged validates the fields of the struct new whose value
+// differs from the one they have in old, a struct of the same type, e.g.
+// the stored version of a resource being updated, so that the values the
+// update leaves untouched are not validated again. Every field is
+// validated when old is nil. The fields of nested
+// structs and the elements of slices of structs are compared one by one,
+// unless the field holding them has rules of its own, in which case it is
+// validated as a whole when any of them changed.
+func (mv *Validator) ValidateChanged(old, new interface{}) error {
+	ov, nv := reflect.ValueOf(old), reflect.ValueOf(new)
+	for ov.Kind() == reflect.Ptr && !ov.IsNil() {
+		ov = ov.Elem()
+	}
+	for nv.Kind() == reflect.Ptr && !nv.IsNil() {
+		nv = nv.Elem()
+	}
+	if nv.Kind() != reflect.Struct {
+		return ErrUnsupported
+	}
+	if !ov.IsValid() || ov.Kind() == reflect.Ptr {
+		// there is nothing to compare with, e.g. on creation
+		return mv.Validate(new)
+	}
+	if ov.Type() != nv.Type() {
+		return ErrUnsupported
+	}
+	var paths []string
+	mv.changedFields(ov, nv, "", &paths)
+	if len(paths) == 0 {
+		return nil
+	}
+	return mv.ValidateFields(new, paths...)
+}
+
+// changedFields adds the paths of the fields of the struct nv, found at
+// path, whose value differs from the one they have in ov to paths.
+func (mv *Validator) changedFields(ov, nv reflect.Value, path string, paths *[]string) {
+	for _, sf := range mv.structFields(nv.Type()) {
+		of, nf := ov.Field(sf.index), nv.Field(sf.index)
+		// unexported embedded structs are only compared field by field
+		comparable := nf.CanInterface()
+		if comparable && reflect.DeepEqual(of.Interface(), nf.Interface()) {
+			continue
+		}
+		name, _ := mv.fieldNames(sf)
+		p := joinPath(path, name)
+		n := len(*paths)
+		if sf.tags == nil && sf.descend {
+			mv.changedValues(of, nf, mv.nestedPath(sf, path, name), paths)
+		}
+		if len(*paths) == n && comparable {
+			*paths = append(*paths, p)
+		}
+	}
+}
+
+// changedValues adds the paths of the fields of the structs held by nv,
+// directly or in a slice or an array, which differ from those of ov to
+// paths. Nothing is added when they cannot be compared one by one.
+func (mv *Validator) changedValues(ov, nv reflect.Value, path string, paths *[]string) {
+	for ov.Kind() == reflect.Ptr && nv.Kind() == reflect.Ptr && !ov.IsNil() && !nv.IsNil() {
+		ov, nv = ov.Elem(), nv.Elem()
+	}
+	switch nv.Kind() {
+	case reflect.Struct:
+		if ov.Type() == nv.Type() {
+			mv.changedFields(ov, nv, path, paths)
+		}
+	case reflect.Slice, reflect.Array:
+		if ov.Len() != nv.Len() || !mayHoldStruct(nv.Type().Elem()) {
+			return
+		}
+		for i := 0; i < nv.Len(); i++ {
+			oe, ne := ov.Index(i), nv.Index(i)
+			if reflect.DeepEqual(oe.Interface(), ne.Interface()) {
+				continue
+			}
+			p := fmt.Sprintf("%s[%d]", path, i)
+			n := len(*paths)
+			mv.changedValues(oe, ne, p, paths)
+			if len(*paths) == n {
+				*paths = append(*paths, p)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type changedUser struct {
+	Name     string   `validate:"min=3"`
+	Email    string   `validate:"nonzero"`
+	Tags     []string `validate:"max=2"`
+	Address  patchAddress
+	Previous []patchAddress
+	Work     *patchAddress
+}
+
+func (ms *MySuite) TestValidateChanged(c *C) {
+	// the stored value is invalid in many ways
+	old := changedUser{
+		Name:     "Jo",
+		Tags:     []string{"a", "b", "c"},
+		Previous: []patchAddress{{}, {}},
+		Work:     &patchAddress{},
+	}
+	c.Assert(validator.ValidateChanged(old, old), IsNil)
+
+	u := old
+	u.Email = "jo@example.com"
+	c.Assert(validator.ValidateChanged(old, u), IsNil)
+
+	u.Name = "J"
+	u.Address.City = "Paris"
+	u.Previous = []patchAddress{{}, {Street: "1 Main St"}}
+	u.Work = &patchAddress{City: "Paris"}
+	errs, ok := validator.ValidateChanged(&old, &u).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	// the fields left untouched in nested structs are not validated
+	c.Assert(errs.Paths(), DeepEquals, []string{"Name"})
+	c.Assert(errs["Name"], HasError, validator.ErrMinString(3, 1))
+
+	// fields with rules of their own are validated as a whole
+	u = old
+	u.Tags = []string{"a", "b", "d"}
+	u.Previous = []patchAddress{{}}
+	errs, ok = validator.ValidateChanged(old, u).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Previous[0].City", "Previous[0].Street", "Tags"})
+
+	errs, ok = validator.ValidateChanged((*changedUser)(nil), u).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 9)
+
+	c.Assert(validator.ValidateChanged(patchUser{}, u), Equals, validator.ErrUnsupported)
+	c.Assert(validator.ValidateChanged(1, 2), Equals, validator.ErrUnsupported)
+}
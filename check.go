@@ -0,0 +1,152 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+)
+
+// Check checks the rules of v, a struct or a pointer to a struct, and of
+// the structs it holds, or MapRules, without validating any value, e.g.
+// at startup. It returns an ErrorMap holding ErrUnknownTag for the rules
+// which are not known and ErrBadParameter for those with an invalid
+// parameter, such as min=abc or a regular expression which does not
+// compile, indexed by the path of their field.
+func Check(v interface{}) error {
+	return defaultValidator.Check(v)
+}
+
+// Check checks the rules of v, a struct or a pointer to a struct, and of
+// the structs it holds, or MapRules, without validating any value, e.g.
+// at startup. It returns an ErrorMap holding ErrUnknownTag for the rules
+// which are not known and ErrBadParameter for those with an invalid
+// parameter, such as min=abc or a regular expression which does not
+// compile, indexed by the path of their field.
+func (mv *Validator) Check(v interface{}) error {
+	m := make(ErrorMap)
+	if rules, ok := v.(MapRules); ok {
+		mv.checkRules(rules, "", m)
+	} else {
+		t, err := structType(v)
+		if err != nil {
+			return err
+		}
+		mv.checkStruct(t, "", map[reflect.Type]bool{}, m)
+	}
+	if len(m) > 0 {
+		return m
+	}
+	return nil
+}
+
+// checkStruct adds the errors of the rules of the fields of the struct
+// type t, and of the structs it holds, to m.
+func (mv *Validator) checkStruct(t reflect.Type, path string, seen map[reflect.Type]bool, m ErrorMap) {
+	seen[t] = true
+	defer delete(seen, t)
+	for _, sf := range mv.structFields(t) {
+		name, errName := mv.fieldNames(sf)
+		p := joinPath(path, errName)
+		if sf.err != nil {
+			m[p] = append(m[p], sf.err)
+		}
+		for _, tg := range sf.tags {
+			if err := mv.checkTag(tg, sf.field.Type); err != nil {
+				m[p] = append(m[p], err)
+			}
+		}
+		if ft := nestedStruct(sf); ft != nil && !seen[ft] {
+			mv.checkStruct(ft, mv.nestedPath(sf, path, name), seen, m)
+		}
+	}
+}
+
+// checkRules adds the errors of rules, the rules of the values of maps,
+// to m.
+func (mv *Validator) checkRules(rules MapRules, path string, m ErrorMap) {
+	for _, k := range sortedKeys(rules) {
+		p := joinPath(path, k)
+		switch r := rules[k].(type) {
+		case string:
+			mv.lock.RLock()
+			tags, err := mv.parseTags(r)
+			mv.lock.RUnlock()
+			if err != nil {
+				m[p] = append(m[p], err)
+			}
+			for _, tg := range tags {
+				if err := mv.checkTag(tg, nil); err != nil {
+					m[p] = append(m[p], err)
+				}
+			}
+		case MapRules:
+			mv.checkRules(r, p, m)
+		case map[string]interface{}:
+			mv.checkRules(MapRules(r), p, m)
+		default:
+			m[p] = append(m[p], ErrBadParameter)
+		}
+	}
+}
+
+// checkTag returns ErrBadParameter if the validation function of tg
+// rejects its parameter when applied to sample values of type t, or of
+// decoded JSON values when t is nil. Context aware functions, which may
+// be slow or remote checks, are not applied.
+func (mv *Validator) checkTag(tg tag, t reflect.Type) error {
+	if tg.Fn == nil {
+		return nil
+	}
+	for _, v := range mv.sampleValues(t) {
+		if badParameter(tg, v) {
+			return ErrBadParameter
+		}
+	}
+	return nil
+}
+
+// sampleValues returns the values of type t the rules are applied to
+// when checking them: the zero value and, for strings, a non-empty one,
+// as the rules checking the format of strings accept empty ones.
+func (mv *Validator) sampleValues(t reflect.Type) []interface{} {
+	if t == nil {
+		return []interface{}{nil, "x", float64(0)}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+	values := []interface{}{mv.ruleValue(reflect.Zero(t).Interface())}
+	if t.Kind() == reflect.String {
+		values = append(values, mv.ruleValue(reflect.ValueOf("x").Convert(t).Interface()))
+	}
+	return values
+}
+
+// badParameter reports whether the validation function of tg returns
+// ErrBadParameter for v, functions panicking for values they do not
+// expect being ignored.
+func badParameter(tg tag, v interface{}) (bad bool) {
+	defer func() {
+		if recover() != nil {
+			bad = false
+		}
+	}()
+	return tg.Fn(v, tg.Param) == ErrBadParameter
+}
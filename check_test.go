@@ -0,0 +1,79 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type checkedAddress struct {
+	City string `validate:"nonzero,max=x"`
+}
+
+type checked struct {
+	Name     string            `json:"name" validate:"min=abc,foo"`
+	Code     string            `validate:"regexp=^[a-z"`
+	Email    string            `validate:"hostname,startswith="`
+	Age      *int              `validate:"min=18,max=1.5"`
+	Tags     []string          `validate:"max=5"`
+	Previous []checkedAddress  `validate:"max=3"`
+	Home     *checkedAddress   `validate:"nonzero"`
+	Labels   map[string]string `validate:"unique"`
+	Limit    int               `default:"many"`
+	Valid    string            `validate:"nonzero,len=3,regexp=^[A-Z]+$"`
+}
+
+func (ms *MySuite) TestCheck(c *C) {
+	err := validator.Check(&checked{})
+	c.Assert(err, DeepEquals, validator.ErrorMap{
+		"name":          {validator.ErrUnknownTag},
+		"Code":          {validator.ErrBadParameter},
+		"Email":         {validator.ErrBadParameter},
+		"Age":           {validator.ErrBadParameter},
+		"Previous.City": {validator.ErrBadParameter},
+		"Home.City":     {validator.ErrBadParameter},
+		"Limit":         {validator.ErrBadParameter},
+	})
+	c.Assert(validator.Check(checkedAddress{City: "ok"}), DeepEquals, validator.ErrorMap{
+		"City": {validator.ErrBadParameter},
+	})
+	c.Assert(validator.Check(schemaUser{}), IsNil)
+	c.Assert(validator.Check("x"), Equals, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestCheckMapRules(c *C) {
+	err := validator.Check(validator.MapRules{
+		"name": "nonzero,max=x",
+		"code": "regexp=(",
+		"age":  "min=18",
+		"address": validator.MapRules{
+			"city": "foo",
+		},
+	})
+	c.Assert(err, DeepEquals, validator.ErrorMap{
+		"name":         {validator.ErrBadParameter},
+		"code":         {validator.ErrBadParameter},
+		"address.city": {validator.ErrUnknownTag},
+	})
+}
+
+func (ms *MySuite) TestMustCompile(c *C) {
+	c.Assert(validator.MustCompile(schemaAddress{}), NotNil)
+	c.Assert(func() { validator.MustCompile(checkedAddress{}) }, PanicMatches, `validator: Compile: City: bad parameter`)
+}
@@ -0,0 +1,271 @@
+// Command validate validates JSON and YAML documents
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command validate validates JSON and YAML documents against the
+// validation tags of a Go type, e.g. in CI checks of data files:
+//
+//	validate -type github.com/acme/app/config.Config config.yaml
+//	validate -type '[]github.com/acme/app/users.User' users.json
+//
+// It generates and builds, with the go command, a program decoding the
+// documents into values of the type and validating them, from the module
+// of the directory given by -dir. The errors of the fields are printed one
+// per line, with the file and the path of the field:
+//
+//	config.yaml: database.max_connections: Must be at least 1, was 0
+//
+// The paths are made of the JSON keys of the fields for JSON documents
+// and of their YAML keys for YAML documents, those with a .yaml or .yml
+// extension unless -format says otherwise. YAML documents are decoded
+// with the package given by -yaml, which the module must require. The
+// standard input is read when the file name is -.
+//
+// The exit status is 0 if all the documents are valid, 1 if some are
+// invalid and 2 if some cannot be read or decoded, or the program
+// cannot be built.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
+)
+
+var (
+	typeName   = flag.String("type", "", "import path and name of the type, e.g. example.com/pkg.Config; []example.com/pkg.Config for lists")
+	formatName = flag.String("format", "", "format of the documents, json or yaml; after the file extension when empty")
+	dir        = flag.String("dir", ".", "directory of the module the program is built in")
+	yamlPath   = flag.String("yaml", "gopkg.in/yaml.v3", "import path of the YAML package")
+	importPath = flag.String("import", "github.com/movio/validator", "import path of the validator package")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of validate:\n")
+	fmt.Fprintf(os.Stderr, "\tvalidate -type path.Type [flags] file...\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("validate: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *typeName == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *formatName != "" && *formatName != "json" && *formatName != "yaml" {
+		log.Printf("unknown format %q", *formatName)
+		os.Exit(2)
+	}
+
+	p := &program{Validator: *importPath}
+	if err := p.setType(*typeName); err != nil {
+		log.Print(err)
+		os.Exit(2)
+	}
+	var args []string
+	for _, name := range flag.Args() {
+		f := documentFormat(name, *formatName)
+		if f == "yaml" {
+			p.YAML = *yamlPath
+		}
+		args = append(args, f, name)
+	}
+	os.Exit(run(p, *dir, args))
+}
+
+// documentFormat returns the format of the document name: format if not
+// empty, yaml for .yaml and .yml files and json otherwise.
+func documentFormat(name, format string) string {
+	if format != "" {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return "json"
+}
+
+// run builds the program p in the module of dir and runs it with the
+// formats and names of the documents args, returning its exit status.
+func run(p *program, dir string, args []string) int {
+	src, err := p.generate()
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	tmp, err := ioutil.TempDir("", "validate")
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	defer os.RemoveAll(tmp)
+	file := filepath.Join(tmp, "main.go")
+	if err := ioutil.WriteFile(file, src, 0644); err != nil {
+		log.Print(err)
+		return 2
+	}
+	bin := filepath.Join(tmp, "validate")
+	build := exec.Command("go", "build", "-o", bin, file)
+	build.Dir = dir
+	build.Stdout, build.Stderr = os.Stderr, os.Stderr
+	if err := build.Run(); err != nil {
+		log.Printf("building the program validating %s: %v", p.typeName(), err)
+		return 2
+	}
+
+	cmd := exec.Command(bin, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	err = cmd.Run()
+	if exit, ok := err.(*exec.ExitError); ok {
+		if status, ok := exit.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus()
+		}
+	}
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	return 0
+}
+
+// program describes the program validating the documents.
+type program struct {
+	// Package and Type are the import path and name of the type of the
+	// documents, and List reports whether they are lists of values of it.
+	Package string
+	Type    string
+	List    bool
+	// Validator and YAML are the import paths of the validator package
+	// and of the YAML package, empty if no YAML document is validated.
+	Validator string
+	YAML      string
+}
+
+// setType sets the type of the documents to the type named s, e.g.
+// example.com/pkg.Config or []example.com/pkg.Config.
+func (p *program) setType(s string) error {
+	name := strings.TrimPrefix(s, "[]")
+	p.List = name != s
+	i := strings.LastIndex(name, ".")
+	if i <= strings.LastIndex(name, "/") || i == len(name)-1 {
+		return fmt.Errorf("invalid type %q, expected an import path and a type name, e.g. example.com/pkg.Config", s)
+	}
+	p.Package, p.Type = name[:i], name[i+1:]
+	return nil
+}
+
+// typeName returns the name of the type of the documents as given to
+// the -type flag.
+func (p *program) typeName() string {
+	name := p.Package + "." + p.Type
+	if p.List {
+		name = "[]" + name
+	}
+	return name
+}
+
+// generate returns the source of the program.
+func (p *program) generate() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := programTemplate.Execute(&buf, p); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+var programTemplate = template.Must(template.New("program").Parse(`// Code generated by validate; DO NOT EDIT.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	target {{printf "%q" .Package}}
+	validator {{printf "%q" .Validator}}
+{{- if .YAML}}
+	yaml {{printf "%q" .YAML}}
+{{- end}}
+)
+
+func main() {
+	status := 0
+	for i := 1; i+1 < len(os.Args); i += 2 {
+		format, name := os.Args[i], os.Args[i+1]
+		var v {{if .List}}[]{{end}}target.{{.Type}}
+		switch errs := decode(format, name, &v).(type) {
+		case nil:
+		case validator.ErrorMap:
+			for _, path := range errs.Paths() {
+				prefix := name + ": "
+				if path != "" {
+					prefix += path + ": "
+				}
+				for _, err := range errs[path] {
+					fmt.Println(prefix + err.Error())
+				}
+			}
+			if status == 0 {
+				status = 1
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, errs)
+			status = 2
+		}
+	}
+	os.Exit(status)
+}
+
+// decode decodes the document name in the given format into v and
+// validates it.
+func decode(format, name string, v interface{}) error {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(name)
+	}
+	if err != nil {
+		return err
+	}
+{{- if .YAML}}
+	if format == "yaml" {
+		return validator.UnmarshalYAML(yaml.Unmarshal, data, v)
+	}
+{{- end}}
+	return validator.NewJSONDecoder(bytes.NewReader(data)).Decode(v)
+}
+`))
@@ -0,0 +1,75 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type ValidateSuite struct{}
+
+var _ = Suite(&ValidateSuite{})
+
+func (s *ValidateSuite) TestGenerate(c *C) {
+	p := &program{Validator: "github.com/movio/validator", YAML: "gopkg.in/yaml.v3"}
+	c.Assert(p.setType("[]example.com/app/users.User"), IsNil)
+	src, err := p.generate()
+	c.Assert(err, IsNil)
+
+	golden := filepath.Join("testdata", "program.golden")
+	if *update {
+		c.Assert(ioutil.WriteFile(golden, src, 0644), IsNil)
+	}
+	want, err := ioutil.ReadFile(golden)
+	c.Assert(err, IsNil)
+	c.Assert(string(src), Equals, string(want))
+}
+
+func (s *ValidateSuite) TestSetType(c *C) {
+	p := &program{}
+	c.Assert(p.setType("example.com/app/config.Config"), IsNil)
+	c.Assert(p, DeepEquals, &program{Package: "example.com/app/config", Type: "Config"})
+	c.Assert(p.typeName(), Equals, "example.com/app/config.Config")
+
+	c.Assert(p.setType("[]time.Duration"), IsNil)
+	c.Assert(p, DeepEquals, &program{Package: "time", Type: "Duration", List: true})
+	c.Assert(p.typeName(), Equals, "[]time.Duration")
+
+	for _, s := range []string{"Config", "example.com/app", "example.com/app.", "[]"} {
+		c.Assert(p.setType(s), ErrorMatches, "invalid type .*")
+	}
+}
+
+func (s *ValidateSuite) TestDocumentFormat(c *C) {
+	c.Assert(documentFormat("config.yaml", ""), Equals, "yaml")
+	c.Assert(documentFormat("testdata/config.YML", ""), Equals, "yaml")
+	c.Assert(documentFormat("users.json", ""), Equals, "json")
+	c.Assert(documentFormat("-", ""), Equals, "json")
+	c.Assert(documentFormat("-", "yaml"), Equals, "yaml")
+	c.Assert(documentFormat("config.yaml", "json"), Equals, "json")
+}
@@ -0,0 +1,553 @@
+// Command validatorgen generates reflection-free Validate methods
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command validatorgen generates Validate methods for struct types from
+// their validation tags, so that they can be validated without the cost
+// of reflection. It is meant to be used with go generate:
+//
+//	//go:generate validatorgen -type User,Address
+//
+// The generated methods check the builtin rules nonzero, len, min, max
+// and regexp directly for fields of basic types, strings, slices and maps
+// and fall back to validator.Valid for the tags with any other rule. The
+// fields holding values validator.Validate walks in ways not generated,
+// such as embedded fields, pointers, maps or interfaces, are validated by
+// validator.ValidateFields. Their errors are the same as those of
+// validator.Validate, indexed the same way.
+// Aliases registered with validator.RegisterAlias are only known at run
+// time, so those shadowing the rules checked directly are not applied.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	typeNames  = flag.String("type", "", "comma-separated list of type names; all structs with validation tags when empty")
+	tagName    = flag.String("tag", "validate", "name of the struct tag holding the validation rules")
+	output     = flag.String("output", "", "output file name; default srcdir/validator_gen.go")
+	importPath = flag.String("import", "github.com/movio/validator", "import path of the validator package")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of validatorgen:\n")
+	fmt.Fprintf(os.Stderr, "\tvalidatorgen [flags] [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("validatorgen: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+
+	g := &generator{tag: *tagName, importPath: *importPath}
+	if err := g.parseDir(dir); err != nil {
+		log.Fatal(err)
+	}
+	src, err := g.generate(types)
+	if err != nil {
+		log.Fatal(err)
+	}
+	out := *output
+	if out == "" {
+		out = filepath.Join(dir, "validator_gen.go")
+	}
+	if err := ioutil.WriteFile(out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generator holds the state of the generation of a package.
+type generator struct {
+	tag        string
+	importPath string
+
+	pkg string
+	// structs holds the struct types of the package and basics the
+	// underlying type of the named types defined from a basic type.
+	structs map[string]*ast.StructType
+	basics  map[string]string
+	// generated holds the types a method is generated for.
+	generated map[string]bool
+
+	buf     bytes.Buffer
+	regexps []string
+	imports map[string]bool
+	// err is the first error found generating the methods.
+	err error
+}
+
+// parseDir parses the Go files of dir, tests excluded.
+func (g *generator) parseDir(dir string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return err
+	}
+	if len(pkgs) != 1 {
+		return fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+	for _, p := range pkgs {
+		return g.parsePackage(p)
+	}
+	return nil
+}
+
+// parsePackage collects the types declared by p.
+func (g *generator) parsePackage(p *ast.Package) error {
+	g.pkg = p.Name
+	g.structs = map[string]*ast.StructType{}
+	g.basics = map[string]string{}
+	names := make([]string, 0, len(p.Files))
+	for name := range p.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ast.Inspect(p.Files[name], func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			switch t := ts.Type.(type) {
+			case *ast.StructType:
+				g.structs[ts.Name.Name] = t
+			case *ast.Ident:
+				if basicKinds[t.Name] != "" {
+					g.basics[ts.Name.Name] = t.Name
+				}
+			}
+			return false
+		})
+	}
+	return nil
+}
+
+// generate returns the source of the Validate methods of the given
+// types, or of all structs with validation tags if none is given.
+func (g *generator) generate(types []string) ([]byte, error) {
+	if len(types) == 0 {
+		for name, st := range g.structs {
+			if g.hasTags(st) {
+				types = append(types, name)
+			}
+		}
+		sort.Strings(types)
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no struct with %s tags found", g.tag)
+	}
+	g.generated = map[string]bool{}
+	for _, name := range types {
+		if g.structs[name] == nil {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+		g.generated[name] = true
+	}
+
+	g.imports = map[string]bool{}
+	g.regexps = nil
+	g.err = nil
+	var body bytes.Buffer
+	for _, name := range types {
+		g.buf.Reset()
+		g.genType(name, g.structs[name])
+		body.Write(g.buf.Bytes())
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by validatorgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", g.pkg)
+	fmt.Fprintf(&src, "import (\n")
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	for _, imp := range imports {
+		fmt.Fprintf(&src, "\t%q\n", imp)
+	}
+	if len(imports) > 0 {
+		fmt.Fprintf(&src, "\n")
+	}
+	fmt.Fprintf(&src, "\t%q\n", g.importPath)
+	fmt.Fprintf(&src, ")\n\n")
+	if len(g.regexps) > 0 {
+		fmt.Fprintf(&src, "var (\n")
+		for i, re := range g.regexps {
+			fmt.Fprintf(&src, "\tvalidatorRegexp%d = regexp.MustCompile(%q)\n", i, re)
+		}
+		fmt.Fprintf(&src, ")\n\n")
+	}
+	src.Write(body.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return formatted, nil
+}
+
+// hasTags reports whether st has at least one validation tag.
+func (g *generator) hasTags(st *ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if g.fieldTag(f) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldTag returns the validation tag of f.
+func (g *generator) fieldTag(f *ast.Field) string {
+	if f.Tag == nil {
+		return ""
+	}
+	s, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(s).Get(g.tag)
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// errorf records an error found generating the methods, if the first.
+func (g *generator) errorf(format string, args ...interface{}) {
+	if g.err == nil {
+		g.err = fmt.Errorf(format, args...)
+	}
+}
+
+// genType generates the Validate method of the struct type name.
+func (g *generator) genType(name string, st *ast.StructType) {
+	g.printf("// Validate validates the fields of %s based on their tags.\n", name)
+	g.printf("func (t %s) Validate() error {\n", name)
+	g.printf("errs := validator.ErrorMap{}\n")
+	// walked holds the fields validated by validator.ValidateFields.
+	var walked []string
+	for _, f := range st.Fields.List {
+		tag := g.fieldTag(f)
+		if tag == "-" {
+			continue
+		}
+		if len(f.Names) == 0 {
+			// embedded fields are named after their type
+			walked = append(walked, strconv.Quote(embeddedName(f.Type)))
+			continue
+		}
+		for _, n := range f.Names {
+			// as for validator.Validate, unexported fields are ignored
+			if !ast.IsExported(n.Name) {
+				continue
+			}
+			if g.walked(f.Type) {
+				walked = append(walked, strconv.Quote(n.Name))
+				continue
+			}
+			g.genField(f, n.Name, tag)
+		}
+	}
+	if len(walked) > 0 {
+		g.genMerge("validator.ValidateFields(t, "+strings.Join(walked, ", ")+")", "")
+	}
+	g.printf("if len(errs) > 0 {\nreturn errs\n}\nreturn nil\n}\n\n")
+}
+
+// genField generates the validation of the field fname of type f.Type.
+func (g *generator) genField(f *ast.Field, fname, tag string) {
+	key := fname
+	if f.Tag != nil {
+		if s, err := strconv.Unquote(f.Tag.Value); err == nil {
+			if j := strings.SplitN(reflect.StructTag(s).Get("json"), ",", 2)[0]; j != "" && j != "-" {
+				key = j
+			}
+		}
+	}
+	expr := "t." + fname
+	if tag != "" {
+		g.genRules(f.Type, expr, key, tag)
+	}
+	g.genNested(f.Type, expr, fname)
+}
+
+// embeddedName returns the name of an embedded field of type typ.
+func embeddedName(typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+// walked reports whether validator.Validate walks the values held by the
+// fields of type typ in ways not generated: only the structs a method is
+// generated for, and the slices and arrays of them, are walked by the
+// generated code, and values of basic types hold nothing to walk.
+func (g *generator) walked(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return !g.isBasic(t.Name) && !g.generated[t.Name]
+	case *ast.StarExpr:
+		id, ok := t.X.(*ast.Ident)
+		return !ok || !g.isBasic(id.Name)
+	case *ast.ArrayType:
+		id, ok := t.Elt.(*ast.Ident)
+		return !ok || !g.isBasic(id.Name) && !g.generated[id.Name]
+	case *ast.MapType:
+		id, ok := t.Value.(*ast.Ident)
+		return !ok || !g.isBasic(id.Name)
+	case *ast.FuncType, *ast.ChanType:
+		return false
+	}
+	return true
+}
+
+// isBasic reports whether name is a basic type or a type defined from one.
+func (g *generator) isBasic(name string) bool {
+	return basicKinds[name] != "" || g.basics[name] != ""
+}
+
+// genRules generates the checks of the rules of tag.
+func (g *generator) genRules(typ ast.Expr, expr, key, tag string) {
+	kind := g.kindOf(typ)
+	if kind == "" || strings.ContainsAny(tag, "~\\'") || strings.Contains(tag, "groups=") || strings.Contains(tag, "@warn") {
+		g.genValid(expr, key, tag)
+		return
+	}
+	// Values of named types are converted to their underlying basic type.
+	converted := expr
+	if id, ok := typ.(*ast.Ident); ok && g.basics[id.Name] != "" {
+		converted = g.basics[id.Name] + "(" + expr + ")"
+	}
+	// A rule not checked directly makes the whole tag fall back to
+	// validator.Valid, which reports an unknown rule in place of the
+	// errors of the others, as validator.Validate does.
+	start, regexps := g.buf.Len(), len(g.regexps)
+	imports := make(map[string]bool, len(g.imports))
+	for k, v := range g.imports {
+		imports[k] = v
+	}
+	for _, rule := range strings.Split(tag, ",") {
+		v := strings.SplitN(rule, "=", 2)
+		name := strings.TrimSpace(v[0])
+		var param string
+		if len(v) > 1 {
+			param = strings.TrimSpace(v[1])
+		}
+		if !g.genRule(kind, converted, key, name, param) {
+			g.buf.Truncate(start)
+			g.regexps, g.imports = g.regexps[:regexps], imports
+			g.genValid(expr, key, tag)
+			return
+		}
+	}
+}
+
+// genValid generates a call to validator.Valid for the rules of tag.
+func (g *generator) genValid(expr, key, tag string) {
+	g.printf("if err := validator.Valid(%s, %q); err != nil {\n", expr, tag)
+	g.printf("if ea, ok := err.(validator.ErrorArray); ok {\n")
+	g.printf("errs[%q] = append(errs[%[1]q], ea...)\n", key)
+	g.printf("} else {\n")
+	g.printf("errs[%q] = append(errs[%[1]q], err)\n", key)
+	g.printf("}\n}\n")
+}
+
+// genNested generates the validation of the structs held by the field, of
+// a type not walked by validator.ValidateFields.
+func (g *generator) genNested(typ ast.Expr, expr, path string) {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if g.generated[t.Name] {
+			g.genMerge(expr+".Validate()", strconv.Quote(path+"."))
+		}
+	case *ast.ArrayType:
+		if id, ok := t.Elt.(*ast.Ident); ok && g.generated[id.Name] {
+			g.imports["strconv"] = true
+			g.printf("for i := range %s {\n", expr)
+			g.genMerge(expr+"[i].Validate()", fmt.Sprintf("%q + strconv.Itoa(i) + %q", path+"[", "]."))
+			g.printf("}\n")
+		}
+	}
+}
+
+// genMerge generates the merge of the errors returned by call into
+// errs, with their keys prefixed by prefix, if not empty.
+func (g *generator) genMerge(call, prefix string) {
+	g.printf("if err := %s; err != nil {\n", call)
+	g.printf("if em, ok := err.(validator.ErrorMap); ok {\n")
+	g.printf("for k, v := range em {\n")
+	if prefix != "" {
+		g.printf("k = %s + k\n", prefix)
+	}
+	g.printf("errs[k] = append(errs[k], v...)\n")
+	g.printf("}\n}\n}\n")
+}
+
+// basicKinds maps the basic types to the kind of checks they support.
+var basicKinds = map[string]string{
+	"string": "string",
+	"bool":   "bool",
+	"int":    "int", "int8": "int", "int16": "int", "int32": "int", "int64": "int", "rune": "int",
+	"uint": "uint", "uint8": "uint", "uint16": "uint", "uint32": "uint", "uint64": "uint", "byte": "uint",
+	"float32": "float", "float64": "float",
+}
+
+// kindOf returns the kind of checks supported by typ, or "" if the
+// rules are to be checked by validator.Valid.
+func (g *generator) kindOf(typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if k := basicKinds[t.Name]; k != "" {
+			return k
+		}
+		if b := g.basics[t.Name]; b != "" {
+			return basicKinds[b]
+		}
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "slice"
+		}
+	case *ast.MapType:
+		return "slice"
+	}
+	return ""
+}
+
+// genRule generates the check of one builtin rule, reporting false
+// if the rule is not one it can check for the given kind.
+func (g *generator) genRule(kind, expr, key, name, param string) bool {
+	add := func(cond, err string) {
+		g.printf("if %s {\n", cond)
+		g.printf("errs[%q] = append(errs[%[1]q], %s)\n", key, err)
+		g.printf("}\n")
+	}
+	size := expr
+	switch kind {
+	case "string":
+		g.imports["unicode/utf8"] = true
+		size = "utf8.RuneCountInString(" + expr + ")"
+	case "slice":
+		size = "len(" + expr + ")"
+	}
+
+	switch name {
+	case "nonzero":
+		switch kind {
+		case "string", "slice":
+			add(size+" == 0", "validator.ErrZeroValueEmpty")
+		case "int", "uint", "float":
+			add(expr+" == 0", "validator.ErrZeroValueNumber")
+		case "bool":
+			add("!"+expr, "validator.ErrZeroValueBool")
+		}
+		return true
+	case "len", "min", "max":
+		op := map[string]string{"len": "!=", "min": "<", "max": ">"}[name]
+		errName := map[string]string{"len": "Len", "min": "Min", "max": "Max"}[name]
+		switch kind {
+		case "string", "slice":
+			p, err := strconv.ParseInt(param, 0, 64)
+			if err != nil {
+				return false
+			}
+			suffix := "String"
+			if kind == "slice" {
+				suffix = "Array"
+			}
+			add(fmt.Sprintf("int64(%s) %s %d", size, op, p),
+				fmt.Sprintf("validator.Err%s%s(%d, %s)", errName, suffix, p, size))
+		case "int":
+			p, err := strconv.ParseInt(param, 0, 64)
+			if err != nil {
+				return false
+			}
+			add(fmt.Sprintf("int64(%s) %s %d", expr, op, p),
+				fmt.Sprintf("validator.Err%sInt(%d, int64(%s))", errName, p, expr))
+		case "uint":
+			p, err := strconv.ParseUint(param, 0, 64)
+			if err != nil {
+				return false
+			}
+			add(fmt.Sprintf("uint64(%s) %s %d", expr, op, p),
+				fmt.Sprintf("validator.Err%sUint(%d, uint64(%s))", errName, p, expr))
+		case "float":
+			p, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return false
+			}
+			add(fmt.Sprintf("float64(%s) %s %v", expr, op, p),
+				fmt.Sprintf("validator.Err%sFloat(%v, float64(%s))", errName, p, expr))
+		default:
+			return false
+		}
+		return true
+	case "regexp":
+		if kind != "string" {
+			return false
+		}
+		// the pattern is compiled now for a bad one to fail the
+		// generation rather than the initialization of the package
+		if _, err := regexp.Compile(param); err != nil {
+			g.errorf("%s: bad regexp parameter %q: %v", key, param, err)
+			return true
+		}
+		g.imports["regexp"] = true
+		g.regexps = append(g.regexps, param)
+		add(fmt.Sprintf("!validatorRegexp%d.MatchString(%s)", len(g.regexps)-1, expr),
+			fmt.Sprintf("validator.ErrRegexpDetailed(%q)", param))
+		return true
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type GenSuite struct{}
+
+var _ = Suite(&GenSuite{})
+
+func (s *GenSuite) TestGenerate(c *C) {
+	g := &generator{tag: "validate", importPath: "github.com/movio/validator"}
+	c.Assert(g.parseDir("testdata"), IsNil)
+	src, err := g.generate(nil)
+	c.Assert(err, IsNil)
+
+	golden := filepath.Join("testdata", "user.golden")
+	if *update {
+		c.Assert(ioutil.WriteFile(golden, src, 0644), IsNil)
+	}
+	want, err := ioutil.ReadFile(golden)
+	c.Assert(err, IsNil)
+	c.Assert(string(src), Equals, string(want))
+}
+
+func (s *GenSuite) TestGenerateUnknownType(c *C) {
+	g := &generator{tag: "validate", importPath: "github.com/movio/validator"}
+	c.Assert(g.parseDir("testdata"), IsNil)
+	_, err := g.generate([]string{"Missing"})
+	c.Assert(err, ErrorMatches, "struct type Missing not found")
+}
+
+func (s *GenSuite) TestGenerateBadRegexp(c *C) {
+	g := &generator{tag: "validate", importPath: "github.com/movio/validator"}
+	c.Assert(g.parseDir(filepath.Join("testdata", "badregexp")), IsNil)
+	_, err := g.generate(nil)
+	c.Assert(err, ErrorMatches, `Code: bad regexp parameter "\^\[a-z": .*`)
+}
+
+// roundTripTest compares the errors of the generated methods with those
+// of validator.Validate for the same values, decoded from JSON into the
+// types of the package with the generated methods and into those of a
+// copy of the package without them.
+const roundTripTest = `package user
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/movio/validator"
+
+	plain "PLAIN"
+)
+
+func check(t *testing.T, data string, g interface{ Validate() error }, p interface{}) {
+	if err := json.Unmarshal([]byte(data), g); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(data), p); err != nil {
+		t.Fatal(err)
+	}
+	got, want := g.Validate(), validator.Validate(p)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%T %s: generated %v, validator.Validate %v", g, data, got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, data := range []string{
+		"{}",
+		` + "`" + `{"ID":"1","Name":"joe","email":"joe@example.com","Age":30,"Score":5,"Quota":1,"Admin":true,"Tags":["a"],"Nick":"jo","Password":"secret123","Address":{"City":"x","Zip":"12345"},"Previous":[{"City":"y","Zip":"12345"}],"Home":{"City":"z","Zip":"12345"},"Homes":{"a":{"City":"w","Zip":"12345"}},"Others":[{"City":"v","Zip":"12345"}],"Extra":1}` + "`" + `,
+		` + "`" + `{"Name":"a name longer than twenty letters","email":"joe","Age":12,"Score":11,"Quota":18446744073709551615,"Tags":["a","b","c","d"],"Nick":"","Password":"short","Address":{"Zip":"1"},"Previous":[{"City":"y"}],"Home":{},"Homes":{"a":{"Zip":"1"}},"Others":[{},null]}` + "`" + `,
+	} {
+		check(t, data, &User{}, &plain.User{})
+	}
+	for _, data := range []string{"{}", ` + "`" + `{"City":"x","Zip":"12345"}` + "`" + `, ` + "`" + `{"Zip":"123456"}` + "`" + `} {
+		check(t, data, &Address{}, &plain.Address{})
+	}
+	for _, data := range []string{"{}", ` + "`" + `{"ID":"1"}` + "`" + `} {
+		check(t, data, &Base{}, &plain.Base{})
+	}
+}
+`
+
+func (s *GenSuite) TestGenerateRoundTrip(c *C) {
+	if testing.Short() {
+		c.Skip("builds and tests the generated code")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		c.Skip("go command not found")
+	}
+	g := &generator{tag: "validate", importPath: "github.com/movio/validator"}
+	c.Assert(g.parseDir("testdata"), IsNil)
+	src, err := g.generate(nil)
+	c.Assert(err, IsNil)
+	types, err := ioutil.ReadFile(filepath.Join("testdata", "user.go"))
+	c.Assert(err, IsNil)
+
+	// the packages are written inside this one for the go command to
+	// resolve the validator package as it does for it
+	dir, err := ioutil.TempDir("testdata", "roundtrip")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	plain, gen := filepath.Join(dir, "plain"), filepath.Join(dir, "gen")
+	c.Assert(os.Mkdir(plain, 0755), IsNil)
+	c.Assert(os.Mkdir(gen, 0755), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(plain, "user.go"), types, 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(gen, "user.go"), types, 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(gen, "validator_gen.go"), src, 0644), IsNil)
+
+	cmd := exec.Command("go", "list", ".")
+	cmd.Dir = plain
+	out, err := cmd.CombinedOutput()
+	c.Assert(err, IsNil, Commentf("%s", out))
+	test := strings.Replace(roundTripTest, "PLAIN", strings.TrimSpace(string(out)), 1)
+	c.Assert(ioutil.WriteFile(filepath.Join(gen, "roundtrip_test.go"), []byte(test), 0644), IsNil)
+
+	cmd = exec.Command("go", "test", ".")
+	cmd.Dir = gen
+	out, err = cmd.CombinedOutput()
+	c.Assert(err, IsNil, Commentf("%s", out))
+}
@@ -0,0 +1,5 @@
+package bad
+
+type Bad struct {
+	Code string `validate:"regexp=^[a-z"`
+}
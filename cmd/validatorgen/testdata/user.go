@@ -0,0 +1,32 @@
+package user
+
+type Email string
+
+type Base struct {
+	ID string `validate:"nonzero"`
+}
+
+type User struct {
+	Base
+	Name     string   `validate:"nonzero,max=20"`
+	Email    Email    `json:"email" validate:"min=3,regexp=^[^@]+@[^@]+$"`
+	Age      int      `validate:"min=18,max=130"`
+	Score    float64  `validate:"max=10"`
+	Quota    uint64   `validate:"max=10000000000000000000"`
+	Admin    bool     `validate:"nonzero"`
+	Tags     []string `validate:"max=3"`
+	Nick     *string  `validate:"nonzero"`
+	Password string   `validate:"min=8 ~ too short"`
+	Address  Address
+	Previous []Address
+	Home     *Address
+	Homes    map[string]Address
+	Others   []*Address
+	Extra    interface{}
+	secret   string
+}
+
+type Address struct {
+	City string `validate:"nonzero"`
+	Zip  string `validate:"len=5,custom"`
+}
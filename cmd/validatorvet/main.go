@@ -0,0 +1,30 @@
+// Command validatorvet checks validation tags statically
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command validatorvet checks the validation tags of struct fields with
+// the analyzer of package validatorvet. It is meant to be run by go vet:
+//
+//	go vet -vettool=$(which validatorvet) ./...
+package main
+
+import (
+	"github.com/movio/validator/validatorvet"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(validatorvet.Analyzer)
+}
@@ -0,0 +1,96 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+// Codes of the errors of the builtin rules, returned by the Code method
+// of TextErr. Unlike messages, which may change, be translated or be
+// formatted, codes are stable, so that clients may branch on them. The
+// errors of a rule share its code, e.g. ErrMinString and ErrMinInt have
+// CodeMin.
+const (
+	CodeZeroValue     = "zero_value"
+	CodeRequired      = "required"
+	CodeMin           = "min"
+	CodeMax           = "max"
+	CodeLen           = "len"
+	CodeRegexp        = "regexp"
+	CodeBefore        = "before"
+	CodeAfter         = "after"
+	CodeDatetime      = "datetime"
+	CodeGreaterThan   = "greater_than"
+	CodeLessThan      = "less_than"
+	CodeEqual         = "equal"
+	CodeNotEqual      = "not_equal"
+	CodeMultipleOf    = "multiple_of"
+	CodeInvalid       = "invalid"
+	CodeUnsupported   = "unsupported"
+	CodeBadParameter  = "bad_parameter"
+	CodeUnknownTag    = "unknown_tag"
+	CodeTimeout       = "timeout"
+	CodeUnset         = "unset"
+	CodeCharacters    = "characters"
+	CodeLowercase     = "lowercase"
+	CodeUppercase     = "uppercase"
+	CodeStartsWith    = "starts_with"
+	CodeEndsWith      = "ends_with"
+	CodeContains      = "contains"
+	CodeExcludes      = "excludes"
+	CodeExcludesAll   = "excludes_all"
+	CodeNumeric       = "numeric"
+	CodeDecimals      = "decimals"
+	CodeBoolean       = "boolean"
+	CodeUTF8          = "utf8"
+	CodeControl       = "control"
+	CodeHTML          = "html"
+	CodeMinBytes      = "min_bytes"
+	CodeMaxBytes      = "max_bytes"
+	CodeLenBytes      = "len_bytes"
+	CodeUnique        = "unique"
+	CodeSorted        = "sorted"
+	CodeEncoding      = "encoding"
+	CodeDecodedLength = "decoded_length"
+	CodeJWT           = "jwt"
+	CodeDigest        = "digest"
+	CodeMediaType     = "media_type"
+	CodeDataURI       = "data_uri"
+	CodeJSON          = "json"
+	CodeCreditCard    = "credit_card"
+	CodeIBAN          = "iban"
+	CodeBIC           = "bic"
+	CodeISBN10        = "isbn10"
+	CodeISBN13        = "isbn13"
+	CodeISSN          = "issn"
+	CodeEAN           = "ean"
+	CodeSemver        = "semver"
+	CodeColor         = "color"
+	CodeFile          = "file"
+	CodeDir           = "dir"
+	CodeFilePath      = "file_path"
+	CodeLatitude      = "latitude"
+	CodeLongitude     = "longitude"
+	CodeCountryCode   = "country_code"
+	CodeCurrencyCode  = "currency_code"
+	CodeLanguageTag   = "language_tag"
+	CodeHostname      = "hostname"
+	CodeFQDN          = "fqdn"
+	CodeDNSLabel      = "dns_label"
+	CodePort          = "port"
+	CodeHostPort      = "host_port"
+	CodePassword      = "password"
+	CodeE164          = "e164"
+	CodePostcode      = "postcode"
+)
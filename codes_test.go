@@ -0,0 +1,67 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"errors"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+// codes returns the codes of the errors of err, a TextErr or an
+// ErrorArray of them.
+func codes(err error) []string {
+	var codes []string
+	errs, ok := err.(validator.ErrorArray)
+	if !ok {
+		errs = validator.ErrorArray{err}
+	}
+	for _, e := range errs {
+		codes = append(codes, e.(validator.TextErr).Code())
+	}
+	return codes
+}
+
+func (ms *MySuite) TestErrorCodes(c *C) {
+	c.Assert(validator.ErrMinString(3, 2).Code(), Equals, validator.CodeMin)
+	c.Assert(validator.ErrMinInt(3, 2).Code(), Equals, validator.CodeMin)
+	c.Assert(validator.ErrZeroValueEmpty.Code(), Equals, validator.CodeZeroValue)
+	c.Assert(validator.ErrRegexpDetailed("^a$").Code(), Equals, validator.CodeRegexp)
+	c.Assert(validator.ErrIBAN.Code(), Equals, validator.CodeIBAN)
+	c.Assert(validator.TextErr{Err: errors.New("custom")}.Code(), Equals, "")
+
+	c.Assert(codes(validator.Valid("", "nonzero,min=3")), DeepEquals, []string{validator.CodeZeroValue, validator.CodeMin})
+	c.Assert(codes(validator.Valid("abc", "max=2,regexp=^[0-9]+$")), DeepEquals, []string{validator.CodeMax, validator.CodeRegexp})
+	c.Assert(codes(validator.Valid(5, "gt=5")), DeepEquals, []string{validator.CodeGreaterThan})
+	c.Assert(codes(validator.Valid("ab", "foo")), DeepEquals, []string{validator.CodeUnknownTag})
+
+	// messages set in tags, translations and formatters keep the codes
+	c.Assert(codes(validator.Valid("", "nonzero ~ name please")), DeepEquals, []string{validator.CodeZeroValue})
+	v := validator.New(
+		validator.WithTranslator(validator.Catalog{"fr": {"min": "au moins {param}"}}),
+		validator.WithLocale("fr"),
+		validator.WithFormatter(validator.FormatterFunc(func(field, rule, param string, value interface{}, err error) (string, bool) {
+			return "too long", rule == "max"
+		})),
+	)
+	err := v.Valid("abcdef", "min=10,max=3")
+	c.Assert(err.(validator.ErrorArray)[0].Error(), Equals, "au moins 10")
+	c.Assert(err.(validator.ErrorArray)[1].Error(), Equals, "too long")
+	c.Assert(codes(err), DeepEquals, []string{validator.CodeMin, validator.CodeMax})
+}
@@ -0,0 +1,161 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ErrTimeout is the error returned when a validation function set with
+// SetValidationFuncTimeout does not complete within its timeout.
+var ErrTimeout = TextErr{coded(CodeTimeout, "validation timed out")}
+
+// ValidationFuncCtx is a ValidationFunc that also receives the context
+// of the validation, e.g. to respect its deadline or to read request
+// scoped values from it.
+type ValidationFuncCtx func(ctx context.Context, v interface{}, param string) error
+
+// SetValidationFuncCtx sets the context aware function to be used for
+// a given validation constraint. Calling this function with nil vf
+// is the same as removing the constraint function from the list.
+func SetValidationFuncCtx(name string, vf ValidationFuncCtx) error {
+	return defaultValidator.SetValidationFuncCtx(name, vf)
+}
+
+// SetValidationFuncCtx sets the context aware function to be used for
+// a given validation constraint. Calling this function with nil vf
+// is the same as removing the constraint function from the list.
+func (mv *Validator) SetValidationFuncCtx(name string, vf ValidationFuncCtx) error {
+	if name == "" {
+		return errors.New("name cannot be empty")
+	}
+	mv.lock.Lock()
+	defer mv.lock.Unlock()
+	delete(mv.validationFuncs, name)
+	delete(mv.aliases, name)
+	mv.tagsCache.reset()
+	mv.structCache.reset()
+	if vf == nil {
+		delete(mv.validationFuncsCtx, name)
+		return nil
+	}
+	mv.validationFuncsCtx[name] = vf
+	return nil
+}
+
+// SetValidationFuncTimeout sets a context aware function, typically a slow
+// or remote check such as a DNS lookup, that is given at most timeout to
+// complete. The context passed to vf is cancelled once the timeout expires
+// and the validation then fails with ErrTimeout without waiting any longer
+// for vf to return.
+func SetValidationFuncTimeout(name string, vf ValidationFuncCtx, timeout time.Duration) error {
+	return defaultValidator.SetValidationFuncTimeout(name, vf, timeout)
+}
+
+// SetValidationFuncTimeout sets a context aware function, typically a slow
+// or remote check such as a DNS lookup, that is given at most timeout to
+// complete. The context passed to vf is cancelled once the timeout expires
+// and the validation then fails with ErrTimeout without waiting any longer
+// for vf to return.
+func (mv *Validator) SetValidationFuncTimeout(name string, vf ValidationFuncCtx, timeout time.Duration) error {
+	if vf == nil {
+		return mv.SetValidationFuncCtx(name, nil)
+	}
+	return mv.SetValidationFuncCtx(name, withTimeout(vf, timeout))
+}
+
+// withTimeout wraps vf so that it fails with ErrTimeout when it does not
+// complete within timeout.
+func withTimeout(vf ValidationFuncCtx, timeout time.Duration) ValidationFuncCtx {
+	return func(ctx context.Context, v interface{}, param string) error {
+		tctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		done := make(chan error, 1)
+		go func() {
+			done <- vf(tctx, v, param)
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-tctx.Done():
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return ErrTimeout
+		}
+	}
+}
+
+// ValidateContext validates the fields of a struct like Validate,
+// passing ctx to context aware validation functions. Validation stops
+// when ctx is done, in which case the error of ctx is returned.
+func ValidateContext(ctx context.Context, v interface{}, opts ...Option) error {
+	return defaultValidator.ValidateContext(ctx, v, opts...)
+}
+
+// ValidateContext validates the fields of a struct like Validate,
+// passing ctx to context aware validation functions. Validation stops
+// when ctx is done, in which case the error of ctx is returned.
+func (mv *Validator) ValidateContext(ctx context.Context, v interface{}, opts ...Option) error {
+	return mv.with(opts...).validate(ctx, v)
+}
+
+// ValidContext validates a value like Valid, passing ctx to context
+// aware validation functions.
+func ValidContext(ctx context.Context, val interface{}, tags string) error {
+	return defaultValidator.ValidContext(ctx, val, tags)
+}
+
+// ValidContext validates a value like Valid, passing ctx to context
+// aware validation functions.
+func (mv *Validator) ValidContext(ctx context.Context, val interface{}, tags string) error {
+	return mv.valid(ctx, val, tags)
+}
+
+// parentKey is the context key of the struct holding the field being
+// validated.
+type parentKey struct{}
+
+// parent describes the field being validated and the struct sv holding
+// it.
+type parent struct {
+	sv    reflect.Value
+	field reflect.StructField
+}
+
+// withParent returns the context given to the context aware validation
+// functions of the field f of the struct sv.
+func withParent(ctx context.Context, sv reflect.Value, f reflect.StructField) context.Context {
+	return context.WithValue(ctx, parentKey{}, parent{sv, f})
+}
+
+// parentField returns the value of the field named name of the struct
+// holding the field being validated, if any.
+func parentField(ctx context.Context, name string) (interface{}, bool) {
+	p, ok := ctx.Value(parentKey{}).(parent)
+	if !ok {
+		return nil, false
+	}
+	f := p.sv.FieldByName(name)
+	if !f.IsValid() || !f.CanInterface() {
+		return nil, false
+	}
+	return indirect(f.Interface()), true
+}
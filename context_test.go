@@ -0,0 +1,99 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type tenantKey struct{}
+
+var errTaken = errors.New("already taken")
+
+func uniqueForTenant(ctx context.Context, v interface{}, param string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Value(tenantKey{}) == "acme" && v == "taken" {
+		return errTaken
+	}
+	return nil
+}
+
+func (ms *MySuite) TestValidateContext(c *C) {
+	type test struct {
+		Name string `validate:"nonzero,unique"`
+	}
+	v := validator.NewValidator()
+	err := v.SetValidationFuncCtx("unique", uniqueForTenant)
+	c.Assert(err, IsNil)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	err = v.ValidateContext(ctx, test{"taken"})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"], HasError, errTaken)
+
+	err = v.ValidateContext(context.Background(), test{"taken"})
+	c.Assert(err, IsNil)
+
+	err = v.Validate(test{"taken"})
+	c.Assert(err, IsNil)
+
+	err = v.ValidContext(ctx, "taken", "unique")
+	c.Assert(err, NotNil)
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = v.ValidateContext(cctx, test{"free"})
+	c.Assert(err, Equals, context.Canceled)
+
+	// a plain validation func replaces the context aware one
+	v.SetValidationFunc("unique", func(interface{}, string) error { return nil })
+	err = v.ValidateContext(ctx, test{"taken"})
+	c.Assert(err, IsNil)
+}
+
+func (ms *MySuite) TestValidationFuncTimeout(c *C) {
+	type test struct {
+		Email string `validate:"mx"`
+		Host  string `validate:"blocklist"`
+	}
+	v := validator.NewValidator()
+	v.SetValidationFuncTimeout("mx", func(ctx context.Context, _ interface{}, _ string) error {
+		<-ctx.Done()
+		return errors.New("should not be reported")
+	}, 10*time.Millisecond)
+	v.SetValidationFuncTimeout("blocklist", func(ctx context.Context, v interface{}, _ string) error {
+		if v == "evil.com" {
+			return errTaken
+		}
+		return nil
+	}, time.Second)
+
+	err := v.Validate(test{"joe@example.com", "evil.com"})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Email"], HasError, validator.ErrTimeout)
+	c.Assert(errs["Host"], HasError, errTaken)
+}
@@ -0,0 +1,75 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+)
+
+// CustomTypeFunc returns the value to validate in place of field, a
+// value of a custom type, e.g. the underlying value of a wrapper type.
+type CustomTypeFunc func(field reflect.Value) interface{}
+
+// RegisterCustomTypeFunc registers fn as the function returning the
+// values to validate in place of the values of the types of the given
+// values, e.g. the decimal of a decimal.Decimal or the string of a
+// null.String, before their rules are applied.
+//
+//	validator.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+//		if s := field.Interface().(null.String); s.Valid {
+//			return s.String
+//		}
+//		return nil
+//	}, null.String{})
+func RegisterCustomTypeFunc(fn CustomTypeFunc, types ...interface{}) {
+	defaultValidator.RegisterCustomTypeFunc(fn, types...)
+}
+
+// RegisterCustomTypeFunc registers fn as the function returning the
+// values to validate in place of the values of the types of the given
+// values, e.g. the decimal of a decimal.Decimal or the string of a
+// null.String, before their rules are applied.
+func (mv *Validator) RegisterCustomTypeFunc(fn CustomTypeFunc, types ...interface{}) {
+	mv.lock.Lock()
+	defer mv.lock.Unlock()
+	if mv.customTypeFuncs == nil {
+		mv.customTypeFuncs = map[reflect.Type]CustomTypeFunc{}
+	}
+	for _, t := range types {
+		mv.customTypeFuncs[reflect.TypeOf(t)] = fn
+	}
+}
+
+// customTypeValue returns the value to validate in place of v, or of
+// the value it points to, if a CustomTypeFunc is registered for its type.
+func (mv *Validator) customTypeValue(v reflect.Value) (interface{}, bool) {
+	mv.lock.RLock()
+	defer mv.lock.RUnlock()
+	if len(mv.customTypeFuncs) == 0 {
+		return nil, false
+	}
+	for v.IsValid() {
+		if fn, ok := mv.customTypeFuncs[v.Type()]; ok {
+			return fn(v), true
+		}
+		if v.Kind() != reflect.Ptr || v.IsNil() {
+			break
+		}
+		v = v.Elem()
+	}
+	return nil, false
+}
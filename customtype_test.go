@@ -0,0 +1,67 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"reflect"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type nullString struct {
+	String string
+	Valid  bool
+}
+
+type wrappedInt struct {
+	value int
+}
+
+type customTypeRequest struct {
+	Name  nullString  `validate:"nonzero,max=5"`
+	Nick  *nullString `validate:"max=5"`
+	Count wrappedInt  `validate:"min=1"`
+}
+
+func (ms *MySuite) TestRegisterCustomTypeFunc(c *C) {
+	v := validator.New()
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if s := field.Interface().(nullString); s.Valid {
+			return s.String
+		}
+		return nil
+	}, nullString{})
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(wrappedInt).value
+	}, wrappedInt{})
+
+	c.Assert(v.Validate(customTypeRequest{Name: nullString{"Joe", true}, Count: wrappedInt{2}}), IsNil)
+
+	errs, ok := v.Validate(customTypeRequest{
+		Nick: &nullString{"Joe the third", true},
+	}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["Nick"], HasError, validator.ErrMaxString(5, 13))
+	c.Assert(errs["Count"], HasError, validator.ErrMinInt(1, 0))
+
+	c.Assert(v.Valid(wrappedInt{3}, "max=2"), HasError, validator.ErrMaxInt(2, 3))
+	// the default validator is left untouched
+	c.Assert(validator.Valid(wrappedInt{3}, "max=2"), HasError, validator.ErrUnsupported)
+}
@@ -0,0 +1,66 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"strings"
+)
+
+// defaultTag is the name of the struct tag holding the default values
+// of fields.
+const defaultTag = "default"
+
+// parseDefault returns the values setting a field of type t to its
+// default value, given as text in t, the elements of slices being
+// separated by commas.
+func parseDefault(t reflect.Type, def string) ([]string, error) {
+	values := []string{def}
+	et := t
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if et.Kind() == reflect.Slice && !reflect.PtrTo(et).Implements(textUnmarshalerType) {
+		values = strings.Split(def, ",")
+	}
+	if err := setFormValue(reflect.New(t).Elem(), values); err != nil {
+		if err == ErrInvalid {
+			err = ErrBadParameter
+		}
+		return nil, err
+	}
+	return values, nil
+}
+
+// setDefault sets f, if it can be set and is zero, to its default value.
+func setDefault(f reflect.Value, values []string) {
+	if f.CanSet() && isZeroValue(f) {
+		setFormValue(f, values)
+	}
+}
+
+// isZeroValue reports whether v is the zero value of its type, nonzero
+// failing for empty strings, slices and maps.
+func isZeroValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.Type().Comparable() && v.Interface() == reflect.Zero(v.Type()).Interface()
+}
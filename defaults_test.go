@@ -0,0 +1,75 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"time"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type defaulted struct {
+	Limit   int           `default:"50" validate:"max=500"`
+	Sort    string        `default:"name" validate:"nonzero"`
+	Order   *string       `default:"asc" sanitize:"toupper"`
+	Tags    []string      `default:"a,b"`
+	Timeout time.Duration `default:"1m"`
+	Since   time.Time     `default:"2020-01-02T00:00:00Z"`
+	Exact   bool          `default:"true"`
+}
+
+func (ms *MySuite) TestDefault(c *C) {
+	var d defaulted
+	c.Assert(validator.Validate(&d), IsNil)
+	c.Assert(d.Limit, Equals, 50)
+	c.Assert(d.Sort, Equals, "name")
+	c.Assert(*d.Order, Equals, "ASC")
+	c.Assert(d.Tags, DeepEquals, []string{"a", "b"})
+	c.Assert(d.Timeout, Equals, time.Minute)
+	c.Assert(d.Since.Year(), Equals, 2020)
+	c.Assert(d.Exact, Equals, true)
+
+	// values set are kept
+	order := "desc"
+	d = defaulted{Limit: 600, Sort: "date", Order: &order, Tags: []string{"c"}}
+	errs, ok := validator.Validate(&d).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Limit"], HasError, validator.ErrMaxInt(500, 600))
+	c.Assert(d.Sort, Equals, "date")
+	c.Assert(order, Equals, "DESC")
+	c.Assert(d.Tags, DeepEquals, []string{"c"})
+
+	// values which cannot be set are validated as they are
+	errs, ok = validator.Validate(defaulted{}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Sort"], HasError, validator.ErrZeroValueEmpty)
+}
+
+func (ms *MySuite) TestDefaultErrors(c *C) {
+	type test struct {
+		A int      `default:"abc"`
+		B struct{} `default:"x"`
+	}
+	var t test
+	errs, ok := validator.Validate(&t).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrBadParameter)
+	c.Assert(errs["B"], HasError, validator.ErrUnsupported)
+	c.Assert(t.A, Equals, 0)
+}
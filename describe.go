@@ -0,0 +1,171 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// FieldRules describes the rules of a field of a struct.
+type FieldRules struct {
+	// Path is the path of the field, as the errors of the field are
+	// indexed by but without indexes or map keys, e.g. Users.Address.City.
+	Path  string
+	Rules []Rule
+}
+
+// Rule describes a validation rule.
+type Rule struct {
+	Name  string `json:"rule"`
+	Param string `json:"param,omitempty"`
+	// Warn reports whether the errors of the rule are warnings.
+	Warn bool `json:"warn,omitempty"`
+}
+
+// String returns the rule as written in a struct tag, its parameter
+// being quoted or escaped as needed.
+func (r Rule) String() string {
+	s := r.Name
+	if r.Param != "" {
+		s += "=" + quoteParam(r.Param)
+	}
+	if r.Warn {
+		s += warnSuffix
+	}
+	return s
+}
+
+// ParseTag returns the rules of tags, written as in a struct tag, without
+// looking them up. It returns an error if tags cannot be parsed.
+func ParseTag(tags string) ([]Rule, error) {
+	items, err := splitTag(tags)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]Rule, len(items))
+	for i, item := range items {
+		if item.name == "" {
+			return nil, ErrUnknownTag
+		}
+		rules[i] = Rule{Name: item.name, Param: item.param, Warn: item.warn}
+	}
+	return rules, nil
+}
+
+// Describe returns the rules applied to the fields of the struct v, or
+// of the struct v points to, including those of the structs it holds.
+// Fields without rules are left out.
+func Describe(v interface{}) ([]FieldRules, error) {
+	return defaultValidator.Describe(v)
+}
+
+// Describe returns the rules applied to the fields of the struct v, or
+// of the struct v points to, including those of the structs it holds.
+// Fields without rules are left out.
+func (mv *Validator) Describe(v interface{}) ([]FieldRules, error) {
+	t, err := structType(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields []FieldRules
+	err = mv.describeStruct(t, "", map[reflect.Type]bool{}, &fields)
+	return fields, err
+}
+
+// Manifest returns the rules applied to the fields of the struct v, or
+// of the struct v points to, as a JSON object mapping the path of each
+// field to its rules, e.g. {"Name":[{"rule":"max","param":"40"}]}, for
+// forms to mirror the validation client-side.
+func Manifest(v interface{}) ([]byte, error) {
+	return defaultValidator.Manifest(v)
+}
+
+// Manifest returns the rules applied to the fields of the struct v, or
+// of the struct v points to, as a JSON object mapping the path of each
+// field to its rules, e.g. {"Name":[{"rule":"max","param":"40"}]}, for
+// forms to mirror the validation client-side.
+func (mv *Validator) Manifest(v interface{}) ([]byte, error) {
+	fields, err := mv.Describe(v)
+	if err != nil {
+		return nil, err
+	}
+	return manifest(fields)
+}
+
+// manifest returns fields as a JSON object mapping their path to their
+// rules.
+func manifest(fields []FieldRules) ([]byte, error) {
+	m := make(map[string][]Rule, len(fields))
+	for _, f := range fields {
+		m[f.Path] = f.Rules
+	}
+	return json.Marshal(m)
+}
+
+// describeStruct adds the rules of the fields of the struct type t to
+// fields. seen holds the struct types being described, as the struct
+// types holding themselves are only described once.
+func (mv *Validator) describeStruct(t reflect.Type, path string, seen map[reflect.Type]bool, fields *[]FieldRules) error {
+	seen[t] = true
+	defer delete(seen, t)
+	for _, sf := range mv.structFields(t) {
+		if sf.err != nil {
+			return sf.err
+		}
+		name, errName := mv.fieldNames(sf)
+		if len(sf.tags) > 0 {
+			rules := make([]Rule, len(sf.tags))
+			for i, t := range sf.tags {
+				rules[i] = Rule{Name: t.Name, Param: t.Param, Warn: t.Warn}
+			}
+			*fields = append(*fields, FieldRules{Path: joinPath(path, errName), Rules: rules})
+		}
+		if ft := nestedStruct(sf); ft != nil && !seen[ft] {
+			if err := mv.describeStruct(ft, mv.nestedPath(sf, path, name), seen, fields); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nestedStruct returns the type of the structs held by the field sf whose
+// fields are validated, nil if there are none.
+func nestedStruct(sf structField) reflect.Type {
+	if !sf.descend {
+		return nil
+	}
+	ft := sf.field.Type
+	for {
+		switch ft.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			ft = ft.Elem()
+			continue
+		}
+		break
+	}
+	if ft.Kind() != reflect.Struct {
+		return nil
+	}
+	// Validatable values validate themselves, without their tags,
+	// unless the field has the nostructlevel modifier.
+	if !sf.noStructLevel && isValidatable(ft) {
+		return nil
+	}
+	return ft
+}
@@ -0,0 +1,76 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestDescribe(c *C) {
+	fields, err := validator.Describe(&schemaUser{})
+	c.Assert(err, IsNil)
+	c.Assert(fields, DeepEquals, []validator.FieldRules{
+		{Path: "schemaBase.id", Rules: []validator.Rule{{Name: "min", Param: "1"}}},
+		{Path: "name", Rules: []validator.Rule{{Name: "nonzero"}, {Name: "max", Param: "40"}}},
+		{Path: "Code", Rules: []validator.Rule{{Name: "len", Param: "3"}, {Name: "regexp", Param: "^[A-Z]+$"}}},
+		{Path: "age", Rules: []validator.Rule{{Name: "min", Param: "18"}, {Name: "max", Param: "130"}}},
+		{Path: "tags", Rules: []validator.Rule{{Name: "max", Param: "5"}}},
+		{Path: "address", Rules: []validator.Rule{{Name: "nonzero"}}},
+		{Path: "Address.city", Rules: []validator.Rule{{Name: "nonzero"}}},
+		{Path: "Previous.city", Rules: []validator.Rule{{Name: "nonzero"}}},
+	})
+
+	_, err = validator.Describe([]int{})
+	c.Assert(err, Equals, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestManifest(c *C) {
+	b, err := validator.Manifest(schemaAddress{})
+	c.Assert(err, IsNil)
+	c.Assert(string(b), Equals, `{"city":[{"rule":"nonzero"}]}`)
+
+	b, err = validator.Manifest(struct {
+		Name string `validate:"min=3,max=40"`
+		Age  int    `json:"age" validate:"min=18"`
+	}{})
+	c.Assert(err, IsNil)
+	c.Assert(string(b), Equals, `{"Name":[{"rule":"min","param":"3"},{"rule":"max","param":"40"}],"age":[{"rule":"min","param":"18"}]}`)
+}
+
+func (ms *MySuite) TestParseTag(c *C) {
+	rules, err := validator.ParseTag("nonzero, max=5@warn ~ too long,regexp=^[a-z]{1\\,3}$,excludesall=' '")
+	c.Assert(err, IsNil)
+	c.Assert(rules, DeepEquals, []validator.Rule{
+		{Name: "nonzero"},
+		{Name: "max", Param: "5", Warn: true},
+		{Name: "regexp", Param: "^[a-z]{1,3}$"},
+		{Name: "excludesall", Param: " "},
+	})
+	for _, r := range rules {
+		parsed, err := validator.ParseTag(r.String())
+		c.Assert(err, IsNil)
+		c.Assert(parsed, DeepEquals, []validator.Rule{r})
+	}
+	c.Assert(rules[2].String(), Equals, `regexp=^[a-z]{1\,3}$`)
+
+	_, err = validator.ParseTag("min=3,")
+	c.Assert(err, Equals, validator.ErrUnknownTag)
+	_, err = validator.ParseTag("regexp='^a")
+	c.Assert(err, Equals, validator.ErrBadParameter)
+}
@@ -49,37 +49,512 @@ Then validating a variable of type NewUserRequest becomes trivial.
 		// do something
 	}
 
+The error returned is an ErrorMap indexed by the path of each failing field.
+Nested structs are validated too, including those held in slices, arrays
+and maps, in which case the path includes the index or key of the element.
+
+	type Team struct {
+		Users []User
+	}
+	// errs["Users[2].Address.City"] holds the errors of the city of
+	// the third user.
+
+The fields of embedded structs, exported or not, are validated like those
+of any nested struct, their errors being indexed below the name of their
+type, e.g. Base.ID. With WithPromotedFields, they are indexed by their
+promoted name instead, e.g. ID, as encoding/json names them. Unexported
+fields are not validated.
+
+The rules of pointer fields apply to the values they point to. Nil
+pointers are left alone by the rules not applying to them, such as min or
+len, unless the WithNilAsZero option is given, with which they are
+validated as the zero value of the type they point to.
+
+The rules of interface fields apply to the value they hold, and the
+structs they hold, directly or in slices and maps, are validated with the
+tags of their own type. The nodynamic modifier leaves the value held by
+an interface field alone, only the other rules of the field applying.
+
+	type Order struct {
+		Payment PaymentMethod `validate:"required"`           // e.g. *Card, validated
+		Source  interface{}   `validate:"required,nodynamic"` // not walked
+	}
+
+Fields are validated in the order they are declared, elements in the order
+of their index or key, and the errors of a field are in the order of its
+rules, so that the errors kept by WithMaxErrors are always the same. The
+paths of an ErrorMap are returned in a stable order by Paths.
+
+Errors can be consumed without walking nested ErrorMaps and ErrorArrays with
+Flatten, returning them in that order along with their full paths. Filter
+keeps the paths of an ErrorMap matching a pattern, as for Except, in which
+** also matches dots, and Dedupe drops the errors repeated for a path, e.g.
+by rules shared by an alias and a tag.
+
+	for _, e := range errs.Filter("Users[*].**").Flatten() {
+		log.Printf("%s: %v", e.Path, e.Err)
+	}
+
+ValidateErrors validates like Validate but returns the errors found as a
+ValidationErrors slice, nil if the value is valid, each error carrying its
+path and the rule returning it, so that they can be inspected without type
+assertions. Validate still returns an ErrorMap, which AsValidationErrors
+converts, and ErrorMap converts back.
+
+	if errs := validator.ValidateErrors(user); errs != nil {
+		if errs.ByField("Email").HasRule("email") {
+			...
+		}
+		return errs.First()
+	}
+
+Some of the fields of a struct can be validated alone with ValidateFields,
+e.g. for a PATCH request only carrying some of them, or all but some of them
+with ValidateFieldsExcept. Fields are given by their path, with or without
+indexes.
+
+	errs := validator.ValidateFields(user, "Name", "Address.City")
+
+Fields can also be excluded from a single call with the Except option, by
+their path or by a pattern in which * matches any field name, e.g. for data
+from a trusted source with legacy fields known to be invalid.
+
+	errs := validator.Validate(user, validator.Except("Password", "Secrets.*"))
+
+Conversely, fields can be required in a single call with the WithRequired
+option, on top of the rules of their tags, e.g. when the fields mandatory
+depend on the configuration of a tenant. A required field fails when it
+holds its zero value, as with nonzero, and so does a nil pointer holding it.
+
+	errs := validator.Validate(user, validator.WithRequired("Email", "Address.Zip"))
+
+Updates can be validated with ValidateChanged, which only validates the
+fields whose value differs from the one stored, so that legacy values the
+client did not touch do not fail the update.
+
+	errs := validator.ValidateChanged(stored, updated)
+
+Batches are validated with ValidateSlice, which returns the error of each
+element apart, ErrZeroValue for nil ones.
+
+	errs, err := validator.ValidateSlice(rows)
+	valid, invalid := validator.Partition(errs)
+
+Dynamic payloads without a struct, such as decoded JSON objects, can be
+validated with ValidateMap and rules given per key, nested maps having
+nested rules.
+
+	errs := validator.ValidateMap(payload, validator.MapRules{
+		"name":    "nonzero,max=40",
+		"address": validator.MapRules{"city": "nonzero"},
+	})
+
+By default every rule of every field is validated. Validation can instead
+stop at the first error found with the WithFailFast option, or once a given
+number of errors were found with WithMaxErrors.
+
+	errs := validator.Validate(req, validator.WithFailFast())
+
+The elements of large slices of structs can be validated with several
+goroutines with WithParallelism. The errors found are the same as when
+validating them one after the other, except with WithMaxErrors, under
+which elements are always validated in order.
+
+	errs := validator.Validate(batch, validator.WithParallelism(runtime.NumCPU()))
+
 Builtin validator functions
 
 Here is the list of validator functions builtin in the package.
 
+	after
+		Only valid for time.Time, it checks that the time is after the
+		time given as parameter, either in RFC 3339 format or now,
+		optionally followed by a duration. (Usage: after=now-24h)
+
+	alpha, alphanum, alphaunicode, alphanumunicode
+		Only valid for strings, they check that the string only contains
+		ASCII letters, ASCII letters and digits, or their Unicode
+		counterparts. (Usage: alphanum)
+
+	ascii, printascii
+		Only valid for strings, they check that the string only contains
+		ASCII characters, or printable ones, from space to tilde.
+		(Usage: printascii)
+
+	base32, base64, base64url, hexadecimal
+		Only valid for strings, they check that the string is valid padded
+		base32, padded standard base64, URL-safe base64 with or without
+		padding, or hexadecimal with an even number of digits. The number of
+		bytes the string must decode to may be given as parameter.
+		(Usage: base64, hexadecimal=32)
+
+	bcp47
+		Only valid for strings, it checks that the string is a well-formed
+		BCP 47 language tag, such as en-NZ or zh-Hant-TW, whose two letters
+		language and region, if any, are known ISO codes. (Usage: bcp47)
+
+	before
+		Only valid for time.Time, it checks that the time is before the
+		time given as parameter, as for after. (Usage: before=now)
+
+	between
+		It checks that the value is within the two bounds given as
+		parameter, bounds included: numbers by their value, strings, slices,
+		arrays and maps by their length, as for min and max, and times as
+		for after and before. Bounds are separated by a colon or, if they
+		hold colons, by a space. Validation functions taking several values
+		may follow the same convention with SplitParams.
+		(Usage: between=1:100, between=2000-01-01T00:00:00Z now)
+
+	bic
+		Only valid for strings, it checks that the string is a BIC, or
+		SWIFT code, of 8 or 11 upper case characters. (Usage: bic)
+
+	boolean
+		Only valid for strings, it checks that the string is a boolean as
+		parsed by strconv.ParseBool, e.g. true, false, 1 or 0. (Usage: boolean)
+
+	contains, excludes
+		Only valid for strings, they check that the string contains, or
+		does not contain, the substring given as parameter.
+		(Usage: contains=@, excludes=..)
+
+	credit_card
+		Only valid for strings, it checks that the string is a credit card
+		number passing the Luhn check, ignoring spaces and dashes. The brands
+		accepted may be given as parameter, separated by |, among visa,
+		mastercard, amex, discover, dinersclub, jcb, unionpay and maestro.
+		(Usage: credit_card, credit_card=visa|mastercard)
+
+	datauri
+		Only valid for strings, it checks that the string is a data URI,
+		e.g. data:image/png;base64,iVBORw0KGgo=, with valid base64 or URL
+		escaped data. The media types accepted may be given as for mime.
+		(Usage: datauri=image/*)
+
+	datetime
+		Only valid for strings, it checks that the string is a time
+		formatted with the Go reference layout given as parameter, or
+		one of rfc3339, rfc3339nano, rfc1123, rfc1123z, rfc822, rfc822z,
+		rfc850, ansic, unixdate, kitchen, date (2006-01-02), time
+		(15:04:05) and datetime (2006-01-02 15:04:05).
+		(Usage: datetime=2006-01-02)
+
+	dir, file
+		Only valid for strings, they check that the string is the path of
+		an existing directory, or of an existing file other than a
+		directory. Note that they do I/O, stating the path. (Usage: file)
+
+	dns_label
+		Only valid for strings, it checks that the string is a lower case
+		DNS label as defined by RFC 1123, up to 63 letters, digits and
+		hyphens, as used for the names of Kubernetes resources.
+		(Usage: dns_label)
+
+	e164
+		Only valid for strings, it checks that the string is a phone number
+		in E.164 format, a + followed by 7 to 15 digits. (Usage: e164)
+
+	ean
+		Only valid for strings, it checks that the string is an EAN-8 or
+		EAN-13 barcode number with a valid check digit. (Usage: ean)
+
+	eq, ne
+		For numbers, they check that the value is equal, or not equal, to
+		the parameter given. (Usage: ne=0)
+
+	excludesall
+		Only valid for strings, it checks that the string contains none of
+		the characters given as parameter. (Usage: excludesall=<>)
+
+	filepath
+		Only valid for strings, it checks that the string is a valid path
+		for the current operating system, whether it exists or not, without
+		any I/O. (Usage: filepath)
+
+	fqdn
+		Only valid for strings, it checks that the string is a fully
+		qualified domain name, a hostname of at least two labels with a
+		top-level domain that is not numeric, optionally with a trailing dot.
+		(Usage: fqdn)
+
+	gt, gte
+		For numbers, they check that the value is strictly greater than, or
+		greater than or equal to, the parameter given. Unlike min, they never
+		apply to the length of strings, slices or maps. (Usage: gt=0)
+
+	hexcolor, rgb, rgba, hsl
+		Only valid for strings, they check that the string is a CSS color,
+		either in hex notation, e.g. #1e90ff, or a call of the rgb, rgba or
+		hsl functions, e.g. rgba(30, 144, 255, 0.5) or hsl(210, 100%, 56%).
+		(Usage: hexcolor)
+
+	hostname
+		Only valid for strings, it checks that the string is a hostname as
+		defined by RFC 1123, of labels of up to 63 letters, digits and
+		hyphens separated by dots. (Usage: hostname)
+
+	hostport
+		Only valid for strings, it checks that the string is a host and
+		port pair, whose host is a hostname or an IP address, e.g.
+		example.com:443 or [::1]:8080. (Usage: hostport)
+
+	iban
+		Only valid for strings, it checks that the string is an IBAN of
+		the length used by its country with a valid checksum, ignoring
+		spaces. (Usage: iban)
+
+	isbn10, isbn13
+		Only valid for strings, they check that the string is an ISBN-10 or
+		an ISBN-13 with a valid check digit, ignoring hyphens and spaces.
+		(Usage: isbn13)
+
+	iso3166_alpha2, iso3166_alpha3, iso4217
+		Only valid for strings, they check regardless of case that the
+		string is a known ISO 3166-1 country code, in its two or three
+		letters form, or an ISO 4217 currency code. The codes embedded in
+		the package can be replaced with SetCountryCodes, SetCurrencyCodes
+		and SetLanguageCodes. (Usage: iso3166_alpha2)
+
+	issn
+		Only valid for strings, it checks that the string is an ISSN with a
+		valid check digit, with or without its hyphen. (Usage: issn)
+
+	json
+		For strings and byte slices, such as json.RawMessage, it checks
+		that the value is valid JSON, whose top level is an object or an
+		array if given as parameter. (Usage: json, json=object)
+
+	jwt
+		Only valid for strings, it checks that the string is made of the
+		three base64url encoded parts of a JSON Web Token, whose header and
+		payload are JSON objects, without verifying its signature.
+		(Usage: jwt)
+
+	latitude, longitude
+		For numbers and strings holding a number, they check that the value
+		is a latitude between -90 and 90 or a longitude between -180 and 180.
+		(Usage: latitude)
+
 	len
 		For numeric numbers, len will simply make sure that the value is
 		equal to the parameter given. For strings, it checks that
 		the string length is exactly that number of characters. For slices,
 		arrays, and maps, validates the number of items. (Usage: len=10)
 
+	lenbytes, minbytes, maxbytes
+		Only valid for strings, they check the number of bytes of the
+		string encoded in UTF-8, rather than its number of characters as
+		len, min and max do, e.g. to fit a database column.
+		(Usage: maxbytes=255)
+
+	lowercase, uppercase
+		Only valid for strings, they check that the string holds no upper
+		case, respectively lower case, characters. Characters without case,
+		such as digits, are accepted. (Usage: iso4217,uppercase)
+
+	lt, lte
+		For numbers, they check that the value is strictly lesser than, or
+		lesser than or equal to, the parameter given. Unlike max, they never
+		apply to the length of strings, slices or maps. (Usage: lt=1)
+
 	max
 		For numeric numbers, max will simply make sure that the value is
 		lesser or equal to the parameter given. For strings, it checks that
 		the string length is at most that number of characters. For slices,
-		arrays, and maps, validates the number of items. (Usage: max=10)
+		arrays, and maps, validates the number of items. For time.Duration,
+		the parameter may be a duration string. (Usage: max=10, max=30s)
+
+	md5, sha1, sha256, sha512
+		Only valid for strings, they check that the string is a hex encoded
+		digest of the size computed by the algorithm. (Usage: sha256)
+
+	mime
+		Only valid for strings, it checks that the string is a media type,
+		such as text/html; charset=utf-8, optionally among those given as
+		parameter, separated by |, where image/* stands for all image types.
+		(Usage: mime, mime=image/png|image/jpeg)
 
 	min
 		For numeric numbers, min will simply make sure that the value is
 		greater or equal to the parameter given. For strings, it checks that
 		the string length is at least that number of characters. For slices,
-		arrays, and maps, validates the number of items. (Usage: min=10)
+		arrays, and maps, validates the number of items. For time.Duration,
+		the parameter may be a duration string. (Usage: min=10, min=100ms)
+
+	min, max, gt, gte, lt, lte, eq and ne also compare big.Int, big.Rat,
+	big.Float and decimal types implementing Ratter exactly, with parameters
+	given as integers, decimals or fractions, e.g. max=99.99 or lt=1/3.
+
+	multipleof
+		For numbers, it checks that the value is a multiple of the parameter
+		given. Floats are taken as the shortest decimal representing them,
+		so that 0.15 is a multiple of 0.05. (Usage: multipleof=6)
+
+	nocontrol
+		Only valid for strings, it checks that the string holds no control
+		characters, such as NUL, other than tabs and line breaks.
+		(Usage: nocontrol)
+
+	nohtml
+		Only valid for strings, it checks that the string contains no HTML
+		markup, such as tags, including script tags, or comments, while
+		accepting a lone < as in a < b. (Usage: nohtml)
 
 	nonzero
 		This validates that the value is not zero. The appropriate zero value
 		is given by the Go spec (e.g. for int it's 0, for string it's "", for
-		pointers is nil, etc.) Usage: nonzero
+		pointers is nil, etc.) For time.Time, it checks that the time is
+		not the zero time. Usage: nonzero
+
+	notnil
+		An alias of required. (Usage: notnil)
+
+	numeric
+		Only valid for strings, it checks that the string is a decimal
+		number, with at most the number of decimal places given as
+		parameter, if any. (Usage: numeric, numeric=2)
+
+	password
+		Only valid for strings, it checks that the string is a password at
+		least 8 characters long, unless otherwise given, meeting the options
+		given as parameter, separated by semicolons: min:n and max:n bound
+		its length, lower, upper, digit and symbol require a character of
+		each class and entropy:n requires an estimated entropy of n bits.
+		(Usage: password=min:12;upper;digit;symbol, password=entropy:60)
+
+	port
+		For integers and strings holding one, it checks that the value is a
+		port number between 1 and 65535, or 0 as well when the parameter is
+		any. The same parameter applies to hostport. (Usage: port, port=any)
+
+	postcode_for
+		Only valid for strings, it checks that the string is a postal code
+		of the country held by the field of the same struct given as
+		parameter. Codes of countries without a known pattern are accepted.
+		(Usage: postcode_for=Country)
+
+	postcode_iso3166_alpha2
+		Only valid for strings, it checks that the string is a postal code
+		of the country given as parameter. (Usage: postcode_iso3166_alpha2=US)
 
 	regexp
 		Only valid for string types, it will validate that the value matches
 		the regular expression provided as parameter. (Usage: regexp=^a.*b$)
 
+	required
+		This validates that the value is present, i.e. is not a nil pointer,
+		interface, slice or map, nor null for sql.Null types, unlike nonzero
+		accepting zero values: a *int tagged required may point to 0. With
+		WithNilAsZero, nil pointers are zero values and thus present.
+		(Usage: required)
+
+	semver
+		Only valid for strings, it checks that the string is a SemVer 2.0.0
+		version, optionally with a leading v when the parameter is v.
+		(Usage: semver, semver=v)
+
+	sorted
+		For slices and arrays of numbers, strings or times, it checks that
+		the elements are sorted in ascending order, or descending order
+		when the parameter is desc. (Usage: sorted, sorted=desc)
+
+	startswith, endswith
+		Only valid for strings, they check that the string starts, or ends,
+		with the parameter given. (Usage: startswith=sk_)
+
+	unique
+		For slices, arrays and maps, it checks that the elements, or values,
+		are all different, pointers being compared by the values they point
+		to. For structs, the field to compare may be given as parameter.
+		(Usage: unique, unique=SKU)
+
+	validutf8
+		For strings and byte slices, it checks that the value is valid
+		UTF-8. (Usage: validutf8)
+
+The rules checking the format of strings, such as credit_card, accept empty
+strings, so that optional fields can be validated. Combine them with nonzero
+to require a value.
+
+The message of the error returned by a rule can be replaced by appending it
+to the rule after a tilde. The message may refer to the parameter of the rule
+as {param} and to the value of the field as {value}. As tildes were once part
+of parameters, the tilde starting the message of a rule with an unquoted
+parameter must have spaces around it, other tildes being taken literally,
+e.g. in regexp=^a~b$. A tilde with spaces around it is kept in a parameter
+by escaping it, e.g. regexp=^a \\~ b$.
+
+	Name     string `validate:"nonzero ~ name is required"`
+	Password string `validate:"min=8 ~ password must be at least {param} characters"`
+
+Commas and tildes separate the rules of a tag and their messages. They can
+be used in a parameter or a message by escaping them with a backslash, or
+by enclosing the parameter in single quotes, which also keeps its leading
+and trailing spaces.
+
+	Code  string `validate:"regexp=^[a-z]{2\\,8}$"`
+	Name  string `validate:"regexp='^[a-z]+(, [a-z]+)*$'"`
+	Token string `validate:"excludesall=' \t'"`
+
+Rules can be marked as warnings by appending @warn to them. Their errors do
+not fail validation and are only returned, separately from the other errors,
+by ValidateWithWarnings, e.g. to tell clients about deprecated values which
+are still accepted.
+
+	Bio string `validate:"max=1000,max=255@warn"`
+
+	warnings, err := validator.ValidateWithWarnings(user)
+
+Sets of rules used on many fields can be given a name with RegisterAlias,
+and then be referenced by that name like any other rule.
+
+	validator.RegisterAlias("username", "nonzero,max=20,regexp=^[a-z0-9_]+$")
+
+	type User struct {
+		Name string `validate:"username ~ invalid user name"`
+	}
+
+String fields can be sanitized before their rules are checked with the
+sanitize tag, listing functions applied in turn: trim, tolower, toupper and
+collapse, which trims and replaces runs of white space with a single space.
+Fields are only sanitized when they can be set, i.e. when validating a
+pointer to the struct. More functions, e.g. to normalize Unicode, can be
+registered with RegisterSanitizer.
+
+	type User struct {
+		Email string `sanitize:"trim,tolower" validate:"nonzero"`
+	}
+
+	err := validator.Validate(&user)
+
+Likewise, fields which can be set are set to the value of their default tag
+when they are zero, before they are sanitized and validated. Defaults are
+written as form values are for BindForm, the elements of slices being
+separated by commas.
+
+	type Query struct {
+		Limit int      `default:"50" validate:"max=500"`
+		Sort  []string `default:"name,id"`
+	}
+
+Values implementing driver.Valuer, such as sql.NullString, are validated as
+their value, null values being validated as nil. Only nonzero fails for nil
+values.
+
+	Name sql.NullString `validate:"nonzero,max=50"`
+
+Other wrapper types can be unwrapped by registering a function returning the
+value to validate in their place with RegisterCustomTypeFunc.
+
+	validator.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(decimal.Decimal).String()
+	}, decimal.Decimal{})
+
+Custom scalar types implementing encoding.TextMarshaler, such as UUIDs or
+enums, can be validated as their text with the WithTextMarshaler option.
+
+	v := validator.New(validator.WithTextMarshaler())
 
 Note that there are no tests to prevent conflicting validator parameters. For
 instance, these fields will never be valid.
@@ -150,6 +625,22 @@ As well, it is possible to overwrite builtin validation functions.
 
 	validate.SetValidationFunc("min", myMinFunc)
 
+This is how format rules can be made stricter, e.g. e164 only checks the
+format of phone numbers, which can be checked against the numbering plan of
+their country with libphonenumber.
+
+	validator.SetValidationFunc("e164", func(v interface{}, param string) error {
+		s, ok := v.(string)
+		if !ok {
+			return validator.ErrUnsupported
+		}
+		n, err := phonenumbers.Parse(s, "")
+		if err != nil || !phonenumbers.IsValidNumber(n) {
+			return validator.ErrE164
+		}
+		return nil
+	})
+
 And you can delete a validation function by setting it to nil.
 
 	validate.SetValidationFunc("notzz", nil)
@@ -158,6 +649,39 @@ And you can delete a validation function by setting it to nil.
 Using a non-existing validation func in a field tag will always return
 false and with error validate.ErrUnknownTag.
 
+Functions of rules taking several values can be set with
+SetValidationFuncParams to receive them split by SplitParams: separated by
+colons or, if they hold colons, by whitespace, a backslash taking the
+character following it literally.
+
+	validator.SetValidationFuncParams("oneof", func(v interface{}, params []string) error {
+		// params holds red, green and light:blue for oneof=red:green:light\\:blue
+	})
+
+Validation functions needing the context of the validation, e.g. to respect
+a deadline when querying a database or to read request scoped values, can be
+set with SetValidationFuncCtx and receive the context given to ValidateContext.
+
+	validator.SetValidationFuncCtx("unique", func(ctx context.Context, v interface{}, param string) error {
+		return db.CheckUnique(ctx, param, v)
+	})
+	errs := validator.ValidateContext(r.Context(), user)
+
+Slow or remote checks can be bounded with SetValidationFuncTimeout. When such
+a function does not complete in time, its context is cancelled and the field
+gets ErrTimeout instead of blocking the whole validation.
+
+	validator.SetValidationFuncTimeout("mx", lookupMX, 2*time.Second)
+
+Functions set with SetValidationFuncField receive a FieldContext giving,
+besides the value and the parameter, the field being validated, the struct
+holding it and the struct given to Validate, e.g. to compare fields.
+
+	validator.SetValidationFuncField("gtefield", func(fc validator.FieldContext) error {
+		other := reflect.ValueOf(fc.Parent()).FieldByName(fc.Param())
+		// compare fc.Value() with other, naming fc.FieldName() in the error
+	})
+
 Finally, package validator also provides a helper function that can be used
 to validate simple variables/values.
 
@@ -170,6 +694,210 @@ to validate simple variables/values.
 	// errs: [validate.ErrMin,validate.ErrMax]
 	errs = validator.Valid("hi", "nonzero,min=3,max=2")
 
+The rules of such values can also be built in code with Value, which
+returns the same errors.
+
+	errs = validator.Value(name).NonZero().Max(40).Rules("nocontrol").Error()
+
+Struct-level validation
+
+Rules spanning several fields of a struct can be expressed with a struct
+validation function, registered for the types it validates. It is called
+with the struct every time a value of that type is validated and the errors
+it returns are merged into the ErrorMap. An ErrorMap returned by the function
+is merged below the path of the struct, so its keys can be virtual field
+names.
+
+	validator.RegisterStructValidation(func(v interface{}) error {
+		p := v.(Payment)
+		if (p.CardToken == "") == (p.BankAccount == "") {
+			return validator.ErrorMap{"Method": {errors.New("exactly one payment method must be set")}}
+		}
+		return nil
+	}, Payment{})
+
+Types may also carry their own validation by implementing Validatable. When
+a nested value implements it, its Validate method is called instead of
+validating its fields and the errors it returns are merged under the path of
+the value. Elements of slices, arrays and maps implementing it are validated
+the same way. A Validate method promoted from an embedded field is not called
+for the struct embedding it, whose fields are validated by their tags.
+
+	func (m Money) Validate() error {
+		if m.Cents < 0 {
+			return errors.New("amount cannot be negative")
+		}
+		return nil
+	}
+
+Types defined elsewhere, such as enums, can be validated wherever they appear
+in the structs validated, whatever the tags of the fields holding them, with
+a type validation function. The values are found as nested structs are.
+
+	validator.RegisterTypeValidation(func(v interface{}) error {
+		if s := v.(Status); s < Active || s > Closed {
+			return validator.ErrInvalid
+		}
+		return nil
+	}, Status(0))
+
+The walk of nested values can be tuned per field with modifiers. A field
+tagged - is skipped entirely. With structonly, the rules of the field apply
+but the structs it holds are not validated. With nostructlevel, the structs
+it holds are validated by the tags of their fields only, without calling
+their struct validation functions or Validate methods.
+
+	type Order struct {
+		Customer *Customer `validate:"structonly,required"`
+		Lines    []Line    `validate:"nostructlevel"`
+		Internal Audit     `validate:"-"`
+	}
+
+The validatorgen command generates such Validate methods from the tags of
+struct types, checking the builtin rules without reflection for fields of
+basic types, strings, slices and maps.
+
+	//go:generate validatorgen -type User,Address
+
+Rules in code
+
+With Go 1.18 or later, rules can also be declared in code with For, the
+fields being referenced by accessor functions checked at compile time. They
+are validated along with the struct tags of the value.
+
+	var userRules = validator.For[User]().
+		Field("Email", func(u User) interface{} { return u.Email }).Rules("nonzero", "max=80").
+		Field("Age", func(u User) interface{} { return u.Age }).Rules("min=18")
+
+	errs := userRules.Validate(user)
+
+Schemas
+
+The rules of a struct can be exported as a JSON Schema document with
+JSONSchema, e.g. to validate forms client-side with the same rules as the
+server. Fields are named after their json tag, nonzero fields are required
+and the builtin rules are converted to the equivalent keywords.
+
+	schema, err := validator.JSONSchema(User{})
+
+OpenAPISchemas likewise returns the schemas of structs as OpenAPI 3
+components, so that API documentation does not drift from validation.
+
+The rules themselves are returned by Describe, for each field path, and
+by Manifest as a compact JSON object for front-end forms.
+
+Compile parses the rules of a struct type, or MapRules, once into a Schema
+which validates many values without looking them up again, and which can be
+shared, described and exported.
+
+	userSchema, err := validator.Compile(User{})
+	...
+	errs := userSchema.Validate(u)
+
+The rules are checked when compiled, rather than when a value is first
+validated: Check returns the unknown rules and those with invalid parameters,
+such as min=abc or regular expressions which do not compile, indexed by the
+path of their field, and MustCompile panics if there are any.
+
+	var userSchema = validator.MustCompile(User{})
+
+The tags can also be checked statically, when vetting, with the analyzer of
+package github.com/movio/validator/validatorvet, run by go vet with the
+validatorvet command. It also reports the rules which do not apply to the
+type of their field, such as hostname on an int.
+
+	go vet -vettool=$(which validatorvet) ./...
+
+Conversely, CompileJSONSchema returns a validator of decoded JSON payloads,
+such as map[string]interface{}, reporting errors as Validate does.
+
+	sv, err := validator.CompileJSONSchema(schema)
+	errs := sv.Validate(json.RawMessage(body))
+
+HTTP requests
+
+BindForm sets the fields of a struct from the form values of a request,
+named after the form tag of the fields, and validates it. Values which
+cannot be converted are reported in the same ErrorMap as the validation
+errors.
+
+	var req SignupRequest
+	if err := validator.BindForm(r, &req); err != nil {
+		// reply with http.StatusBadRequest
+	}
+
+JSONHandler validates JSON bodies before handing the decoded value to the
+next handler, and replies with http.StatusUnprocessableEntity and the errors
+found otherwise.
+
+	http.Handle("/signup", validator.JSONHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := validator.RequestValue(r).(*SignupRequest)
+		// ...
+	}), SignupRequest{}))
+
+Outside of handlers, NewJSONDecoder decodes and validates JSON values in one
+step, the paths of the errors being made of JSON keys, and NewJSONEncoder
+refuses to encode invalid values.
+
+	var req SignupRequest
+	if err := validator.NewJSONDecoder(r).Decode(&req); err != nil {
+		// errs["address.city"] holds the errors of the city, if invalid
+	}
+
+UnmarshalYAML does the same for YAML documents, with the Unmarshal function
+of the YAML package of choice, the paths being made of YAML keys as named by
+YAMLName.
+
+	var cfg Config
+	if err := validator.UnmarshalYAML(yaml.Unmarshal, data, &cfg); err != nil {
+		// errs["database.max_connections"] holds the errors of the field
+	}
+
+ValidateEnv validates configuration structs read from the environment, e.g.
+at startup, indexing the errors by the names of the environment variables
+of the fields, after their env and envPrefix tags. Given os.LookupEnv, it
+also reports the required variables which are not set.
+
+	if err := validator.ValidateEnv(&cfg, os.LookupEnv); err != nil {
+		log.Fatal(err) // e.g. DATABASE_URL: Must be set
+	}
+
+The validate command checks JSON and YAML documents against the tags of a
+Go type, printing the errors with their paths and exiting with status 1 if
+some document is invalid, e.g. in CI checks of data files.
+
+	validate -type github.com/acme/app/config.Config config.yaml
+
+WithObserver sets an Observer notified of every validation, with its type,
+duration and number of errors, and of every rule failing, with the struct
+type and field it belongs to, e.g. to count the rules failing the most.
+
+	v := validator.New(validator.WithObserver(metrics))
+
+ValidateWithTrace validates like Validate and also returns the trace of
+every rule evaluated, with the path of its field, its outcome and its
+duration, to debug why a field of nested structs did or did not fail.
+
+	trace, err := validator.ValidateWithTrace(order)
+	fmt.Print(trace) // e.g. Items[1].Quantity: min=1: failed: ...
+
+Packages can contribute bundles of rules under a namespace by registering
+a Pack, whose rules validators enable with EnablePack and disable with
+DisablePack, the rules being named after the namespace. Enabling fails when
+a rule is named like one already set.
+
+	func init() {
+		validator.RegisterPack(validator.Pack{
+			Namespace: "geo",
+			Funcs:     map[string]validator.ValidationFunc{"latlng": latLng},
+		})
+	}
+
+	validator.EnablePack("geo") // validate:"geo.latlng"
+
+Package grpcvalidator provides gRPC server interceptors validating request
+messages the same way.
+
 Custom tag name
 
 In case there is a reason why one would not wish to use tag 'validate' (maybe due to
@@ -193,6 +921,68 @@ with a new call to SetTag. A way to temporarily use a different tag exists.
 	// But this will go back to using 'validate'
 	validator.Validate(t)
 
+Since SetTag changes the default validator shared by the whole process, code
+hosting structs annotated for different frameworks should rather create one
+validator per tag name.
+
+	bindingValidator := validator.NewValidator(validator.WithTagName("binding"))
+
+Field names
+
+By default, the errors of a field are indexed by its json tag name if it
+has one, or by its field name otherwise. A different naming can be set
+with SetNameFunc or the WithNameFunc option, e.g. to name nested structs
+after their json tag as well.
+
+	v := validator.NewValidator(validator.WithNameFunc(validator.TagNameFunc("json")))
+
+Translations
+
+Error messages can be translated by setting a Translator. Catalog is a
+simple Translator holding message templates indexed by locale and rule name,
+in which {param} is replaced with the parameter of the rule and {value} with
+the value being validated.
+
+	validator.SetTranslator(validator.Catalog{
+		"fr": {
+			"nonzero": "ne doit pas être vide",
+			"min":     "doit être au moins {param}",
+		},
+	})
+	errs := validator.ValidateTranslated(t, "fr")
+
+Messages without a translation are left untouched. A validator can also be
+bound to a locale with the WithLocale option.
+
+Templates, as well as the messages set in tags, may also refer to the length
+of the value as {len} and choose among plural forms by a number, the forms
+following its placeholder and # standing for the number, e.g.
+{param|# caractère|# caractères}. Forms are chosen by the plural rule of the
+language of the locale, English and French being built in; others are set
+with SetPluralRule. The default messages are pluralized the same way.
+
+The messages of the other errors can be formatted by a Formatter, given the
+field, rule, parameter and value of each error, e.g. to follow the style
+guide of an API. Messages set in tags and translations take precedence.
+
+	validator.SetFormatter(validator.FormatterFunc(func(field, rule, param string, value interface{}, err error) (string, bool) {
+		if rule == "nonzero" {
+			return field + " is required", true
+		}
+		return "", false
+	}))
+
+The errors of the builtin rules also carry a stable code, e.g. CodeMin for
+ErrMinString and ErrMinInt, returned by the Code method of TextErr and kept
+when their message is replaced, for clients to branch on rather than on
+messages.
+
+	for _, err := range errs["Name"] {
+		if e, ok := err.(validator.TextErr); ok && e.Code() == validator.CodeMin {
+			// ...
+		}
+	}
+
 Multiple validators
 
 You may often need to have a different set of validation
@@ -200,6 +990,19 @@ rules for different situations. In all the examples above,
 we only used the default validator but you could create a
 new one and set specific rules for it.
 
+Validators are created with New, configured with options. Each of them holds
+its own validation functions and settings, so that libraries can use their own
+validator without interfering with the default one shared by the package-level
+functions.
+
+	v := validator.New(
+		validator.WithTagName("valid"),
+		validator.WithValidationFunc("notzz", notZZ),
+	)
+
+Options can also be given to a single call of Validate, leaving the validator
+itself unchanged.
+
 For instance, you might use the same struct to decode incoming JSON for a REST API
 but your needs will change when you're using it to, say, create a new instance
 in storage vs. when you need to change something.
@@ -261,5 +1064,18 @@ It is also possible to do all of that using only the default validator as long
 as SetTag is always called before calling validator.Validate() or you chain the
 with WithTag().
 
+Alternatively, rules can be put in groups with the groups pseudo-rule, taking
+a list of group names separated by |, and a validation restricted to some
+groups with the WithGroups option. Rules not belonging to any group are always
+validated, as are all rules when no group is given.
+
+	type User struct {
+		ID       int    `validate:"nonzero,groups=update"`
+		Username string `validate:"nonzero"`
+		Password string `validate:"nonzero,groups=create|chgpw"`
+	}
+
+	errs := validator.Validate(user, validator.WithGroups("create"))
+
 */
 package validator
@@ -0,0 +1,128 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ErrUnset is the error of the required environment variables which are
+// not set.
+var ErrUnset = TextErr{coded(CodeUnset, "Must be set")}
+
+// ValidateEnv validates the struct v, or the struct v points to, holding
+// the configuration read from the environment, e.g. by
+// github.com/caarlos0/env. Its errors are indexed by the names of the
+// environment variables of the fields, after their env tag and the
+// envPrefix tags of the structs holding them, e.g.
+// DATABASE_URL: Must be a valid URL. If lookup is not nil, e.g.
+// os.LookupEnv, the variables of the required fields, those with the
+// env tag option required or one of the nonzero, required and notnil
+// rules, which are not set are reported as ErrUnset instead.
+func ValidateEnv(v interface{}, lookup func(key string) (string, bool)) error {
+	return defaultValidator.ValidateEnv(v, lookup)
+}
+
+// ValidateEnv validates the struct v, or the struct v points to, holding
+// the configuration read from the environment, e.g. by
+// github.com/caarlos0/env. Its errors are indexed by the names of the
+// environment variables of the fields, after their env tag and the
+// envPrefix tags of the structs holding them, e.g.
+// DATABASE_URL: Must be a valid URL. If lookup is not nil, e.g.
+// os.LookupEnv, the variables of the required fields, those with the
+// env tag option required or one of the nonzero, required and notnil
+// rules, which are not set are reported as ErrUnset instead.
+func (mv *Validator) ValidateEnv(v interface{}, lookup func(key string) (string, bool)) error {
+	t, err := structType(v)
+	if err != nil {
+		return err
+	}
+	// validate with Go names, then index the errors by variable names
+	mv = mv.with(WithNameFunc(goName), WithPromotedFields())
+	vars := make(map[string]envVar)
+	mv.envVars(t, "", "", vars, map[reflect.Type]bool{})
+
+	m := make(ErrorMap)
+	if err := mv.Validate(v); err != nil {
+		errs, ok := err.(ErrorMap)
+		if !ok {
+			return err
+		}
+		for path, fieldErrs := range errs {
+			if ev, ok := vars[path]; ok {
+				path = ev.name
+			}
+			m[path] = append(m[path], fieldErrs...)
+		}
+	}
+	if lookup != nil {
+		for _, ev := range vars {
+			if _, ok := lookup(ev.name); ev.required && !ok {
+				m[ev.name] = ErrorArray{ErrUnset}
+			}
+		}
+	}
+	if len(m) > 0 {
+		return m
+	}
+	return nil
+}
+
+// envVar describes the environment variable a field is read from.
+type envVar struct {
+	name     string
+	required bool
+}
+
+// goName is a NameFunc naming fields after their Go name.
+func goName(f reflect.StructField) string {
+	return f.Name
+}
+
+// envVars adds to vars the environment variables of the fields of the
+// struct type t at path, keyed by the paths of the fields. The names of
+// the variables of nested structs are prefixed by their envPrefix tag.
+// seen holds the struct types being walked, which are not walked again.
+func (mv *Validator) envVars(t reflect.Type, path, prefix string, vars map[string]envVar, seen map[reflect.Type]bool) {
+	seen[t] = true
+	defer delete(seen, t)
+	for _, sf := range mv.structFields(t) {
+		f := sf.field
+		opts := strings.Split(f.Tag.Get("env"), ",")
+		switch name := opts[0]; name {
+		case "-":
+		case "":
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !seen[ft] {
+				mv.envVars(ft, mv.nestedPath(sf, path, f.Name), prefix+f.Tag.Get("envPrefix"), vars, seen)
+			}
+		default:
+			ev := envVar{name: prefix + name}
+			for _, opt := range opts[1:] {
+				ev.required = ev.required || opt == "required"
+			}
+			for _, t := range sf.tags {
+				ev.required = ev.required || t.Name == "nonzero" || t.Name == "required" || t.Name == "notnil"
+			}
+			vars[joinPath(path, f.Name)] = ev
+		}
+	}
+}
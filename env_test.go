@@ -0,0 +1,79 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type envDatabase struct {
+	URL      string `env:"URL,required" validate:"min=12"`
+	PoolSize int    `env:"POOL_SIZE" validate:"min=1"`
+}
+
+type envConfig struct {
+	Port     int          `env:"PORT" validate:"min=1024"`
+	LogLevel string       `env:"LOG_LEVEL" validate:"nonzero"`
+	Database envDatabase  `envPrefix:"DATABASE_"`
+	Replica  *envDatabase `envPrefix:"REPLICA_"`
+	Secret   string       `env:"-" validate:"nonzero"`
+	Debug    bool         `env:"DEBUG"`
+}
+
+func (ms *MySuite) TestValidateEnv(c *C) {
+	cfg := envConfig{
+		Port:     8080,
+		LogLevel: "info",
+		Database: envDatabase{URL: "postgres://localhost/app", PoolSize: 4},
+		Secret:   "s3cr3t",
+	}
+	c.Assert(validator.ValidateEnv(cfg, nil), IsNil)
+	c.Assert(validator.ValidateEnv(&cfg, nil), IsNil)
+
+	cfg.Port = 80
+	cfg.Database.URL = "db"
+	cfg.Replica = &envDatabase{URL: "postgres://replica/app"}
+	cfg.Secret = ""
+	err := validator.ValidateEnv(&cfg, nil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 4)
+	c.Assert(errs["PORT"], HasError, validator.ErrMinInt(1024, 80))
+	c.Assert(errs["DATABASE_URL"], HasError, validator.ErrMinString(12, 2))
+	c.Assert(errs["REPLICA_POOL_SIZE"], HasError, validator.ErrMinInt(1, 0))
+	c.Assert(errs["Secret"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(err.Error(), Equals, "DATABASE_URL: "+validator.ErrMinString(12, 2).Error())
+
+	c.Assert(validator.ValidateEnv("PORT", nil), Equals, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestValidateEnvUnset(c *C) {
+	env := map[string]string{"PORT": "8080", "DATABASE_POOL_SIZE": "4"}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+	cfg := envConfig{Port: 8080, Database: envDatabase{PoolSize: 4}, Secret: "s3cr3t"}
+	errs, ok := validator.ValidateEnv(cfg, lookup).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["REPLICA_URL"], DeepEquals, validator.ErrorArray{validator.ErrUnset})
+	c.Assert(errs["LOG_LEVEL"], DeepEquals, validator.ErrorArray{validator.ErrUnset})
+	c.Assert(errs["DATABASE_URL"], DeepEquals, validator.ErrorArray{validator.ErrUnset})
+}
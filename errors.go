@@ -0,0 +1,136 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+// PathError is an error found at the given path of the value validated.
+type PathError struct {
+	Path string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e PathError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error found at the path.
+func (e PathError) Unwrap() error {
+	return e.Err
+}
+
+// Flatten returns the errors held by err, in the order of their paths,
+// with their full paths. Nested ErrorMaps and ErrorArrays are flattened,
+// the entries of an ErrorMap held by the errors of a path being below
+// the path. Any other error is returned at the empty path.
+func Flatten(err error) []PathError {
+	return appendFlat(nil, "", err)
+}
+
+// appendFlat appends the errors of err at path to errs.
+func appendFlat(errs []PathError, path string, err error) []PathError {
+	switch e := err.(type) {
+	case nil:
+	case ErrorMap:
+		for _, k := range e.Paths() {
+			errs = appendFlat(errs, joinSubPath(path, k), e[k])
+		}
+	case ErrorArray:
+		for _, err := range e {
+			errs = appendFlat(errs, path, err)
+		}
+	default:
+		errs = append(errs, PathError{path, err})
+	}
+	return errs
+}
+
+// joinSubPath appends the path sub to path, indexes being appended as is.
+func joinSubPath(path, sub string) string {
+	if sub == "" {
+		return path
+	}
+	if sub[0] == '[' {
+		return path + sub
+	}
+	return joinPath(path, sub)
+}
+
+// Flatten returns the errors of the map with their full paths, see
+// Flatten.
+func (err ErrorMap) Flatten() []PathError {
+	return Flatten(err)
+}
+
+// Filter returns the entries of the map whose path matches the glob
+// pattern, in which * matches any sequence of characters but dots, e.g.
+// Users[*].Name, and ** any sequence of characters, e.g. Address.**. The
+// map returned is nil if no path matches.
+func (err ErrorMap) Filter(pattern string) ErrorMap {
+	var m ErrorMap
+	for k, errs := range err {
+		if pathMatch(pattern, k) {
+			if m == nil {
+				m = ErrorMap{}
+			}
+			m[k] = errs
+		}
+	}
+	return m
+}
+
+// Dedupe returns the map without the errors repeated for a path, e.g. when
+// the rules of an alias and of the tag of a field overlap, the errors
+// being equal when they have the same code and message. The errors of
+// each path are kept in order.
+func (err ErrorMap) Dedupe() ErrorMap {
+	m := make(ErrorMap, len(err))
+	for k, errs := range err {
+		m[k] = dedupeErrors(errs)
+	}
+	return m
+}
+
+// dedupeErrors returns errs without the errors equal to a previous one,
+// errs itself if it has none.
+func dedupeErrors(errs ErrorArray) ErrorArray {
+	type key struct{ code, msg string }
+	var seen map[key]bool
+	for i, err := range errs {
+		k := key{errorCode(err), err.Error()}
+		if seen == nil {
+			seen = make(map[key]bool, len(errs))
+		}
+		if !seen[k] {
+			seen[k] = true
+			continue
+		}
+		// copy the errors kept so far rather than modifying errs
+		out := append(ErrorArray(nil), errs[:i]...)
+		for _, err := range errs[i+1:] {
+			k := key{errorCode(err), err.Error()}
+			if !seen[k] {
+				seen[k] = true
+				out = append(out, err)
+			}
+		}
+		return out
+	}
+	return errs
+}
@@ -0,0 +1,92 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"errors"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestFlatten(c *C) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+	err := validator.ErrorMap{
+		"Users[10].Name": validator.ErrorArray{errA},
+		"Users[2].Name":  validator.ErrorArray{errA, errB},
+		"Address": validator.ErrorArray{
+			validator.ErrorMap{"City": validator.ErrorArray{errB}},
+			validator.ErrorMap{"[0]": validator.ErrorArray{errA}},
+			errA,
+		},
+	}
+	c.Assert(err.Flatten(), DeepEquals, []validator.PathError{
+		{Path: "Address.City", Err: errB},
+		{Path: "Address[0]", Err: errA},
+		{Path: "Address", Err: errA},
+		{Path: "Users[2].Name", Err: errA},
+		{Path: "Users[2].Name", Err: errB},
+		{Path: "Users[10].Name", Err: errA},
+	})
+	c.Assert(validator.Flatten(errA), DeepEquals, []validator.PathError{{Err: errA}})
+	c.Assert(validator.Flatten(nil), IsNil)
+	c.Assert(validator.PathError{Path: "A.B", Err: errA}.Error(), Equals, "A.B: a")
+}
+
+func (ms *MySuite) TestFilter(c *C) {
+	errs := validator.ErrorArray{errors.New("a")}
+	err := validator.ErrorMap{
+		"Name":                  errs,
+		"Address.City":          errs,
+		"Address.Geo.Lat":       errs,
+		"Users[0].Name":         errs,
+		"Users[1].Address.City": errs,
+	}
+	for pattern, paths := range map[string][]string{
+		"Name":           {"Name"},
+		"Address.*":      {"Address.City"},
+		"Address.**":     {"Address.City", "Address.Geo.Lat"},
+		"Users[*].Name":  {"Users[0].Name"},
+		"Users**.City":   {"Users[1].Address.City"},
+		"**City":         {"Address.City", "Users[1].Address.City"},
+		"*":              {"Name"},
+		"Address.Street": nil,
+	} {
+		c.Assert(err.Filter(pattern).Paths(), DeepEquals, append([]string{}, paths...), Commentf("pattern %s", pattern))
+	}
+}
+
+func (ms *MySuite) TestDedupe(c *C) {
+	v := validator.NewValidator()
+	c.Assert(v.RegisterAlias("username", "nonzero,max=8"), IsNil)
+	type test struct {
+		A string `validate:"username,nonzero"`
+		B string `validate:"username"`
+	}
+	err := v.Validate(test{B: "abcdefghij"})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasLen, 2)
+
+	deduped := errs.Dedupe()
+	c.Assert(deduped["A"], DeepEquals, errs["A"][:1])
+	c.Assert(deduped["B"], DeepEquals, errs["B"])
+	// the original map is left untouched
+	c.Assert(errs["A"], HasLen, 2)
+}
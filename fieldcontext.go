@@ -0,0 +1,115 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"reflect"
+)
+
+// topKey is the context key of the struct given to Validate.
+type topKey struct{}
+
+// FieldContext describes the value being validated by a function set
+// with SetValidationFuncField: the field holding it, the struct holding
+// the field and the struct being validated, e.g. for rules comparing
+// fields or for messages naming them.
+type FieldContext struct {
+	ctx   context.Context
+	value interface{}
+	param string
+}
+
+// Context returns the context of the validation, as given to
+// ValidateContext.
+func (fc FieldContext) Context() context.Context {
+	return fc.ctx
+}
+
+// Value returns the value being validated.
+func (fc FieldContext) Value() interface{} {
+	return fc.value
+}
+
+// Param returns the parameter of the rule.
+func (fc FieldContext) Param() string {
+	return fc.param
+}
+
+// Parent returns the struct holding the field being validated, nil when
+// validating a single value with Valid.
+func (fc FieldContext) Parent() interface{} {
+	p, ok := fc.ctx.Value(parentKey{}).(parent)
+	if !ok || !p.sv.CanInterface() {
+		return nil
+	}
+	return p.sv.Interface()
+}
+
+// FieldName returns the Go name of the field being validated, empty
+// when validating a single value with Valid.
+func (fc FieldContext) FieldName() string {
+	return fc.StructField().Name
+}
+
+// StructField returns the field being validated, the zero StructField
+// when validating a single value with Valid.
+func (fc FieldContext) StructField() reflect.StructField {
+	p, _ := fc.ctx.Value(parentKey{}).(parent)
+	return p.field
+}
+
+// Top returns the struct given to Validate, or the first struct validated
+// by the call, nil when validating a single value with Valid.
+func (fc FieldContext) Top() interface{} {
+	sv, ok := fc.ctx.Value(topKey{}).(reflect.Value)
+	if !ok || !sv.CanInterface() {
+		return nil
+	}
+	return sv.Interface()
+}
+
+// ValidationFuncField is a validation function receiving the value being
+// validated along with the field and structs holding it.
+type ValidationFuncField func(fc FieldContext) error
+
+// SetValidationFuncField sets the function to be used for a given
+// validation constraint, receiving a FieldContext describing the value
+// being validated. Calling this function with nil vf is the same as
+// removing the constraint function from the list.
+func SetValidationFuncField(name string, vf ValidationFuncField) error {
+	return defaultValidator.SetValidationFuncField(name, vf)
+}
+
+// SetValidationFuncField sets the function to be used for a given
+// validation constraint, receiving a FieldContext describing the value
+// being validated. Calling this function with nil vf is the same as
+// removing the constraint function from the list.
+func (mv *Validator) SetValidationFuncField(name string, vf ValidationFuncField) error {
+	if vf == nil {
+		return mv.SetValidationFuncCtx(name, nil)
+	}
+	if err := mv.SetValidationFuncCtx(name, func(ctx context.Context, v interface{}, param string) error {
+		return vf(FieldContext{ctx: ctx, value: v, param: param})
+	}); err != nil {
+		return err
+	}
+	mv.lock.Lock()
+	mv.fieldFuncs = true
+	mv.lock.Unlock()
+	return nil
+}
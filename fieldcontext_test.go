@@ -0,0 +1,80 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type fieldContextRange struct {
+	Min int
+	Max int `validate:"gtefield=Min"`
+}
+
+type fieldContextOrder struct {
+	ID    string
+	Range fieldContextRange
+	Note  string `validate:"describe"`
+}
+
+func (ms *MySuite) TestFieldContext(c *C) {
+	v := validator.New()
+	c.Assert(v.SetValidationFuncField("gtefield", func(fc validator.FieldContext) error {
+		other := reflect.ValueOf(fc.Parent()).FieldByName(fc.Param())
+		if fc.Value().(int) < int(other.Int()) {
+			return fmt.Errorf("%s must be at least %s", fc.FieldName(), fc.Param())
+		}
+		return nil
+	}), IsNil)
+	var got []string
+	c.Assert(v.SetValidationFuncField("describe", func(fc validator.FieldContext) error {
+		top, _ := fc.Top().(fieldContextOrder)
+		tag := fc.StructField().Tag.Get("validate")
+		got = append(got, fmt.Sprintf("%s %s %s %v", fc.FieldName(), tag, top.ID, fc.Context().Value("request")))
+		return nil
+	}), IsNil)
+
+	order := fieldContextOrder{ID: "42", Range: fieldContextRange{Min: 3, Max: 1}}
+	ctx := context.WithValue(context.Background(), "request", "r1")
+	errs, ok := v.ValidateContext(ctx, &order).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Range.Max"], HasError, errors.New("Max must be at least Min"))
+	c.Assert(got, DeepEquals, []string{"Note describe 42 r1"})
+
+	order.Range.Max = 3
+	c.Assert(v.Validate(order), IsNil)
+
+	c.Assert(v.SetValidationFuncField("describe", func(fc validator.FieldContext) error {
+		c.Assert(fc.Parent(), IsNil)
+		c.Assert(fc.Top(), IsNil)
+		c.Assert(fc.FieldName(), Equals, "")
+		c.Assert(fc.Value(), Equals, "x")
+		c.Assert(fc.Param(), Equals, "p")
+		return validator.ErrInvalid
+	}), IsNil)
+	c.Assert(v.Valid("x", "describe=p"), HasError, validator.ErrInvalid)
+
+	c.Assert(v.SetValidationFuncField("describe", nil), IsNil)
+	c.Assert(v.Valid("x", "describe"), Equals, validator.ErrUnknownTag)
+}
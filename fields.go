@@ -0,0 +1,163 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import "strings"
+
+// ValidateFields validates the given fields of the struct v only,
+// ignoring any other field. Fields are given by their path as found
+// in the keys of an ErrorMap, with or without indexes, e.g. "Name",
+// "Address.City" or "Users.Email". The fields held by a field given
+// are validated too.
+func ValidateFields(v interface{}, fields ...string) error {
+	return defaultValidator.ValidateFields(v, fields...)
+}
+
+// ValidateFields validates the given fields of the struct v only,
+// ignoring any other field. Fields are given by their path as found
+// in the keys of an ErrorMap, with or without indexes, e.g. "Name",
+// "Address.City" or "Users.Email". The fields held by a field given
+// are validated too.
+func (mv *Validator) ValidateFields(v interface{}, fields ...string) error {
+	return mv.Validate(v, withFields(fields))
+}
+
+// ValidateFieldsExcept validates the fields of the struct v except the
+// given ones, which are given by their path like for ValidateFields.
+func ValidateFieldsExcept(v interface{}, fields ...string) error {
+	return defaultValidator.ValidateFieldsExcept(v, fields...)
+}
+
+// ValidateFieldsExcept validates the fields of the struct v except the
+// given ones, which are given by their path like for ValidateFields.
+func (mv *Validator) ValidateFieldsExcept(v interface{}, fields ...string) error {
+	return mv.Validate(v, withExcept(fields))
+}
+
+// Except excludes the given fields from a single validation, e.g.
+// Validate(v, Except("Password", "Secrets.*")). Fields are given by their
+// path like for ValidateFields, or by a pattern in which * matches any
+// field name and ** any sequence of field names.
+func Except(fields ...string) Option {
+	return withExcept(fields)
+}
+
+// withFields restricts validation to the given fields.
+func withFields(fields []string) Option {
+	return func(mv *Validator) {
+		mv.fields = append(mv.fields[:len(mv.fields):len(mv.fields)], fields...)
+	}
+}
+
+// withExcept excludes the given fields from validation.
+func withExcept(fields []string) Option {
+	return func(mv *Validator) {
+		mv.except = append(mv.except[:len(mv.except):len(mv.except)], fields...)
+	}
+}
+
+// selecting reports whether only some of the fields are validated.
+func (mv *Validator) selecting() bool {
+	return len(mv.fields) > 0 || len(mv.except) > 0
+}
+
+// selected reports whether the rules of the field found at any of the
+// given paths are to be validated and whether the values it holds are
+// to be descended into.
+func (mv *Validator) selected(paths ...string) (validate, descend bool) {
+	if !mv.selecting() {
+		return true, true
+	}
+	validate = len(mv.fields) == 0
+	for _, p := range paths {
+		sp := stripIndexes(p)
+		for _, f := range mv.except {
+			if pathMatch(f, p) || pathMatch(f, sp) || isAncestor(f, p) || isAncestor(f, sp) {
+				return false, false
+			}
+		}
+		for _, f := range mv.fields {
+			switch {
+			case pathMatch(f, p), pathMatch(f, sp), isAncestor(f, p), isAncestor(f, sp):
+				validate = true
+			case isAncestor(p, f), isAncestor(sp, f):
+				descend = true
+			}
+		}
+	}
+	return validate, validate || descend
+}
+
+// pathMatch reports whether path matches pattern, in which * matches
+// any sequence of characters but dots, e.g. Secrets.* matches
+// Secrets.Key and Users[*].Name matches Users[2].Name, and ** any
+// sequence of characters, e.g. Secrets.** matches Secrets.Keys[0].ID.
+func pathMatch(pattern, path string) bool {
+	i := strings.IndexByte(pattern, '*')
+	if i < 0 {
+		return pattern == path
+	}
+	if !strings.HasPrefix(path, pattern[:i]) {
+		return false
+	}
+	deep := strings.HasPrefix(pattern[i+1:], "*")
+	pattern, path = pattern[i+1:], path[i:]
+	if deep {
+		pattern = pattern[1:]
+	}
+	// try every sequence of characters, but dots unless deep, matched
+	for j := 0; j <= len(path); j++ {
+		if pathMatch(pattern, path[j:]) {
+			return true
+		}
+		if j < len(path) && path[j] == '.' && !deep {
+			break
+		}
+	}
+	return false
+}
+
+// isAncestor reports whether the field at path holds the one at
+// descendant, e.g. Users holds Users[2] which holds Users[2].Name.
+func isAncestor(path, descendant string) bool {
+	if path == "" {
+		return descendant != ""
+	}
+	return len(descendant) > len(path) && strings.HasPrefix(descendant, path) &&
+		(descendant[len(path)] == '.' || descendant[len(path)] == '[')
+}
+
+// stripIndexes removes the indexes and keys from a path, e.g.
+// Users[2].Name becomes Users.Name.
+func stripIndexes(path string) string {
+	if !strings.Contains(path, "[") {
+		return path
+	}
+	b := make([]byte, 0, len(path))
+	depth := 0
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,106 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type patchAddress struct {
+	Street string `validate:"nonzero"`
+	City   string `validate:"nonzero"`
+}
+
+type patchUser struct {
+	Name     string `validate:"nonzero"`
+	Email    string `validate:"nonzero"`
+	Address  patchAddress
+	Previous []patchAddress
+}
+
+func (ms *MySuite) TestValidateFields(c *C) {
+	u := patchUser{Previous: []patchAddress{{}, {}}}
+
+	err := validator.ValidateFields(u, "Name")
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Name"], HasError, validator.ErrZeroValueEmpty)
+
+	err = validator.ValidateFields(u, "Address.City", "Previous[1]")
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["Address.City"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Previous[1].Street"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Previous[1].City"], HasError, validator.ErrZeroValueEmpty)
+
+	err = validator.ValidateFields(u, "Previous.City")
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Previous[0].City"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Previous[1].City"], HasError, validator.ErrZeroValueEmpty)
+
+	u = patchUser{Name: "joe"}
+	err = validator.ValidateFields(u, "Name", "Address")
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+
+	err = validator.ValidateFields(u, "Name")
+	c.Assert(err, IsNil)
+}
+
+func (ms *MySuite) TestValidateFieldsExcept(c *C) {
+	u := patchUser{Previous: []patchAddress{{}}}
+
+	err := validator.ValidateFieldsExcept(u, "Email", "Address.Street", "Previous")
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Name"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Address.City"], HasError, validator.ErrZeroValueEmpty)
+}
+
+func (ms *MySuite) TestExcept(c *C) {
+	u := patchUser{Previous: []patchAddress{{}, {}}}
+
+	errs, ok := validator.Validate(u, validator.Except("Email", "Address.*")).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Name", "Previous[0].City", "Previous[0].Street", "Previous[1].City", "Previous[1].Street"})
+
+	errs, ok = validator.Validate(u, validator.Except("*.Street", "Previous[1]")).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Address.City", "Email", "Name", "Previous[0].City"})
+
+	errs, ok = validator.Validate(u, validator.Except("Previous[*].City", "Address")).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Email", "Name", "Previous[0].Street", "Previous[1].Street"})
+	c.Assert(validator.Validate(u, validator.Except("*")), IsNil)
+
+	// * does not match dots
+	errs, ok = validator.Validate(u, validator.Except("Ad*City", "N*e")).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"], HasLen, 0)
+	c.Assert(errs["Address.City"], HasLen, 1)
+
+	// the validator itself is left unchanged
+	c.Assert(validator.Validate(u).(validator.ErrorMap), HasLen, 8)
+}
@@ -0,0 +1,57 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+// Formatter formats the messages of the errors of validation rules,
+// e.g. to follow the style guide of an API.
+type Formatter interface {
+	// Format returns the message of the error err returned by the rule
+	// with the given param when validating value, the value of the field
+	// named field, its Go name, or of no field when validating a single
+	// value with Valid. ok is false to keep err as it is.
+	Format(field, rule, param string, value interface{}, err error) (msg string, ok bool)
+}
+
+// FormatterFunc is a function implementing the Formatter interface.
+type FormatterFunc func(field, rule, param string, value interface{}, err error) (string, bool)
+
+// Format implements the Formatter interface.
+func (f FormatterFunc) Format(field, rule, param string, value interface{}, err error) (string, bool) {
+	return f(field, rule, param, value, err)
+}
+
+// WithFormatter sets the Formatter of the messages of errors. Messages
+// set in tags and translations take precedence over it.
+func WithFormatter(f Formatter) Option {
+	return func(mv *Validator) {
+		mv.formatter = f
+	}
+}
+
+// SetFormatter sets the Formatter of the messages of errors. Messages
+// set in tags and translations take precedence over it. Calling this
+// function with nil f restores the default messages.
+func SetFormatter(f Formatter) {
+	defaultValidator.SetFormatter(f)
+}
+
+// SetFormatter sets the Formatter of the messages of errors. Messages
+// set in tags and translations take precedence over it. Calling this
+// function with nil f restores the default messages.
+func (mv *Validator) SetFormatter(f Formatter) {
+	mv.formatter = f
+}
@@ -0,0 +1,74 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"fmt"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+// styleGuide formats messages as "<field> <reason>", leaving the errors
+// of rules other than nonzero and min alone.
+var styleGuide = validator.FormatterFunc(func(field, rule, param string, value interface{}, err error) (string, bool) {
+	if field == "" {
+		field = "value"
+	}
+	switch rule {
+	case "nonzero":
+		return field + " is required", true
+	case "min":
+		return fmt.Sprintf("%s must be at least %s, got %v", field, param, value), true
+	}
+	return "", false
+})
+
+func (ms *MySuite) TestFormatter(c *C) {
+	type user struct {
+		Name  string `validate:"nonzero"`
+		Age   int    `validate:"min=18"`
+		Nick  string `validate:"max=3"`
+		Email string `validate:"nonzero ~ email please"`
+	}
+	v := validator.New(validator.WithFormatter(styleGuide))
+	errs, ok := v.Validate(user{Age: 12, Nick: "Joseph"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"].Error(), Equals, "Name is required")
+	c.Assert(errs["Age"].Error(), Equals, "Age must be at least 18, got 12")
+	c.Assert(errs["Nick"], HasError, validator.ErrMaxString(3, 6))
+	c.Assert(errs["Email"].Error(), Equals, "email please")
+
+	c.Assert(v.Valid(0, "nonzero").Error(), Equals, "value is required")
+
+	// translations take precedence
+	v = validator.New(
+		validator.WithFormatter(styleGuide),
+		validator.WithTranslator(validator.Catalog{"fr": {"nonzero": "ne doit pas être vide"}}),
+		validator.WithLocale("fr"),
+	)
+	errs, ok = v.Validate(user{Email: "a"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"].Error(), Equals, "ne doit pas être vide")
+	c.Assert(errs["Age"].Error(), Equals, "Age must be at least 18, got 0")
+
+	v.SetFormatter(nil)
+	errs, ok = v.Validate(user{Email: "a"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Age"], HasError, validator.ErrMinInt(18, 0))
+}
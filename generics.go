@@ -0,0 +1,110 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package validator
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// Rules holds the validation rules of values of type T declared in
+// code rather than in struct tags. Fields are referenced by accessor
+// functions, so renaming a field breaks the build instead of silently
+// disabling its rules.
+//
+//	var userRules = validator.For[User]().
+//		Field("Name", func(u User) interface{} { return u.Name }).Rules("nonzero", "max=40").
+//		Field("Age", func(u User) interface{} { return u.Age }).Rules("min=18")
+type Rules[T any] struct {
+	mv     *Validator
+	fields []*FieldBuilder[T]
+}
+
+// FieldBuilder holds the rules of one field of a Rules.
+type FieldBuilder[T any] struct {
+	rules *Rules[T]
+	name  string
+	get   func(T) interface{}
+	tags  string
+}
+
+// For returns an empty set of rules of values of type T, checked by
+// the default validator configured with the given options.
+func For[T any](opts ...Option) *Rules[T] {
+	return &Rules[T]{mv: defaultValidator.with(opts...)}
+}
+
+// Using returns the rules checked by mv instead of the default validator.
+func (r *Rules[T]) Using(mv *Validator) *Rules[T] {
+	return &Rules[T]{mv: mv, fields: r.fields}
+}
+
+// Field declares a field named name, whose value is returned by get.
+// The name is the key of the errors of the field.
+func (r *Rules[T]) Field(name string, get func(T) interface{}) *FieldBuilder[T] {
+	return &FieldBuilder[T]{rules: r, name: name, get: get}
+}
+
+// Rules sets the rules of the field, written as in a struct tag, and
+// returns the rules it belongs to, so that more fields can be declared.
+func (f *FieldBuilder[T]) Rules(rules ...string) *Rules[T] {
+	f.tags = strings.Join(rules, ",")
+	fields := make([]*FieldBuilder[T], len(f.rules.fields), len(f.rules.fields)+1)
+	copy(fields, f.rules.fields)
+	return &Rules[T]{mv: f.rules.mv, fields: append(fields, f)}
+}
+
+// Validate validates v against the rules declared in code and, if v is
+// a struct, its struct tags. It returns the errors found indexed by the
+// field name, or nil.
+func (r *Rules[T]) Validate(v T) error {
+	return r.ValidateContext(context.Background(), v)
+}
+
+// ValidateContext is like Validate, passing ctx to the validation
+// functions needing it.
+func (r *Rules[T]) ValidateContext(ctx context.Context, v T) error {
+	m := make(ErrorMap)
+	if sv := reflect.ValueOf(indirect(v)); sv.Kind() == reflect.Struct {
+		r.mv.validateStruct(ctx, sv, "", m, true)
+	}
+	for _, f := range r.fields {
+		if ctx.Err() != nil || r.mv.full(m) {
+			break
+		}
+		if ok, _ := r.mv.selected(f.name); !ok {
+			continue
+		}
+		err := r.mv.validWarn(ctx, f.get(v), f.tags)
+		if errs, ok := err.(ErrorArray); ok {
+			r.mv.addErrors(m, f.name, errs...)
+		} else if err != nil {
+			r.mv.addErrors(m, f.name, err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(m) > 0 {
+		return m
+	}
+	return nil
+}
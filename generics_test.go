@@ -0,0 +1,78 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type typedUser struct {
+	Name  string `validate:"nonzero"`
+	Email string
+	Age   int
+}
+
+var typedUserRules = validator.For[typedUser]().
+	Field("Email", func(u typedUser) interface{} { return u.Email }).Rules("nonzero", "regexp=@").
+	Field("Age", func(u typedUser) interface{} { return u.Age }).Rules("min=18")
+
+func (ms *MySuite) TestFor(c *C) {
+	err := typedUserRules.Validate(typedUser{Email: "joe", Age: 12})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Email"], HasError, validator.ErrRegexpDetailed("@"))
+	c.Assert(errs["Age"], HasError, validator.ErrMinInt(18, 12))
+
+	c.Assert(typedUserRules.Validate(typedUser{Name: "Joe", Email: "joe@example.com", Age: 20}), IsNil)
+}
+
+func (ms *MySuite) TestForOptions(c *C) {
+	rules := validator.For[typedUser](validator.WithFailFast()).
+		Field("Age", func(u typedUser) interface{} { return u.Age }).Rules("min=18")
+	errs, ok := rules.Validate(typedUser{}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Name"], HasLen, 1)
+
+	v := validator.New(validator.WithValidationFunc("adult", func(v interface{}, _ string) error {
+		if v.(int) < 18 {
+			return validator.ErrMin
+		}
+		return nil
+	}))
+	rules = validator.For[typedUser]().Using(v).
+		Field("Age", func(u typedUser) interface{} { return u.Age }).Rules("adult")
+	errs, ok = rules.Validate(typedUser{Name: "Joe", Age: 3}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Age"], HasError, validator.ErrMin)
+}
+
+func (ms *MySuite) TestForNonStruct(c *C) {
+	rules := validator.For[string]().
+		Field("value", func(s string) interface{} { return s }).Rules("min=3")
+	errs, ok := rules.Validate("ab").(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["value"], HasError, validator.ErrMinString(3, 2))
+	c.Assert(rules.Validate("abc"), IsNil)
+}
@@ -0,0 +1,121 @@
+// Package grpcvalidator validates gRPC request messages
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcvalidator provides gRPC server interceptors validating
+// request messages with package validator, either with the tags of their
+// generated structs or with the rules registered for their types, e.g.
+// with RegisterStructValidation.
+//
+//	s := grpc.NewServer(
+//		grpc.UnaryInterceptor(grpcvalidator.UnaryServerInterceptor(nil)),
+//		grpc.StreamInterceptor(grpcvalidator.StreamServerInterceptor(nil)),
+//	)
+//
+// Invalid requests are rejected with codes.InvalidArgument, the errors of
+// each field being attached as the field violations of an
+// errdetails.BadRequest.
+package grpcvalidator
+
+import (
+	"context"
+	"sort"
+
+	"github.com/movio/validator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns an interceptor validating the requests
+// of unary calls with v, or with the default validator if v is nil.
+func UnaryServerInterceptor(v *validator.Validator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := validate(ctx, v, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor validating each message
+// received from the clients of streaming calls with v, or with the
+// default validator if v is nil.
+func StreamServerInterceptor(v *validator.Validator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, v: v})
+	}
+}
+
+// serverStream validates the messages it receives.
+type serverStream struct {
+	grpc.ServerStream
+	v *validator.Validator
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return validate(s.Context(), s.v, m)
+}
+
+// validate validates the message m, returning an InvalidArgument status
+// error if it is invalid. Messages which are not structs are not
+// validated.
+func validate(ctx context.Context, v *validator.Validator, m interface{}) error {
+	var err error
+	if v == nil {
+		err = validator.ValidateContext(ctx, m)
+	} else {
+		err = v.ValidateContext(ctx, m)
+	}
+	switch err {
+	case nil, validator.ErrUnsupported:
+		return nil
+	case context.Canceled, context.DeadlineExceeded:
+		return status.FromContextError(err).Err()
+	}
+	errs, ok := err.(validator.ErrorMap)
+	if !ok {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return invalidArgument(errs)
+}
+
+// invalidArgument returns an InvalidArgument status error with the
+// errors of errs as field violations, sorted by field.
+func invalidArgument(errs validator.ErrorMap) error {
+	fields := make([]string, 0, len(errs))
+	for f := range errs {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	br := &errdetails.BadRequest{}
+	for _, f := range fields {
+		for _, err := range errs[f] {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f,
+				Description: err.Error(),
+			})
+		}
+	}
+	st := status.New(codes.InvalidArgument, "invalid request: "+errs.Error())
+	if ds, err := st.WithDetails(br); err == nil {
+		st = ds
+	}
+	return st.Err()
+}
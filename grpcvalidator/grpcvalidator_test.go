@@ -0,0 +1,108 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcvalidator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/movio/validator"
+	"github.com/movio/validator/grpcvalidator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type InterceptorSuite struct{}
+
+var _ = Suite(&InterceptorSuite{})
+
+type createUserRequest struct {
+	Name  string `validate:"nonzero"`
+	Email string `validate:"nonzero,min=5"`
+}
+
+func handler(ctx context.Context, req interface{}) (interface{}, error) {
+	return "ok", nil
+}
+
+func (s *InterceptorSuite) TestUnaryServerInterceptor(c *C) {
+	i := grpcvalidator.UnaryServerInterceptor(nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.Users/Create"}
+
+	resp, err := i(context.Background(), &createUserRequest{Name: "Joe", Email: "joe@example.com"}, info, handler)
+	c.Assert(err, IsNil)
+	c.Assert(resp, Equals, "ok")
+
+	resp, err = i(context.Background(), &createUserRequest{Email: "joe"}, info, handler)
+	c.Assert(resp, IsNil)
+	st := status.Convert(err)
+	c.Assert(st.Code(), Equals, codes.InvalidArgument)
+	c.Assert(st.Details(), HasLen, 1)
+	br := st.Details()[0].(*errdetails.BadRequest)
+	c.Assert(br.FieldViolations, DeepEquals, []*errdetails.BadRequest_FieldViolation{
+		{Field: "Email", Description: validator.ErrMinString(5, 3).Error()},
+		{Field: "Name", Description: validator.ErrZeroValueEmpty.Error()},
+	})
+
+	// messages which are not structs are not validated
+	resp, err = i(context.Background(), "foo", info, handler)
+	c.Assert(err, IsNil)
+	c.Assert(resp, Equals, "ok")
+}
+
+func (s *InterceptorSuite) TestUnaryServerInterceptorValidator(c *C) {
+	v := validator.New(validator.WithTagName("grpc"))
+	i := grpcvalidator.UnaryServerInterceptor(v)
+	_, err := i(context.Background(), &createUserRequest{}, &grpc.UnaryServerInfo{}, handler)
+	c.Assert(err, IsNil)
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	msgs []createUserRequest
+}
+
+func (s *fakeStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeStream) RecvMsg(m interface{}) error {
+	*m.(*createUserRequest) = s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return nil
+}
+
+func (s *InterceptorSuite) TestStreamServerInterceptor(c *C) {
+	i := grpcvalidator.StreamServerInterceptor(nil)
+	ss := &fakeStream{msgs: []createUserRequest{{Name: "Joe", Email: "joe@example.com"}, {Name: "Joe"}}}
+	err := i(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		var req createUserRequest
+		c.Assert(stream.RecvMsg(&req), IsNil)
+		err := stream.RecvMsg(&req)
+		c.Assert(status.Convert(err).Code(), Equals, codes.InvalidArgument)
+		return err
+	})
+	c.Assert(status.Convert(err).Code(), Equals, codes.InvalidArgument)
+}
@@ -0,0 +1,325 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxFormMemory is the memory used to parse multipart forms, the rest
+// of the files being stored on disk, as by http.Request.FormValue.
+const maxFormMemory = 32 << 20
+
+// DefaultMaxBodySize is the maximum size in bytes of the bodies decoded
+// by JSONHandler, unless set otherwise with WithMaxBodySize.
+const DefaultMaxBodySize = 1 << 20
+
+// WithMaxBodySize sets the maximum size in bytes of the bodies decoded
+// by JSONHandler, DefaultMaxBodySize when zero. A negative size lifts
+// the limit, which should only be done behind a trusted proxy.
+func WithMaxBodySize(n int64) Option {
+	return func(mv *Validator) {
+		mv.maxBodySize = n
+	}
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// BindForm sets the fields of the struct dst points to from the form
+// values of r, either URL-encoded or multipart, and validates it. The
+// fields are set from the value named after their form tag, or after
+// their name if they have none, nested structs being named with dots,
+// e.g. address.city. Fields tagged with form:"-" are left untouched.
+// The values which cannot be converted to the type of their field are
+// reported with ErrInvalid, in the same ErrorMap as the validation
+// errors. To index them by form names as well, use a validator created
+// with WithNameFunc(TagNameFunc("form")).
+func BindForm(r *http.Request, dst interface{}) error {
+	return defaultValidator.BindForm(r, dst)
+}
+
+// BindForm sets the fields of the struct dst points to from the form
+// values of r, either URL-encoded or multipart, and validates it. The
+// fields are set from the value named after their form tag, or after
+// their name if they have none, nested structs being named with dots,
+// e.g. address.city. Fields tagged with form:"-" are left untouched.
+// The values which cannot be converted to the type of their field are
+// reported with ErrInvalid, in the same ErrorMap as the validation
+// errors. To index them by form names as well, use a validator created
+// with WithNameFunc(TagNameFunc("form")).
+func (mv *Validator) BindForm(r *http.Request, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrUnsupported
+	}
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		err = r.ParseMultipartForm(maxFormMemory)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		return err
+	}
+
+	m := make(ErrorMap)
+	mv.bindStruct(r.Form, v.Elem(), "", "", m)
+	if err := mv.Validate(dst); err != nil {
+		errs, ok := err.(ErrorMap)
+		if !ok {
+			return err
+		}
+		for k, v := range errs {
+			m[k] = append(m[k], v...)
+		}
+	}
+	if len(m) > 0 {
+		return m
+	}
+	return nil
+}
+
+// contextKey is the type of the keys of the values set by the package
+// in contexts.
+type contextKey int
+
+const requestValueKey contextKey = 0
+
+// JSONHandler returns a handler decoding the JSON body of requests into
+// a new value of the type of v, a struct or a pointer to a struct, and
+// validating it. Valid values are then handled by next, which gets a
+// pointer to the value with RequestValue. Bodies larger than
+// DefaultMaxBodySize, or the size set with WithMaxBodySize, are rejected
+// with http.StatusRequestEntityTooLarge, bodies which cannot be decoded
+// with http.StatusBadRequest and invalid values with
+// http.StatusUnprocessableEntity and their errors as a JSON object,
+// e.g. {"errors":{"Name":["Must not be empty"]}}.
+func JSONHandler(next http.Handler, v interface{}, opts ...Option) http.Handler {
+	return defaultValidator.JSONHandler(next, v, opts...)
+}
+
+// JSONHandler returns a handler decoding the JSON body of requests into
+// a new value of the type of v, a struct or a pointer to a struct, and
+// validating it. Valid values are then handled by next, which gets a
+// pointer to the value with RequestValue. Bodies larger than
+// DefaultMaxBodySize, or the size set with WithMaxBodySize, are rejected
+// with http.StatusRequestEntityTooLarge, bodies which cannot be decoded
+// with http.StatusBadRequest and invalid values with
+// http.StatusUnprocessableEntity and their errors as a JSON object,
+// e.g. {"errors":{"Name":["Must not be empty"]}}.
+func (mv *Validator) JSONHandler(next http.Handler, v interface{}, opts ...Option) http.Handler {
+	t, err := structType(v)
+	if err != nil {
+		panic("validator: JSONHandler of a value which is not a struct")
+	}
+	mv = mv.with(opts...)
+	limit := mv.maxBodySize
+	if limit == 0 {
+		limit = DefaultMaxBodySize
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := r.Body
+		if limit > 0 {
+			body = http.MaxBytesReader(w, body, limit)
+		}
+		dst := reflect.New(t).Interface()
+		if err := json.NewDecoder(body).Decode(dst); err != nil {
+			code := http.StatusBadRequest
+			if isBodyTooLarge(err) {
+				code = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, err.Error(), code)
+			return
+		}
+		if err := mv.ValidateContext(r.Context(), dst); err != nil {
+			errs, ok := err.(ErrorMap)
+			if !ok {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeErrors(w, http.StatusUnprocessableEntity, errs)
+			return
+		}
+		ctx := context.WithValue(r.Context(), requestValueKey, dst)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// isBodyTooLarge reports whether err was returned by a reader of
+// http.MaxBytesReader past its limit. The error is matched on its text
+// as http.MaxBytesError only exists since Go 1.19.
+func isBodyTooLarge(err error) bool {
+	return err.Error() == "http: request body too large"
+}
+
+// RequestValue returns the pointer to the value decoded from the body of
+// r by JSONHandler, or nil.
+func RequestValue(r *http.Request) interface{} {
+	return r.Context().Value(requestValueKey)
+}
+
+// writeErrors writes errs to w as a JSON object with the given status.
+func writeErrors(w http.ResponseWriter, status int, errs ErrorMap) {
+	msgs := make(map[string][]string, len(errs))
+	for k, errs := range errs {
+		for _, err := range errs {
+			msgs[k] = append(msgs[k], err.Error())
+		}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": msgs})
+}
+
+// bindStruct sets the fields of the struct sv from the form values
+// named with the given prefix, adding conversion errors to m under
+// their path.
+func (mv *Validator) bindStruct(form map[string][]string, sv reflect.Value, prefix, path string, m ErrorMap) {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		formName := strings.SplitN(sf.Tag.Get("form"), ",", 2)[0]
+		if formName == "-" {
+			continue
+		}
+		if formName == "" {
+			formName = sf.Name
+		}
+		name, errName := mv.fieldNames(structField{field: sf, jsonName: jsonTagName(sf)})
+
+		f := sv.Field(i)
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && !reflect.PtrTo(ft).Implements(textUnmarshalerType) {
+			if f.Kind() == reflect.Ptr {
+				if !hasPrefix(form, prefix+formName+".") {
+					continue
+				}
+				f = allocate(f)
+			}
+			mv.bindStruct(form, f, prefix+formName+".", joinPath(path, name), m)
+			continue
+		}
+		values, ok := form[prefix+formName]
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if err := setFormValue(f, values); err != nil {
+			key := joinPath(path, errName)
+			m[key] = append(m[key], err)
+		}
+	}
+}
+
+// hasPrefix reports whether any of the names of form starts with prefix.
+func hasPrefix(form map[string][]string, prefix string) bool {
+	for k := range form {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// allocate returns the value the pointer v points to, allocating the
+// values of the nil pointers on the way.
+func allocate(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// setFormValue sets v from the form values, all of them for slices and
+// the first one otherwise.
+func setFormValue(v reflect.Value, values []string) error {
+	v = allocate(v)
+	if v.Kind() == reflect.Slice && !reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setFormValue(s.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return setString(v, values[0])
+}
+
+// setString sets v from its text representation s.
+func setString(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			return ErrInvalid
+		}
+		return nil
+	}
+	if s == "" {
+		// empty inputs leave numbers and booleans to their zero value
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == reflect.TypeOf(time.Duration(0)) {
+			var d time.Duration
+			d, err = time.ParseDuration(s)
+			v.SetInt(int64(d))
+			break
+		}
+		var i int64
+		i, err = strconv.ParseInt(s, 10, v.Type().Bits())
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, v.Type().Bits())
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, v.Type().Bits())
+		v.SetFloat(f)
+	default:
+		return ErrUnsupported
+	}
+	if err != nil {
+		return ErrInvalid
+	}
+	return nil
+}
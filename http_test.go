@@ -0,0 +1,184 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type formAddress struct {
+	City string `form:"city" validate:"nonzero"`
+}
+
+type formUser struct {
+	Name    string        `form:"name" validate:"nonzero,min=3"`
+	Age     int           `form:"age" validate:"min=18"`
+	Score   *float64      `form:"score"`
+	Admin   bool          `form:"admin"`
+	Tags    []string      `form:"tag" validate:"max=2"`
+	Born    time.Time     `form:"born"`
+	Timeout time.Duration `form:"timeout"`
+	Address formAddress   `form:"address"`
+	Work    *formAddress  `form:"work"`
+	Secret  string        `form:"-"`
+}
+
+func (ms *MySuite) TestBindForm(c *C) {
+	form := url.Values{
+		"name":         {"Joe"},
+		"age":          {"42"},
+		"score":        {"4.5"},
+		"admin":        {"true"},
+		"tag":          {"a", "b"},
+		"born":         {"1975-04-01T00:00:00Z"},
+		"timeout":      {"1m"},
+		"address.city": {"Paris"},
+		"Secret":       {"x"},
+		"-":            {"x"},
+	}
+	r := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var u formUser
+	c.Assert(validator.BindForm(r, &u), IsNil)
+	c.Assert(u.Name, Equals, "Joe")
+	c.Assert(u.Age, Equals, 42)
+	c.Assert(*u.Score, Equals, 4.5)
+	c.Assert(u.Admin, Equals, true)
+	c.Assert(u.Tags, DeepEquals, []string{"a", "b"})
+	c.Assert(u.Born.Year(), Equals, 1975)
+	c.Assert(u.Timeout, Equals, time.Minute)
+	c.Assert(u.Address.City, Equals, "Paris")
+	c.Assert(u.Work, IsNil)
+	c.Assert(u.Secret, Equals, "")
+}
+
+func (ms *MySuite) TestBindFormErrors(c *C) {
+	r := httptest.NewRequest("GET", "/?name=Jo&age=abc&tag=a&tag=b&tag=c&work.city=", nil)
+
+	var u formUser
+	err := validator.BindForm(r, &u)
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"], HasError, validator.ErrMinString(3, 2))
+	c.Assert(errs["Age"], HasError, validator.ErrInvalid)
+	c.Assert(errs["Age"], HasError, validator.ErrMinInt(18, 0))
+	c.Assert(errs["Tags"], HasError, validator.ErrMaxArray(2, 3))
+	c.Assert(errs["Address.City"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Work.City"], HasError, validator.ErrZeroValueEmpty)
+
+	c.Assert(validator.BindForm(r, u), Equals, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestBindFormMultipart(c *C) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("name", "Joe")
+	w.WriteField("age", "20")
+	w.WriteField("address.city", "Paris")
+	w.Close()
+	r, err := http.NewRequest("POST", "/", &body)
+	c.Assert(err, IsNil)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+
+	var u formUser
+	c.Assert(validator.BindForm(r, &u), IsNil)
+	c.Assert(u.Name, Equals, "Joe")
+	c.Assert(u.Age, Equals, 20)
+}
+
+func (ms *MySuite) TestBindFormNameFunc(c *C) {
+	r := httptest.NewRequest("GET", "/?age=abc", nil)
+	v := validator.New(validator.WithNameFunc(validator.TagNameFunc("form")))
+
+	var u formUser
+	errs, ok := v.BindForm(r, &u).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["age"], HasError, validator.ErrInvalid)
+	c.Assert(errs["address.city"], HasError, validator.ErrZeroValueEmpty)
+}
+
+type jsonSignup struct {
+	Email string `json:"email" validate:"nonzero"`
+	Age   int    `json:"age" validate:"min=18"`
+}
+
+func (ms *MySuite) TestJSONHandler(c *C) {
+	var got *jsonSignup
+	h := validator.JSONHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = validator.RequestValue(r).(*jsonSignup)
+	}), jsonSignup{})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"email":"joe@example.com","age":20}`)))
+	c.Assert(w.Code, Equals, http.StatusOK)
+	c.Assert(got, DeepEquals, &jsonSignup{Email: "joe@example.com", Age: 20})
+
+	got = nil
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"age":12}`)))
+	c.Assert(w.Code, Equals, http.StatusUnprocessableEntity)
+	c.Assert(w.Header().Get("Content-Type"), Equals, "application/json; charset=utf-8")
+	c.Assert(w.Body.String(), Equals, `{"errors":{"age":["Must be at least 18, was 12"],"email":["Must not be empty"]}}`+"\n")
+	c.Assert(got, IsNil)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"age":`)))
+	c.Assert(w.Code, Equals, http.StatusBadRequest)
+	c.Assert(got, IsNil)
+}
+
+func (ms *MySuite) TestJSONHandlerMaxBodySize(c *C) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	body := `{"email":"joe@example.com","age":20}`
+
+	w := httptest.NewRecorder()
+	h := validator.JSONHandler(next, jsonSignup{}, validator.WithMaxBodySize(10))
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	c.Assert(w.Code, Equals, http.StatusRequestEntityTooLarge)
+
+	w = httptest.NewRecorder()
+	h = validator.JSONHandler(next, jsonSignup{}, validator.WithMaxBodySize(int64(len(body))))
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	c.Assert(w.Code, Equals, http.StatusOK)
+
+	large := `{"email":"joe@example.com","age":20,"pad":"` + strings.Repeat("x", validator.DefaultMaxBodySize) + `"}`
+	w = httptest.NewRecorder()
+	h = validator.JSONHandler(next, jsonSignup{})
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(large)))
+	c.Assert(w.Code, Equals, http.StatusRequestEntityTooLarge)
+
+	w = httptest.NewRecorder()
+	h = validator.JSONHandler(next, jsonSignup{}, validator.WithMaxBodySize(-1))
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(large)))
+	c.Assert(w.Code, Equals, http.StatusOK)
+}
+
+func (ms *MySuite) TestJSONHandlerNotStruct(c *C) {
+	c.Assert(func() { validator.JSONHandler(http.NotFoundHandler(), 42) }, PanicMatches, ".*not a struct")
+}
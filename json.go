@@ -0,0 +1,110 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// JSONDecoder reads and decodes JSON values from an input stream, as
+// json.Decoder does, and validates them.
+type JSONDecoder struct {
+	*json.Decoder
+	mv *Validator
+}
+
+// NewJSONDecoder returns a JSONDecoder reading from r.
+func NewJSONDecoder(r io.Reader) *JSONDecoder {
+	return defaultValidator.NewJSONDecoder(r)
+}
+
+// NewJSONDecoder returns a JSONDecoder reading from r.
+func (mv *Validator) NewJSONDecoder(r io.Reader) *JSONDecoder {
+	return &JSONDecoder{json.NewDecoder(r), mv}
+}
+
+// Decode decodes the next JSON value from its input into v, as
+// json.Decoder does, and validates it. The errors of decoding are
+// returned as they are, and the errors of validation as an ErrorMap
+// whose paths are made of the JSON keys of the fields, e.g.
+// address.city, unless the validator has a NameFunc of its own. The
+// structs held by slices and maps decoded are validated too.
+func (d *JSONDecoder) Decode(v interface{}) error {
+	if err := d.Decoder.Decode(v); err != nil {
+		return err
+	}
+	return d.mv.validateJSON(v)
+}
+
+// JSONEncoder writes JSON values to an output stream, as json.Encoder
+// does, once they are validated.
+type JSONEncoder struct {
+	*json.Encoder
+	mv *Validator
+}
+
+// NewJSONEncoder returns a JSONEncoder writing to w.
+func NewJSONEncoder(w io.Writer) *JSONEncoder {
+	return defaultValidator.NewJSONEncoder(w)
+}
+
+// NewJSONEncoder returns a JSONEncoder writing to w.
+func (mv *Validator) NewJSONEncoder(w io.Writer) *JSONEncoder {
+	return &JSONEncoder{json.NewEncoder(w), mv}
+}
+
+// Encode validates v, as JSONDecoder.Decode does, and writes its JSON
+// encoding to the stream, as json.Encoder does. Invalid values are not
+// written, their errors being returned instead.
+func (e *JSONEncoder) Encode(v interface{}) error {
+	if err := e.mv.validateJSON(v); err != nil {
+		return err
+	}
+	return e.Encoder.Encode(v)
+}
+
+// validateJSON validates the value v, decoded from JSON or to be encoded
+// to it, naming its fields as encoding/json does.
+func (mv *Validator) validateJSON(v interface{}) error {
+	if mv.nameFunc == nil {
+		mv = mv.with(WithNameFunc(jsonTagName), WithPromotedFields())
+	}
+	return mv.validateDecoded(v)
+}
+
+// validateDecoded validates the value v decoded from a document: structs
+// as Validate does and the structs held by slices and maps.
+func (mv *Validator) validateDecoded(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	m := make(ErrorMap)
+	if rv.Kind() == reflect.Struct {
+		// as for Validate, the Validate method of v itself is not called
+		mv.validateStruct(context.Background(), rv, "", m, true)
+	} else {
+		mv.validateDeep(context.Background(), rv, "", m, true)
+	}
+	if len(m) > 0 {
+		return m
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type jsonAddress struct {
+	City string `json:"city" validate:"nonzero"`
+}
+
+type jsonAccount struct {
+	Email    string        `json:"email" validate:"nonzero"`
+	Nick     string        `validate:"max=8"`
+	Address  jsonAddress   `json:"address"`
+	Previous []jsonAddress `json:"previous"`
+}
+
+func (ms *MySuite) TestJSONDecoder(c *C) {
+	d := validator.NewJSONDecoder(strings.NewReader(`
+		{"email":"joe@example.com","address":{"city":"Paris"}}
+		{"Nick":"joe the great","previous":[{"city":"Rome"},{}]}
+		{"email":
+	`))
+
+	var a jsonAccount
+	c.Assert(d.Decode(&a), IsNil)
+	c.Assert(a.Address.City, Equals, "Paris")
+
+	a = jsonAccount{}
+	errs, ok := d.Decode(&a).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Nick", "address.city", "email", "previous[1].city"})
+	c.Assert(errs["Nick"], HasError, validator.ErrMaxString(8, 13))
+
+	// decoding errors are returned as they are
+	_, ok = d.Decode(&a).(validator.ErrorMap)
+	c.Assert(ok, Equals, false)
+
+	// the structs of slices are validated
+	var list []jsonAddress
+	d = validator.NewJSONDecoder(strings.NewReader(`[{"city":"Paris"},{"city":""}]`))
+	errs, ok = d.Decode(&list).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"[1].city"})
+
+	// as are other values, with nothing to validate
+	var n int
+	c.Assert(validator.NewJSONDecoder(strings.NewReader(`42`)).Decode(&n), IsNil)
+	c.Assert(n, Equals, 42)
+}
+
+func (ms *MySuite) TestJSONDecoderNameFunc(c *C) {
+	v := validator.New(validator.WithNameFunc(validator.TagNameFunc("validate_name")))
+	type test struct {
+		Name string `json:"name" validate:"nonzero" validate_name:"Name"`
+	}
+	errs, ok := v.NewJSONDecoder(strings.NewReader(`{}`)).Decode(&test{}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Name"})
+}
+
+func (ms *MySuite) TestJSONEncoder(c *C) {
+	var b bytes.Buffer
+	e := validator.NewJSONEncoder(&b)
+	c.Assert(e.Encode(jsonAddress{"Paris"}), IsNil)
+	errs, ok := e.Encode(&jsonAccount{Email: "joe@example.com"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"address.city"})
+	c.Assert(b.String(), Equals, `{"city":"Paris"}`+"\n")
+}
@@ -0,0 +1,365 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the documents
+// returned by JSONSchema.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema document describing the struct v,
+// or the struct v points to, with its validation tags converted to
+// the equivalent keywords. Rules without an equivalent are left out.
+func JSONSchema(v interface{}) ([]byte, error) {
+	return defaultValidator.JSONSchema(v)
+}
+
+// JSONSchema returns a JSON Schema document describing the struct v,
+// or the struct v points to, with its validation tags converted to
+// the equivalent keywords. Rules without an equivalent are left out.
+func (mv *Validator) JSONSchema(v interface{}) ([]byte, error) {
+	t, err := structType(v)
+	if err != nil {
+		return nil, err
+	}
+	g := &schemaGen{mv: mv, defs: map[string]interface{}{}}
+	g.ref = func(st reflect.Type) string {
+		if st == t {
+			return "#"
+		}
+		return "#/$defs/" + st.Name()
+	}
+	doc, err := g.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	doc["$schema"] = jsonSchemaDraft
+	if len(g.defs) > 0 {
+		doc["$defs"] = g.defs
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// structType returns the struct type of v, or of the struct v points to.
+func structType(v interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, ErrUnsupported
+	}
+	return t, nil
+}
+
+// schemaRule sets the keywords of schema s, of a value of kind k,
+// equivalent to a validation rule with the given parameter.
+type schemaRule func(s map[string]interface{}, k reflect.Kind, param string)
+
+func schemaMin(s map[string]interface{}, k reflect.Kind, param string) {
+	setSizeKeywords(s, k, param, "min")
+	if isNumberKind(k) {
+		setNumberKeyword(s, "minimum", param)
+	}
+}
+
+func schemaMax(s map[string]interface{}, k reflect.Kind, param string) {
+	setSizeKeywords(s, k, param, "max")
+	if isNumberKind(k) {
+		setNumberKeyword(s, "maximum", param)
+	}
+}
+
+// schemaRules holds the schema equivalents of the builtin rules.
+var schemaRules = map[string]schemaRule{
+	"len": func(s map[string]interface{}, k reflect.Kind, param string) {
+		setSizeKeywords(s, k, param, "min", "max")
+		if isNumberKind(k) {
+			setNumberKeyword(s, "const", param)
+		}
+	},
+	"min": schemaMin,
+	"max": schemaMax,
+	"gt": func(s map[string]interface{}, k reflect.Kind, param string) {
+		if isNumberKind(k) {
+			setNumberKeyword(s, "exclusiveMinimum", param)
+		}
+	},
+	"lt": func(s map[string]interface{}, k reflect.Kind, param string) {
+		if isNumberKind(k) {
+			setNumberKeyword(s, "exclusiveMaximum", param)
+		}
+	},
+	"between": func(s map[string]interface{}, k reflect.Kind, param string) {
+		if bounds := SplitParams(param); len(bounds) == 2 {
+			schemaMin(s, k, bounds[0])
+			schemaMax(s, k, bounds[1])
+		}
+	},
+	"gte": func(s map[string]interface{}, k reflect.Kind, param string) {
+		if isNumberKind(k) {
+			setNumberKeyword(s, "minimum", param)
+		}
+	},
+	"lte": func(s map[string]interface{}, k reflect.Kind, param string) {
+		if isNumberKind(k) {
+			setNumberKeyword(s, "maximum", param)
+		}
+	},
+	"eq": func(s map[string]interface{}, k reflect.Kind, param string) {
+		if isNumberKind(k) {
+			setNumberKeyword(s, "const", param)
+		}
+	},
+	"ne": func(s map[string]interface{}, k reflect.Kind, param string) {
+		if _, err := strconv.ParseFloat(param, 64); err == nil && isNumberKind(k) {
+			excludeValue(s, json.Number(param))
+		}
+	},
+	"multipleof": func(s map[string]interface{}, k reflect.Kind, param string) {
+		if isNumberKind(k) {
+			setNumberKeyword(s, "multipleOf", param)
+		}
+	},
+	"regexp": func(s map[string]interface{}, k reflect.Kind, param string) {
+		if k == reflect.String {
+			s["pattern"] = param
+		}
+	},
+	"datetime": func(s map[string]interface{}, k reflect.Kind, param string) {
+		if k != reflect.String {
+			return
+		}
+		layout, ok := datetimeLayouts[strings.ToLower(param)]
+		if !ok {
+			layout = param
+		}
+		switch layout {
+		case time.RFC3339, time.RFC3339Nano:
+			s["format"] = "date-time"
+		case datetimeLayouts["date"]:
+			s["format"] = "date"
+		}
+	},
+	"nonzero": func(s map[string]interface{}, k reflect.Kind, param string) {
+		switch {
+		case k == reflect.Bool:
+			s["const"] = true
+		case isNumberKind(k):
+			excludeValue(s, 0)
+		default:
+			for _, kw := range sizeKeywords(k, "min") {
+				if _, ok := s[kw]; !ok {
+					s[kw] = 1
+				}
+			}
+		}
+	},
+}
+
+// excludeValue makes the schema s reject v on top of the values it
+// already excludes, turning the const of its not subschema into an
+// enum for several values.
+func excludeValue(s map[string]interface{}, v interface{}) {
+	not, ok := s["not"].(map[string]interface{})
+	if !ok {
+		s["not"] = map[string]interface{}{"const": v}
+		return
+	}
+	if c, ok := not["const"]; ok {
+		delete(not, "const")
+		not["enum"] = []interface{}{c}
+	}
+	enum, _ := not["enum"].([]interface{})
+	not["enum"] = append(enum, v)
+}
+
+// sizeKeywords returns the keywords bounding the size of a value of
+// kind k, for the given bounds ("min" or "max").
+func sizeKeywords(k reflect.Kind, bounds ...string) []string {
+	var suffix string
+	switch k {
+	case reflect.String:
+		suffix = "Length"
+	case reflect.Slice, reflect.Array:
+		suffix = "Items"
+	case reflect.Map:
+		suffix = "Properties"
+	default:
+		return nil
+	}
+	kws := make([]string, len(bounds))
+	for i, b := range bounds {
+		kws[i] = b + suffix
+	}
+	return kws
+}
+
+func setSizeKeywords(s map[string]interface{}, k reflect.Kind, param string, bounds ...string) {
+	for _, kw := range sizeKeywords(k, bounds...) {
+		setNumberKeyword(s, kw, param)
+	}
+}
+
+// setNumberKeyword sets the keyword kw of s to param, if it is a number.
+func setNumberKeyword(s map[string]interface{}, kw, param string) {
+	if _, err := strconv.ParseFloat(param, 64); err == nil {
+		s[kw] = json.Number(param)
+	}
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaGen generates the schemas of Go types. Named structs are
+// added to defs and referenced with the reference returned by ref.
+type schemaGen struct {
+	mv   *Validator
+	defs map[string]interface{}
+	ref  func(reflect.Type) string
+}
+
+// typeSchema returns the schema of values of type t.
+func (g *schemaGen) typeSchema(t reflect.Type) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	}
+	switch k := t.Kind(); {
+	case k == reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case k == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case k == reflect.Float32 || k == reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case isNumberKind(k):
+		return map[string]interface{}{"type": "integer"}, nil
+	case k == reflect.Slice || k == reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes byte slices as base64 strings
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case k == reflect.Map:
+		values, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	case k == reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		ref := g.ref(t)
+		if _, ok := g.defs[t.Name()]; !ok && ref != "#" {
+			// set first, as the struct may refer to itself
+			g.defs[t.Name()] = nil
+			s, err := g.structSchema(t)
+			if err != nil {
+				return nil, err
+			}
+			g.defs[t.Name()] = s
+		}
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+	return map[string]interface{}{}, nil
+}
+
+// structSchema returns the object schema of the struct type t.
+func (g *schemaGen) structSchema(t reflect.Type) (map[string]interface{}, error) {
+	props := map[string]interface{}{}
+	var required []string
+	if err := g.addProperties(t, props, &required); err != nil {
+		return nil, err
+	}
+	s := map[string]interface{}{"type": "object", "properties": props}
+	if t.Name() != "" {
+		s["title"] = t.Name()
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s, nil
+}
+
+// addProperties adds the schemas of the fields of the struct type t to
+// props, and the names of the fields which must not be zero to required.
+// The fields of embedded structs are added as encoding/json does.
+func (g *schemaGen) addProperties(t reflect.Type, props map[string]interface{}, required *[]string) error {
+	for _, sf := range g.mv.structFields(t) {
+		if sf.err != nil {
+			return sf.err
+		}
+		f := sf.field
+		if f.PkgPath != "" && !f.Anonymous || f.Tag.Get("json") == "-" {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && sf.jsonName == "" && ft.Kind() == reflect.Struct {
+			if err := g.addProperties(ft, props, required); err != nil {
+				return err
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		s, err := g.typeSchema(ft)
+		if err != nil {
+			return err
+		}
+		name := f.Name
+		if sf.jsonName != "" {
+			name = sf.jsonName
+		}
+		for _, t := range sf.tags {
+			if t.Name == "nonzero" || t.Name == "required" || t.Name == "notnil" {
+				*required = append(*required, name)
+			}
+			if rule, ok := schemaRules[t.Name]; ok && ft != timeType && ft.Kind() != reflect.Struct {
+				rule(s, ft.Kind(), t.Param)
+			}
+		}
+		props[name] = s
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type schemaAddress struct {
+	City string `json:"city" validate:"nonzero"`
+}
+
+type schemaBase struct {
+	ID int `json:"id" validate:"min=1"`
+}
+
+type schemaUser struct {
+	schemaBase
+	Name     string            `json:"name" validate:"nonzero,max=40"`
+	Code     string            `validate:"len=3,regexp=^[A-Z]+$"`
+	Age      *int              `json:"age,omitempty" validate:"min=18,max=130"`
+	Tags     []string          `json:"tags" validate:"max=5"`
+	Labels   map[string]string `json:"labels"`
+	Created  time.Time         `json:"created"`
+	Address  schemaAddress     `json:"address" validate:"nonzero"`
+	Previous []schemaAddress   `json:"previous"`
+	Manager  *schemaUser       `json:"manager"`
+	Secret   string            `json:"-"`
+	internal string
+}
+
+func (ms *MySuite) TestJSONSchema(c *C) {
+	b, err := validator.JSONSchema(&schemaUser{})
+	c.Assert(err, IsNil)
+	var doc map[string]interface{}
+	c.Assert(json.Unmarshal(b, &doc), IsNil)
+
+	c.Assert(doc["$schema"], Equals, "https://json-schema.org/draft/2020-12/schema")
+	c.Assert(doc["type"], Equals, "object")
+	c.Assert(doc["required"], DeepEquals, []interface{}{"name", "address"})
+
+	props := doc["properties"].(map[string]interface{})
+	c.Assert(props, HasLen, 10)
+	c.Assert(props["id"], DeepEquals, map[string]interface{}{"type": "integer", "minimum": 1.0})
+	c.Assert(props["name"], DeepEquals, map[string]interface{}{"type": "string", "minLength": 1.0, "maxLength": 40.0})
+	c.Assert(props["Code"], DeepEquals, map[string]interface{}{
+		"type": "string", "minLength": 3.0, "maxLength": 3.0, "pattern": "^[A-Z]+$",
+	})
+	c.Assert(props["age"], DeepEquals, map[string]interface{}{"type": "integer", "minimum": 18.0, "maximum": 130.0})
+	c.Assert(props["tags"], DeepEquals, map[string]interface{}{
+		"type": "array", "items": map[string]interface{}{"type": "string"}, "maxItems": 5.0,
+	})
+	c.Assert(props["labels"], DeepEquals, map[string]interface{}{
+		"type": "object", "additionalProperties": map[string]interface{}{"type": "string"},
+	})
+	c.Assert(props["created"], DeepEquals, map[string]interface{}{"type": "string", "format": "date-time"})
+	c.Assert(props["address"], DeepEquals, map[string]interface{}{"$ref": "#/$defs/schemaAddress"})
+	c.Assert(props["previous"], DeepEquals, map[string]interface{}{
+		"type": "array", "items": map[string]interface{}{"$ref": "#/$defs/schemaAddress"},
+	})
+	c.Assert(props["manager"], DeepEquals, map[string]interface{}{"$ref": "#"})
+
+	defs := doc["$defs"].(map[string]interface{})
+	c.Assert(defs["schemaAddress"], DeepEquals, map[string]interface{}{
+		"type":       "object",
+		"title":      "schemaAddress",
+		"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string", "minLength": 1.0}},
+		"required":   []interface{}{"city"},
+	})
+}
+
+func (ms *MySuite) TestJSONSchemaKeywords(c *C) {
+	b, err := validator.JSONSchema(struct {
+		Birthday string  `validate:"datetime=date"`
+		Created  string  `validate:"datetime=2006-01-02T15:04:05Z07:00"`
+		Kitchen  string  `validate:"datetime=kitchen"`
+		Rate     float64 `validate:"gt=0,lt=1"`
+		Price    float64 `validate:"multipleof=0.05"`
+	}{})
+	c.Assert(err, IsNil)
+	var doc map[string]interface{}
+	c.Assert(json.Unmarshal(b, &doc), IsNil)
+	props := doc["properties"].(map[string]interface{})
+	c.Assert(props["Birthday"], DeepEquals, map[string]interface{}{"type": "string", "format": "date"})
+	c.Assert(props["Created"], DeepEquals, map[string]interface{}{"type": "string", "format": "date-time"})
+	c.Assert(props["Kitchen"], DeepEquals, map[string]interface{}{"type": "string"})
+	c.Assert(props["Rate"], DeepEquals, map[string]interface{}{"type": "number", "exclusiveMinimum": 0.0, "exclusiveMaximum": 1.0})
+	c.Assert(props["Price"], DeepEquals, map[string]interface{}{"type": "number", "multipleOf": 0.05})
+}
+
+func (ms *MySuite) TestJSONSchemaErrors(c *C) {
+	_, err := validator.JSONSchema(42)
+	c.Assert(err, Equals, validator.ErrUnsupported)
+
+	type unknown struct {
+		A string `validate:"foo"`
+	}
+	_, err = validator.JSONSchema(unknown{})
+	c.Assert(err, Equals, validator.ErrUnknownTag)
+}
+
+func (ms *MySuite) TestJSONSchemaComparisons(c *C) {
+	b, err := validator.JSONSchema(struct {
+		Discount float64 `validate:"gte=0,lte=1"`
+		Version  int     `validate:"eq=2"`
+		Stock    int     `validate:"ne=0"`
+		Rank     int     `validate:"nonzero,ne=5"`
+	}{})
+	c.Assert(err, IsNil)
+	var doc map[string]interface{}
+	c.Assert(json.Unmarshal(b, &doc), IsNil)
+	props := doc["properties"].(map[string]interface{})
+	c.Assert(props["Discount"], DeepEquals, map[string]interface{}{"type": "number", "minimum": 0.0, "maximum": 1.0})
+	c.Assert(props["Version"], DeepEquals, map[string]interface{}{"type": "integer", "const": 2.0})
+	c.Assert(props["Stock"], DeepEquals, map[string]interface{}{"type": "integer", "not": map[string]interface{}{"const": 0.0}})
+	c.Assert(props["Rank"], DeepEquals, map[string]interface{}{"type": "integer", "not": map[string]interface{}{"enum": []interface{}{0.0, 5.0}}})
+}
+
+func (ms *MySuite) TestJSONSchemaBetween(c *C) {
+	b, err := validator.JSONSchema(struct {
+		Age  int      `validate:"between=18:130"`
+		Name string   `validate:"between=1:40"`
+		Tags []string `validate:"between=1 5"`
+	}{})
+	c.Assert(err, IsNil)
+	var doc map[string]interface{}
+	c.Assert(json.Unmarshal(b, &doc), IsNil)
+	props := doc["properties"].(map[string]interface{})
+	c.Assert(props["Age"], DeepEquals, map[string]interface{}{"type": "integer", "minimum": 18.0, "maximum": 130.0})
+	c.Assert(props["Name"], DeepEquals, map[string]interface{}{"type": "string", "minLength": 1.0, "maxLength": 40.0})
+	c.Assert(props["Tags"].(map[string]interface{})["minItems"], Equals, 1.0)
+	c.Assert(props["Tags"].(map[string]interface{})["maxItems"], Equals, 5.0)
+}
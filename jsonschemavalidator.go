@@ -0,0 +1,456 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONSchemaValidator validates decoded JSON values, such as dynamic
+// payloads without a Go struct, against a JSON Schema. It supports the
+// validation keywords of the schemas returned by JSONSchema along with
+// enum, const, not, allOf, anyOf, the exclusive bounds and references
+// to "#" and to the schemas of $defs and definitions. Other keywords
+// are ignored. Patterns are Go regular expressions and multipleOf is
+// checked exactly, as by the multipleof rule.
+type JSONSchemaValidator struct {
+	root *jsonSchema
+}
+
+// CompileJSONSchema compiles the JSON Schema document schema into a
+// validator.
+func CompileJSONSchema(schema []byte) (*JSONSchemaValidator, error) {
+	var doc interface{}
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return nil, err
+	}
+	c := &schemaCompiler{refs: map[string]*jsonSchema{}}
+	root, err := c.compile(doc)
+	if err != nil {
+		return nil, err
+	}
+	c.refs["#"] = root
+	if m, ok := doc.(map[string]interface{}); ok {
+		for _, kw := range []string{"$defs", "definitions"} {
+			defs, _ := m[kw].(map[string]interface{})
+			for name, def := range defs {
+				s, err := c.compile(def)
+				if err != nil {
+					return nil, err
+				}
+				c.refs["#/"+kw+"/"+name] = s
+			}
+		}
+	}
+	for _, s := range c.pending {
+		if s.ref = c.refs[s.refName]; s.ref == nil {
+			return nil, fmt.Errorf("validator: unresolved schema reference %q", s.refName)
+		}
+	}
+	return &JSONSchemaValidator{root: root}, nil
+}
+
+// Validate validates v, either a json.RawMessage or []byte holding a
+// JSON document or a value as decoded by encoding/json, such as a
+// map[string]interface{}. It returns an ErrorMap indexed by the path of
+// the invalid values, as for structs, with the properties not declared
+// by the schema and the array indexes in brackets. The errors of the
+// document itself are indexed by "".
+func (sv *JSONSchemaValidator) Validate(v interface{}) error {
+	switch b := v.(type) {
+	case json.RawMessage:
+		return sv.Validate([]byte(b))
+	case []byte:
+		var doc interface{}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+		v = doc
+	}
+	m := make(ErrorMap)
+	sv.root.validate(v, "", m)
+	if len(m) > 0 {
+		return m
+	}
+	return nil
+}
+
+// jsonSchema is a compiled JSON Schema.
+type jsonSchema struct {
+	// never is set for the false schema, rejecting any value.
+	never bool
+
+	types      []string
+	properties map[string]*jsonSchema
+	required   []string
+	additional *jsonSchema
+	items      *jsonSchema
+
+	minLength, maxLength, minItems, maxItems, minProperties, maxProperties *int64
+	minimum, maximum, exclusiveMinimum, exclusiveMaximum                   *float64
+	// multipleOf is checked exactly as by the multipleof rule, the
+	// numbers being taken as the shortest decimals representing them.
+	multipleOf     *big.Rat
+	multipleOfText string
+
+	pattern  *regexp.Regexp
+	enum     []interface{}
+	constant interface{}
+	hasConst bool
+	not      *jsonSchema
+	allOf    []*jsonSchema
+	anyOf    []*jsonSchema
+
+	refName string
+	ref     *jsonSchema
+}
+
+// schemaCompiler compiles the schemas of a document, keeping track of
+// the references to resolve once all of them are compiled.
+type schemaCompiler struct {
+	refs    map[string]*jsonSchema
+	pending []*jsonSchema
+}
+
+var errBadSchema = errors.New("validator: invalid JSON Schema")
+
+func (c *schemaCompiler) compile(doc interface{}) (*jsonSchema, error) {
+	s := &jsonSchema{}
+	switch d := doc.(type) {
+	case bool:
+		s.never = !d
+		return s, nil
+	case map[string]interface{}:
+		return s, c.compileKeywords(s, d)
+	}
+	return nil, errBadSchema
+}
+
+func (c *schemaCompiler) compileKeywords(s *jsonSchema, d map[string]interface{}) error {
+	var err error
+	if ref, ok := d["$ref"].(string); ok {
+		s.refName = ref
+		c.pending = append(c.pending, s)
+	}
+	switch t := d["type"].(type) {
+	case string:
+		s.types = []string{t}
+	case []interface{}:
+		for _, e := range t {
+			name, ok := e.(string)
+			if !ok {
+				return errBadSchema
+			}
+			s.types = append(s.types, name)
+		}
+	}
+	if props, ok := d["properties"].(map[string]interface{}); ok {
+		s.properties = make(map[string]*jsonSchema, len(props))
+		for name, p := range props {
+			if s.properties[name], err = c.compile(p); err != nil {
+				return err
+			}
+		}
+	}
+	if req, ok := d["required"].([]interface{}); ok {
+		for _, e := range req {
+			name, ok := e.(string)
+			if !ok {
+				return errBadSchema
+			}
+			s.required = append(s.required, name)
+		}
+	}
+	for kw, dst := range map[string]**jsonSchema{
+		"additionalProperties": &s.additional,
+		"items":                &s.items,
+		"not":                  &s.not,
+	} {
+		if sub, ok := d[kw]; ok {
+			if *dst, err = c.compile(sub); err != nil {
+				return err
+			}
+		}
+	}
+	for kw, dst := range map[string]*[]*jsonSchema{"allOf": &s.allOf, "anyOf": &s.anyOf} {
+		subs, _ := d[kw].([]interface{})
+		for _, sub := range subs {
+			cs, err := c.compile(sub)
+			if err != nil {
+				return err
+			}
+			*dst = append(*dst, cs)
+		}
+	}
+	for kw, dst := range map[string]**int64{
+		"minLength": &s.minLength, "maxLength": &s.maxLength,
+		"minItems": &s.minItems, "maxItems": &s.maxItems,
+		"minProperties": &s.minProperties, "maxProperties": &s.maxProperties,
+	} {
+		if n, ok := d[kw].(float64); ok {
+			i := int64(n)
+			*dst = &i
+		}
+	}
+	for kw, dst := range map[string]**float64{
+		"minimum": &s.minimum, "maximum": &s.maximum,
+		"exclusiveMinimum": &s.exclusiveMinimum, "exclusiveMaximum": &s.exclusiveMaximum,
+	} {
+		if n, ok := d[kw].(float64); ok {
+			*dst = &n
+		}
+	}
+	if n, ok := d["multipleOf"].(float64); ok {
+		if s.multipleOf, ok = floatRat(n, 64); !ok || s.multipleOf.Sign() <= 0 {
+			return errBadSchema
+		}
+		s.multipleOfText = strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	if p, ok := d["pattern"].(string); ok {
+		if s.pattern, err = regexp.Compile(p); err != nil {
+			return err
+		}
+	}
+	if e, ok := d["enum"].([]interface{}); ok {
+		s.enum = e
+	}
+	s.constant, s.hasConst = d["const"]
+	return nil
+}
+
+// validate validates v against s, adding the errors found to m under
+// path.
+func (s *jsonSchema) validate(v interface{}, path string, m ErrorMap) {
+	if s.never {
+		m[path] = append(m[path], ErrInvalid)
+		return
+	}
+	if s.ref != nil {
+		s.ref.validate(v, path, m)
+	}
+	if len(s.types) > 0 && !s.hasType(v) {
+		m[path] = append(m[path], TextErr{fmt.Errorf("Must be of type %s", strings.Join(s.types, " or "))})
+		return
+	}
+	if s.hasConst && !jsonEqual(v, s.constant) {
+		m[path] = append(m[path], ErrInvalid)
+	}
+	if s.enum != nil && !s.inEnum(v) {
+		m[path] = append(m[path], ErrInvalid)
+	}
+	if s.not != nil && s.not.valid(v) {
+		m[path] = append(m[path], ErrInvalid)
+	}
+	for _, sub := range s.allOf {
+		sub.validate(v, path, m)
+	}
+	if len(s.anyOf) > 0 {
+		valid := false
+		for _, sub := range s.anyOf {
+			if sub.valid(v) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			m[path] = append(m[path], ErrInvalid)
+		}
+	}
+
+	switch x := v.(type) {
+	case string:
+		s.validateString(x, path, m)
+	case []interface{}:
+		s.validateArray(x, path, m)
+	case map[string]interface{}:
+		s.validateObject(x, path, m)
+	default:
+		if f, ok := jsonNumber(v); ok {
+			s.validateNumber(f, path, m)
+		}
+	}
+}
+
+// valid reports whether v is valid against s.
+func (s *jsonSchema) valid(v interface{}) bool {
+	m := make(ErrorMap)
+	s.validate(v, "", m)
+	return len(m) == 0
+}
+
+func (s *jsonSchema) validateString(x, path string, m ErrorMap) {
+	n := utf8.RuneCountInString(x)
+	if s.minLength != nil && int64(n) < *s.minLength {
+		m[path] = append(m[path], ErrMinString(*s.minLength, n))
+	}
+	if s.maxLength != nil && int64(n) > *s.maxLength {
+		m[path] = append(m[path], ErrMaxString(*s.maxLength, n))
+	}
+	if s.pattern != nil && !s.pattern.MatchString(x) {
+		m[path] = append(m[path], ErrRegexpDetailed(s.pattern.String()))
+	}
+}
+
+func (s *jsonSchema) validateArray(x []interface{}, path string, m ErrorMap) {
+	n := len(x)
+	if s.minItems != nil && int64(n) < *s.minItems {
+		m[path] = append(m[path], ErrMinArray(*s.minItems, n))
+	}
+	if s.maxItems != nil && int64(n) > *s.maxItems {
+		m[path] = append(m[path], ErrMaxArray(*s.maxItems, n))
+	}
+	if s.items != nil {
+		for i, e := range x {
+			s.items.validate(e, path+"["+strconv.Itoa(i)+"]", m)
+		}
+	}
+}
+
+func (s *jsonSchema) validateObject(x map[string]interface{}, path string, m ErrorMap) {
+	n := len(x)
+	if s.minProperties != nil && int64(n) < *s.minProperties {
+		m[path] = append(m[path], ErrMinArray(*s.minProperties, n))
+	}
+	if s.maxProperties != nil && int64(n) > *s.maxProperties {
+		m[path] = append(m[path], ErrMaxArray(*s.maxProperties, n))
+	}
+	for _, name := range s.required {
+		if _, ok := x[name]; !ok {
+			p := joinPath(path, name)
+			m[p] = append(m[p], ErrRequired)
+		}
+	}
+	for name, e := range x {
+		if p, ok := s.properties[name]; ok {
+			p.validate(e, joinPath(path, name), m)
+		} else if s.additional != nil {
+			s.additional.validate(e, path+"["+name+"]", m)
+		}
+	}
+}
+
+func (s *jsonSchema) validateNumber(f float64, path string, m ErrorMap) {
+	if s.minimum != nil && f < *s.minimum {
+		m[path] = append(m[path], numberErr(ErrMinInt, ErrMinFloat, *s.minimum, f))
+	}
+	if s.maximum != nil && f > *s.maximum {
+		m[path] = append(m[path], numberErr(ErrMaxInt, ErrMaxFloat, *s.maximum, f))
+	}
+	if s.exclusiveMinimum != nil && f <= *s.exclusiveMinimum {
+		m[path] = append(m[path], ErrMin)
+	}
+	if s.exclusiveMaximum != nil && f >= *s.exclusiveMaximum {
+		m[path] = append(m[path], ErrMax)
+	}
+	if s.multipleOf != nil {
+		if r, ok := floatRat(f, 64); !ok || !r.Quo(r, s.multipleOf).IsInt() {
+			m[path] = append(m[path], ErrMultipleOf(s.multipleOfText))
+		}
+	}
+}
+
+// numberErr returns the error of the bound b for the number f, as an
+// integer when both are integers.
+func numberErr(intErr func(int64, int64) TextErr, floatErr func(float64, float64) TextErr, b, f float64) TextErr {
+	if b == math.Trunc(b) && f == math.Trunc(f) {
+		return intErr(int64(b), int64(f))
+	}
+	return floatErr(b, f)
+}
+
+// hasType reports whether v is of one of the types of s.
+func (s *jsonSchema) hasType(v interface{}) bool {
+	for _, t := range s.types {
+		switch t {
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		case "number":
+			if _, ok := jsonNumber(v); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := jsonNumber(v); ok && f == math.Trunc(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *jsonSchema) inEnum(v interface{}) bool {
+	for _, e := range s.enum {
+		if jsonEqual(v, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonNumber returns the value of v if it is a number, as a float64.
+func jsonNumber(v interface{}) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// jsonEqual reports whether the JSON values a and b are equal,
+// numbers being compared by value.
+func jsonEqual(a, b interface{}) bool {
+	if fa, ok := jsonNumber(a); ok {
+		fb, ok := jsonNumber(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
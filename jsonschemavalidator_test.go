@@ -0,0 +1,126 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/json"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestJSONSchemaValidator(c *C) {
+	schema, err := validator.JSONSchema(schemaUser{})
+	c.Assert(err, IsNil)
+	sv, err := validator.CompileJSONSchema(schema)
+	c.Assert(err, IsNil)
+
+	err = sv.Validate(json.RawMessage(`{
+		"id": 0,
+		"name": "",
+		"Code": "abcd",
+		"age": 12.5,
+		"tags": ["a", "b", "c", "d", "e", "f"],
+		"labels": {"x": 1},
+		"previous": [{"city": "Paris"}, {}],
+		"manager": {"name": "Joe", "address": {"city": ""}}
+	}`))
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["id"], HasError, validator.ErrMinInt(1, 0))
+	c.Assert(errs["name"], HasError, validator.ErrMinString(1, 0))
+	c.Assert(errs["Code"], HasError, validator.ErrMaxString(3, 4))
+	c.Assert(errs["Code"], HasError, validator.ErrRegexpDetailed("^[A-Z]+$"))
+	c.Assert(errs["age"], HasLen, 1)
+	c.Assert(errs["age"][0].Error(), Equals, "Must be of type integer")
+	c.Assert(errs["tags"], HasError, validator.ErrMaxArray(5, 6))
+	c.Assert(errs["labels[x]"], HasLen, 1)
+	c.Assert(errs["address"], HasError, validator.ErrRequired)
+	c.Assert(errs["previous[1].city"], HasError, validator.ErrRequired)
+	c.Assert(errs["manager.address.city"], HasError, validator.ErrMinString(1, 0))
+	c.Assert(errs, HasLen, 9)
+
+	c.Assert(sv.Validate(map[string]interface{}{
+		"name":    "Joe",
+		"id":      3,
+		"address": map[string]interface{}{"city": "Paris"},
+	}), IsNil)
+}
+
+func (ms *MySuite) TestJSONSchemaValidatorKeywords(c *C) {
+	sv, err := validator.CompileJSONSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"kind": {"enum": ["a", "b"]},
+			"version": {"const": 2},
+			"ratio": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1},
+			"id": {"anyOf": [{"type": "string"}, {"type": "integer"}]},
+			"name": {"not": {"const": "root"}}
+		},
+		"additionalProperties": false
+	}`))
+	c.Assert(err, IsNil)
+	errs, ok := sv.Validate([]byte(`{"kind": "c", "version": 2.0, "ratio": 1, "id": true, "name": "root", "extra": 1}`)).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["kind"], HasError, validator.ErrInvalid)
+	c.Assert(errs["version"], IsNil)
+	c.Assert(errs["ratio"], HasError, validator.ErrMax)
+	c.Assert(errs["id"], HasError, validator.ErrInvalid)
+	c.Assert(errs["name"], HasError, validator.ErrInvalid)
+	c.Assert(errs["[extra]"], HasError, validator.ErrInvalid)
+
+	errs, ok = sv.Validate([]byte(`[1]`)).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs[""], HasLen, 1)
+}
+
+func (ms *MySuite) TestJSONSchemaValidatorMultipleOf(c *C) {
+	type test struct {
+		Price float64 `json:"price" validate:"multipleof=0.05"`
+		Count int     `json:"count" validate:"multipleof=3"`
+	}
+	schema, err := validator.JSONSchema(test{})
+	c.Assert(err, IsNil)
+	sv, err := validator.CompileJSONSchema(schema)
+	c.Assert(err, IsNil)
+
+	// the same values are valid for the schema and the struct
+	for _, t := range []test{{0.15, 6}, {0.07, 6}, {0.1, 4}} {
+		doc, err := json.Marshal(t)
+		c.Assert(err, IsNil)
+		errs, _ := sv.Validate(doc).(validator.ErrorMap)
+		want, _ := validator.Validate(t).(validator.ErrorMap)
+		c.Assert(errs.Paths(), DeepEquals, want.Paths(), Commentf("%+v", t))
+	}
+	errs, ok := sv.Validate([]byte(`{"price": 0.07}`)).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["price"], HasError, validator.ErrMultipleOf("0.05"))
+
+	_, err = validator.CompileJSONSchema([]byte(`{"multipleOf": 0}`))
+	c.Assert(err, NotNil)
+}
+
+func (ms *MySuite) TestCompileJSONSchemaErrors(c *C) {
+	_, err := validator.CompileJSONSchema([]byte(`{"$ref": "#/$defs/missing"}`))
+	c.Assert(err, ErrorMatches, `validator: unresolved schema reference "#/\$defs/missing"`)
+	_, err = validator.CompileJSONSchema([]byte(`{"pattern": "("}`))
+	c.Assert(err, NotNil)
+	_, err = validator.CompileJSONSchema([]byte(`{"items": 3}`))
+	c.Assert(err, NotNil)
+}
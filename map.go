@@ -0,0 +1,125 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"sort"
+	"strconv"
+)
+
+// MapRules holds the rules of the values of a map, indexed by their key.
+// Rules are either written as in a struct tag or, for nested maps, given
+// as MapRules themselves. Nested rules are applied to each element of
+// slices of maps.
+//
+//	rules := validator.MapRules{
+//		"name": "nonzero,max=40",
+//		"address": validator.MapRules{
+//			"city": "nonzero",
+//		},
+//	}
+type MapRules map[string]interface{}
+
+// ValidateMap validates the values of data, e.g. a decoded JSON object,
+// against rules and returns the errors found indexed by the path of the
+// values, as Validate does for structs. Missing values are validated as
+// nil and values without rules are not validated.
+func ValidateMap(data map[string]interface{}, rules MapRules, opts ...Option) error {
+	return defaultValidator.ValidateMap(data, rules, opts...)
+}
+
+// ValidateMap validates the values of data, e.g. a decoded JSON object,
+// against rules and returns the errors found indexed by the path of the
+// values, as Validate does for structs. Missing values are validated as
+// nil and values without rules are not validated.
+func (mv *Validator) ValidateMap(data map[string]interface{}, rules MapRules, opts ...Option) error {
+	mv = mv.with(opts...)
+	m := make(ErrorMap)
+	mv.validateMap(context.Background(), data, rules, "", m)
+	if len(m) > 0 {
+		return m
+	}
+	return nil
+}
+
+// validateMap validates the values of data against rules, adding the
+// errors found to m under path.
+func (mv *Validator) validateMap(ctx context.Context, data map[string]interface{}, rules MapRules, path string, m ErrorMap) {
+	for _, k := range sortedKeys(rules) {
+		if mv.full(m) {
+			return
+		}
+		p := joinPath(path, k)
+		validate, descend := mv.selected(p)
+		v := data[k]
+		switch r := rules[k].(type) {
+		case string:
+			if !validate {
+				continue
+			}
+			err := mv.validWarn(ctx, v, r)
+			if errs, ok := err.(ErrorArray); ok {
+				mv.addErrors(m, p, errs...)
+			} else if err != nil {
+				mv.addErrors(m, p, err)
+			}
+		case MapRules:
+			if descend {
+				mv.validateNested(ctx, v, r, p, m)
+			}
+		case map[string]interface{}:
+			if descend {
+				mv.validateNested(ctx, v, MapRules(r), p, m)
+			}
+		default:
+			mv.addErrors(m, p, ErrBadParameter)
+		}
+	}
+}
+
+// validateNested validates v, a map or a slice of maps, against the
+// nested rules.
+func (mv *Validator) validateNested(ctx context.Context, v interface{}, rules MapRules, path string, m ErrorMap) {
+	switch x := v.(type) {
+	case nil:
+		mv.validateMap(ctx, nil, rules, path, m)
+	case map[string]interface{}:
+		mv.validateMap(ctx, x, rules, path, m)
+	case []interface{}:
+		for i, e := range x {
+			mv.validateNested(ctx, e, rules, path+"["+strconv.Itoa(i)+"]", m)
+		}
+	case []map[string]interface{}:
+		for i, e := range x {
+			mv.validateMap(ctx, e, rules, path+"["+strconv.Itoa(i)+"]", m)
+		}
+	default:
+		mv.addErrors(m, path, ErrUnsupported)
+	}
+}
+
+// sortedKeys returns the keys of rules in order, so that the values of
+// maps are validated in the same order every time.
+func sortedKeys(rules MapRules) []string {
+	keys := make([]string, 0, len(rules))
+	for k := range rules {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,71 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+var signupRules = validator.MapRules{
+	"name": "nonzero,max=10",
+	"age":  "min=18",
+	"address": validator.MapRules{
+		"city": "nonzero",
+	},
+	"contacts": map[string]interface{}{
+		"email": "nonzero,regexp=@",
+	},
+}
+
+func (ms *MySuite) TestValidateMap(c *C) {
+	err := validator.ValidateMap(map[string]interface{}{
+		"name":     "Joe",
+		"age":      float64(20),
+		"address":  map[string]interface{}{"city": "Paris"},
+		"contacts": []interface{}{map[string]interface{}{"email": "joe@example.com"}},
+		"extra":    42,
+	}, signupRules)
+	c.Assert(err, IsNil)
+
+	err = validator.ValidateMap(map[string]interface{}{
+		"name":     "Joe the third",
+		"age":      12,
+		"contacts": []interface{}{map[string]interface{}{"email": "joe@example.com"}, map[string]interface{}{"email": "joe"}, "joe"},
+	}, signupRules)
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["name"], HasError, validator.ErrMaxString(10, 13))
+	c.Assert(errs["age"], HasError, validator.ErrMinInt(18, 12))
+	c.Assert(errs["address.city"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["contacts[1].email"], HasError, validator.ErrRegexpDetailed("@"))
+	c.Assert(errs["contacts[2]"], HasError, validator.ErrUnsupported)
+	c.Assert(errs, HasLen, 5)
+}
+
+func (ms *MySuite) TestValidateMapOptions(c *C) {
+	errs, ok := validator.ValidateMap(map[string]interface{}{}, signupRules, validator.WithFailFast()).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["address.city"], HasError, validator.ErrZeroValue)
+
+	errs, ok = validator.ValidateMap(nil, validator.MapRules{"name": 42}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["name"], HasError, validator.ErrBadParameter)
+}
@@ -0,0 +1,58 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"time"
+)
+
+// Observer is notified of the outcome of validations, e.g. to export
+// metrics of the types validated and of the rules failing the most. Its
+// methods may be called concurrently, with WithParallelism or when the
+// validator is shared, and should return quickly.
+type Observer interface {
+	// OnValidate is called once a value of type typ, a struct for
+	// Validate and the type of the value for Valid, has been validated
+	// in d, with the number of errors found.
+	OnValidate(typ reflect.Type, d time.Duration, errCount int)
+	// OnRuleFail is called when the rule named rule of the field named
+	// field, its Go name, of the struct type typ fails. The field is
+	// empty for the rules of Valid, typ being the type of the value.
+	// The rules marked as warnings are not reported.
+	OnRuleFail(typ reflect.Type, field, rule string)
+}
+
+// WithObserver sets the observer notified of the outcome of validations.
+func WithObserver(o Observer) Option {
+	return func(mv *Validator) {
+		mv.observer = o
+	}
+}
+
+// countErrorsOf returns the number of errors held by err.
+func countErrorsOf(err error) int {
+	switch errs := err.(type) {
+	case nil:
+		return 0
+	case ErrorMap:
+		return countErrors(errs)
+	case ErrorArray:
+		return len(errs)
+	}
+	return 1
+}
@@ -0,0 +1,86 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+// recorder is an Observer recording the calls of its methods.
+type recorder struct {
+	mu        sync.Mutex
+	validated []string
+	failed    []string
+}
+
+func (r *recorder) OnValidate(typ reflect.Type, d time.Duration, errCount int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.validated = append(r.validated, typ.String()+" "+strconv.Itoa(errCount))
+}
+
+func (r *recorder) OnRuleFail(typ reflect.Type, field, rule string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = append(r.failed, typ.String()+"."+field+" "+rule)
+}
+
+type observedAddress struct {
+	City string `validate:"nonzero"`
+}
+
+type observedUser struct {
+	Name    string `validate:"nonzero,min=3"`
+	Age     int    `validate:"min=18"`
+	Nick    string `validate:"max=4@warn"`
+	Address observedAddress
+}
+
+func (ms *MySuite) TestObserver(c *C) {
+	r := &recorder{}
+	v := validator.New(validator.WithObserver(r))
+
+	c.Assert(v.Validate(observedUser{Name: "Joe", Age: 20, Address: observedAddress{City: "Paris"}}), IsNil)
+	c.Assert(r.validated, DeepEquals, []string{"validator_test.observedUser 0"})
+	c.Assert(r.failed, IsNil)
+
+	r.validated = nil
+	c.Assert(v.Validate(&observedUser{Age: 20, Nick: "Joseph"}), NotNil)
+	c.Assert(r.validated, DeepEquals, []string{"validator_test.observedUser 3"})
+	c.Assert(r.failed, DeepEquals, []string{
+		"validator_test.observedUser.Name nonzero",
+		"validator_test.observedUser.Name min",
+		"validator_test.observedAddress.City nonzero",
+	})
+
+	r.validated, r.failed = nil, nil
+	c.Assert(v.Valid("ab", "min=3,max=5"), NotNil)
+	c.Assert(r.validated, DeepEquals, []string{"string 1"})
+	c.Assert(r.failed, DeepEquals, []string{"string. min"})
+
+	r.validated, r.failed = nil, nil
+	c.Assert(validator.Validate(observedUser{}), NotNil)
+	c.Assert(r.validated, IsNil)
+	c.Assert(r.failed, IsNil)
+}
@@ -0,0 +1,102 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+)
+
+// OpenAPISchemas returns the OpenAPI 3.0 schemas of the structs values,
+// or of the structs they point to, and of the named structs they hold,
+// as the JSON object to be set as components/schemas of an OpenAPI
+// document. Their validation tags are converted as by JSONSchema.
+func OpenAPISchemas(values ...interface{}) ([]byte, error) {
+	return defaultValidator.OpenAPISchemas(values...)
+}
+
+// OpenAPISchemas returns the OpenAPI 3.0 schemas of the structs values,
+// or of the structs they point to, and of the named structs they hold,
+// as the JSON object to be set as components/schemas of an OpenAPI
+// document. Their validation tags are converted as by JSONSchema.
+func (mv *Validator) OpenAPISchemas(values ...interface{}) ([]byte, error) {
+	g := &schemaGen{mv: mv, defs: map[string]interface{}{}}
+	g.ref = func(st reflect.Type) string {
+		return "#/components/schemas/" + st.Name()
+	}
+	for _, v := range values {
+		t, err := structType(v)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := g.typeSchema(t); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range g.defs {
+		toOpenAPI(s)
+	}
+	return json.MarshalIndent(g.defs, "", "  ")
+}
+
+// toOpenAPI replaces the keywords of the JSON Schema s, and of its
+// subschemas, which OpenAPI 3.0 does not support with their equivalents.
+func toOpenAPI(s interface{}) {
+	m, ok := s.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if c, ok := m["const"]; ok {
+		delete(m, "const")
+		m["enum"] = []interface{}{c}
+	}
+	if m["contentEncoding"] == "base64" {
+		delete(m, "contentEncoding")
+		m["format"] = "byte"
+	}
+	exclusiveBound(m, "exclusiveMinimum", "minimum", 1)
+	exclusiveBound(m, "exclusiveMaximum", "maximum", -1)
+	for _, kw := range []string{"items", "additionalProperties", "not"} {
+		toOpenAPI(m[kw])
+	}
+	props, _ := m["properties"].(map[string]interface{})
+	for _, p := range props {
+		toOpenAPI(p)
+	}
+}
+
+// exclusiveBound replaces the numeric exclusive bound kw of the schema m
+// with the bound named bound and kw set to true, as OpenAPI 3.0 expects,
+// unless m already has a tighter inclusive bound. sign is 1 for lower
+// bounds and -1 for upper ones.
+func exclusiveBound(m map[string]interface{}, kw, bound string, sign int) {
+	x, ok := m[kw].(json.Number)
+	if !ok {
+		return
+	}
+	delete(m, kw)
+	if b, ok := m[bound].(json.Number); ok {
+		rb, okb := new(big.Rat).SetString(string(b))
+		rx, okx := new(big.Rat).SetString(string(x))
+		if okb && okx && rb.Cmp(rx)*sign > 0 {
+			return
+		}
+	}
+	m[bound] = x
+	m[kw] = true
+}
@@ -0,0 +1,81 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/json"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type openAPIFlags struct {
+	Active bool   `json:"active" validate:"nonzero"`
+	Count  int    `json:"count" validate:"nonzero,len=3"`
+	Data   []byte `json:"data"`
+}
+
+func (ms *MySuite) TestOpenAPISchemas(c *C) {
+	b, err := validator.OpenAPISchemas(schemaUser{}, &openAPIFlags{})
+	c.Assert(err, IsNil)
+	var schemas map[string]map[string]interface{}
+	c.Assert(json.Unmarshal(b, &schemas), IsNil)
+	c.Assert(schemas, HasLen, 3)
+
+	user := schemas["schemaUser"]
+	c.Assert(user["required"], DeepEquals, []interface{}{"name", "address"})
+	props := user["properties"].(map[string]interface{})
+	c.Assert(props["name"], DeepEquals, map[string]interface{}{"type": "string", "minLength": 1.0, "maxLength": 40.0})
+	c.Assert(props["address"], DeepEquals, map[string]interface{}{"$ref": "#/components/schemas/schemaAddress"})
+	c.Assert(props["manager"], DeepEquals, map[string]interface{}{"$ref": "#/components/schemas/schemaUser"})
+	c.Assert(schemas["schemaAddress"]["required"], DeepEquals, []interface{}{"city"})
+
+	props = schemas["openAPIFlags"]["properties"].(map[string]interface{})
+	c.Assert(props["active"], DeepEquals, map[string]interface{}{"type": "boolean", "enum": []interface{}{true}})
+	c.Assert(props["count"], DeepEquals, map[string]interface{}{
+		"type": "integer",
+		"enum": []interface{}{3.0},
+		"not":  map[string]interface{}{"enum": []interface{}{0.0}},
+	})
+	c.Assert(props["data"], DeepEquals, map[string]interface{}{"type": "string", "format": "byte"})
+
+	type bounded struct {
+		Rate  float64 `json:"rate" validate:"gt=0,lt=1"`
+		Count int     `json:"count" validate:"min=10,gt=5,max=20,lt=20"`
+	}
+	b, err = validator.OpenAPISchemas(bounded{})
+	c.Assert(err, IsNil)
+	c.Assert(json.Unmarshal(b, &schemas), IsNil)
+	props = schemas["bounded"]["properties"].(map[string]interface{})
+	c.Assert(props["rate"], DeepEquals, map[string]interface{}{
+		"type":             "number",
+		"minimum":          0.0,
+		"exclusiveMinimum": true,
+		"maximum":          1.0,
+		"exclusiveMaximum": true,
+	})
+	c.Assert(props["count"], DeepEquals, map[string]interface{}{
+		"type":             "integer",
+		"minimum":          10.0,
+		"maximum":          20.0,
+		"exclusiveMaximum": true,
+	})
+
+	_, err = validator.OpenAPISchemas("foo")
+	c.Assert(err, Equals, validator.ErrUnsupported)
+}
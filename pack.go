@@ -0,0 +1,191 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Pack is a bundle of validation functions contributed by a package,
+// such as a company rules pack, under a namespace. Once the pack is
+// registered and enabled, its rules are named after the namespace, e.g.
+// the function latlng of the namespace geo is used as validate:"geo.latlng".
+type Pack struct {
+	// Namespace is made of lower case letters, digits and underscores.
+	Namespace string
+	Funcs     map[string]ValidationFunc
+	FuncsCtx  map[string]ValidationFuncCtx
+}
+
+// rules returns the names of the rules of p, sorted.
+func (p Pack) rules() []string {
+	var names []string
+	for name := range p.Funcs {
+		names = append(names, p.Namespace+"."+name)
+	}
+	for name := range p.FuncsCtx {
+		names = append(names, p.Namespace+"."+name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// packs holds the registered packs indexed by their namespace.
+var (
+	packsLock sync.RWMutex
+	packs     = map[string]Pack{}
+)
+
+// RegisterPack registers the pack p, typically in the init function of
+// the package providing it, so that validators can enable it with
+// EnablePack. It fails if the namespace of p is invalid or already
+// registered, or if some of its functions are nil, badly named or
+// declared twice.
+func RegisterPack(p Pack) error {
+	if !isNamespace(p.Namespace) {
+		return fmt.Errorf("invalid namespace %q", p.Namespace)
+	}
+	for name, fn := range p.Funcs {
+		if err := checkPackFunc(p, name, fn == nil); err != nil {
+			return err
+		}
+		if _, ok := p.FuncsCtx[name]; ok {
+			return fmt.Errorf("rule %s.%s declared twice", p.Namespace, name)
+		}
+	}
+	for name, fn := range p.FuncsCtx {
+		if err := checkPackFunc(p, name, fn == nil); err != nil {
+			return err
+		}
+	}
+	packsLock.Lock()
+	defer packsLock.Unlock()
+	if _, ok := packs[p.Namespace]; ok {
+		return fmt.Errorf("pack %s already registered", p.Namespace)
+	}
+	packs[p.Namespace] = p
+	return nil
+}
+
+// checkPackFunc checks the function named name of the pack p.
+func checkPackFunc(p Pack, name string, isNil bool) error {
+	if name == "" || strings.ContainsAny(name, ",=~|@ ") {
+		return fmt.Errorf("invalid rule name %q in pack %s", name, p.Namespace)
+	}
+	if isNil {
+		return fmt.Errorf("nil function for rule %s.%s", p.Namespace, name)
+	}
+	return nil
+}
+
+// isNamespace reports whether s is a valid namespace.
+func isNamespace(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// registeredPack returns the registered pack of namespace.
+func registeredPack(namespace string) (Pack, error) {
+	packsLock.RLock()
+	defer packsLock.RUnlock()
+	p, ok := packs[namespace]
+	if !ok {
+		return Pack{}, fmt.Errorf("unknown pack %s", namespace)
+	}
+	return p, nil
+}
+
+// EnablePack enables the rules of the registered pack of namespace. It
+// fails, leaving the validator unchanged, if some of the rules of the
+// pack are named like a function or an alias already set.
+func EnablePack(namespace string) error {
+	return defaultValidator.EnablePack(namespace)
+}
+
+// EnablePack enables the rules of the registered pack of namespace. It
+// fails, leaving the validator unchanged, if some of the rules of the
+// pack are named like a function or an alias already set.
+func (mv *Validator) EnablePack(namespace string) error {
+	p, err := registeredPack(namespace)
+	if err != nil {
+		return err
+	}
+	mv.lock.Lock()
+	defer mv.lock.Unlock()
+	if mv.packs[namespace] {
+		return nil
+	}
+	for _, name := range p.rules() {
+		_, isFunc := mv.validationFuncs[name]
+		_, isFuncCtx := mv.validationFuncsCtx[name]
+		_, isAlias := mv.aliases[name]
+		if isFunc || isFuncCtx || isAlias {
+			return fmt.Errorf("rule %s of pack %s conflicts with a rule already set", name, namespace)
+		}
+	}
+	for name, fn := range p.Funcs {
+		mv.validationFuncs[namespace+"."+name] = fn
+	}
+	for name, fn := range p.FuncsCtx {
+		mv.validationFuncsCtx[namespace+"."+name] = fn
+	}
+	if mv.packs == nil {
+		mv.packs = map[string]bool{}
+	}
+	mv.packs[namespace] = true
+	mv.tagsCache.reset()
+	mv.structCache.reset()
+	return nil
+}
+
+// DisablePack disables the rules of the pack of namespace enabled with
+// EnablePack, the tags using them failing to parse afterwards.
+func DisablePack(namespace string) error {
+	return defaultValidator.DisablePack(namespace)
+}
+
+// DisablePack disables the rules of the pack of namespace enabled with
+// EnablePack, the tags using them failing to parse afterwards.
+func (mv *Validator) DisablePack(namespace string) error {
+	p, err := registeredPack(namespace)
+	if err != nil {
+		return err
+	}
+	mv.lock.Lock()
+	defer mv.lock.Unlock()
+	if !mv.packs[namespace] {
+		return fmt.Errorf("pack %s not enabled", namespace)
+	}
+	for _, name := range p.rules() {
+		delete(mv.validationFuncs, name)
+		delete(mv.validationFuncsCtx, name)
+	}
+	delete(mv.packs, namespace)
+	mv.tagsCache.reset()
+	mv.structCache.reset()
+	return nil
+}
@@ -0,0 +1,97 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+var errNotUpper = errors.New("must be upper case")
+
+var testPack = validator.Pack{
+	Namespace: "testpack",
+	Funcs: map[string]validator.ValidationFunc{
+		"upper": func(v interface{}, param string) error {
+			if s, ok := v.(string); ok && s != strings.ToUpper(s) {
+				return errNotUpper
+			}
+			return nil
+		},
+	},
+	FuncsCtx: map[string]validator.ValidationFuncCtx{
+		"prefix": func(ctx context.Context, v interface{}, param string) error {
+			if s, ok := v.(string); ok && !strings.HasPrefix(s, param) {
+				return validator.ErrInvalid
+			}
+			return nil
+		},
+	},
+}
+
+func init() {
+	if err := validator.RegisterPack(testPack); err != nil {
+		panic(err)
+	}
+}
+
+func (ms *MySuite) TestPack(c *C) {
+	type code struct {
+		Value string `validate:"testpack.upper,testpack.prefix=AB"`
+	}
+	v := validator.New()
+	c.Assert(v.Validate(code{"ABC"}), DeepEquals, validator.ErrorMap{"Value": {validator.ErrUnknownTag}})
+
+	c.Assert(v.EnablePack("testpack"), IsNil)
+	c.Assert(v.EnablePack("testpack"), IsNil)
+	c.Assert(v.Validate(code{"ABC"}), IsNil)
+	errs, ok := v.Validate(code{"abc"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Value"], HasError, errNotUpper)
+	c.Assert(errs["Value"], HasError, validator.ErrInvalid)
+
+	// other validators are left alone
+	c.Assert(validator.Valid("abc", "testpack.upper"), Equals, validator.ErrUnknownTag)
+
+	c.Assert(v.DisablePack("testpack"), IsNil)
+	c.Assert(v.Validate(code{"ABC"}), DeepEquals, validator.ErrorMap{"Value": {validator.ErrUnknownTag}})
+	c.Assert(v.DisablePack("testpack"), ErrorMatches, "pack testpack not enabled")
+	c.Assert(v.EnablePack("missing"), ErrorMatches, "unknown pack missing")
+}
+
+func (ms *MySuite) TestPackConflicts(c *C) {
+	c.Assert(validator.RegisterPack(testPack), ErrorMatches, "pack testpack already registered")
+	c.Assert(validator.RegisterPack(validator.Pack{Namespace: "Test.Pack"}), ErrorMatches, `invalid namespace "Test.Pack"`)
+	c.Assert(validator.RegisterPack(validator.Pack{
+		Namespace: "badpack",
+		Funcs:     map[string]validator.ValidationFunc{"a,b": testPack.Funcs["upper"]},
+	}), ErrorMatches, `invalid rule name "a,b" in pack badpack`)
+	c.Assert(validator.RegisterPack(validator.Pack{
+		Namespace: "badpack",
+		Funcs:     map[string]validator.ValidationFunc{"upper": nil},
+	}), ErrorMatches, "nil function for rule badpack.upper")
+
+	v := validator.New()
+	c.Assert(v.SetValidationFunc("testpack.upper", testPack.Funcs["upper"]), IsNil)
+	c.Assert(v.EnablePack("testpack"), ErrorMatches, "rule testpack.upper of pack testpack conflicts with a rule already set")
+	c.Assert(v.Valid("x", "testpack.prefix=x"), Equals, validator.ErrUnknownTag)
+}
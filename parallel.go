@@ -0,0 +1,101 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// WithParallelism validates the elements of slices and arrays of structs,
+// including those given to ValidateSlice, with up to n goroutines. The
+// errors found are the same as when validating them one after the other.
+// It has no effect together with WithMaxErrors, as errors must then be
+// found in order.
+func WithParallelism(n int) Option {
+	return func(mv *Validator) {
+		mv.parallelism = n
+	}
+}
+
+// parallel reports whether n elements are to be validated in parallel.
+func (mv *Validator) parallel(n int) bool {
+	return mv.parallelism > 1 && mv.maxErrors == 0 && n > 1
+}
+
+// inParallel splits the indexes of n elements into chunks, one for each
+// goroutine, calls fn for each of them with the index of the chunk and
+// the range of indexes [lo, hi) it spans, and waits for them to return.
+func (mv *Validator) inParallel(n int, fn func(chunk, lo, hi int)) {
+	size := (n + mv.parallelism - 1) / mv.parallelism
+	var wg sync.WaitGroup
+	for c, lo := 0, 0; lo < n; c, lo = c+1, lo+size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(c, lo, hi int) {
+			defer wg.Done()
+			fn(c, lo, hi)
+		}(c, lo, hi)
+	}
+	wg.Wait()
+}
+
+// sequential returns a copy of mv validating nested elements one after
+// the other, for the goroutines validating elements in parallel.
+func (mv *Validator) sequential() *Validator {
+	nv := *mv
+	nv.parallelism = 0
+	return &nv
+}
+
+// validateElems validates the elements of the slice or array v in
+// parallel, as validateDeep does, each goroutine collecting its own
+// errors and warnings before they are merged.
+func (mv *Validator) validateElems(ctx context.Context, v reflect.Value, path string, m ErrorMap, structLevel bool) {
+	errs := make([]ErrorMap, mv.parallelism)
+	warnings := make([]ErrorMap, mv.parallelism)
+	mv.inParallel(v.Len(), func(c, lo, hi int) {
+		nv := mv.sequential()
+		if mv.warnings != nil {
+			nv.warnings = make(ErrorMap)
+			warnings[c] = nv.warnings
+		}
+		errs[c] = getErrorMap()
+		for i := lo; i < hi && ctx.Err() == nil; i++ {
+			nv.validateDeep(ctx, v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs[c], structLevel)
+		}
+	})
+	for c := range errs {
+		mergeMap(m, errs[c])
+		mergeMap(mv.warnings, warnings[c])
+		if errs[c] != nil {
+			putErrorMap(errs[c])
+		}
+	}
+}
+
+// mergeMap adds the errors of src to dst.
+func mergeMap(dst, src ErrorMap) {
+	for k, errs := range src {
+		dst[k] = append(dst[k], errs...)
+	}
+}
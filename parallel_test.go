@@ -0,0 +1,73 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"fmt"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type parallelItem struct {
+	Name  string `validate:"nonzero"`
+	Code  string `validate:"max=2 @warn"`
+	Items []csvRow
+}
+
+func parallelItems(n int) []parallelItem {
+	items := make([]parallelItem, n)
+	for i := range items {
+		if i%3 != 0 {
+			items[i].Name = fmt.Sprint("item", i)
+		}
+		if i%5 == 0 {
+			items[i].Code = "abc"
+		}
+		items[i].Items = []csvRow{{"Joe", 20}, {"", i % 30}}
+	}
+	return items
+}
+
+func (ms *MySuite) TestWithParallelism(c *C) {
+	v := struct{ Items []parallelItem }{parallelItems(1000)}
+
+	want := validator.Validate(v)
+	c.Assert(want, NotNil)
+	got := validator.Validate(v, validator.WithParallelism(8))
+	c.Assert(got, DeepEquals, want)
+	c.Assert(got.Error(), Equals, want.Error())
+
+	wantWarnings, _ := validator.ValidateWithWarnings(v)
+	gotWarnings, _ := validator.ValidateWithWarnings(v, validator.WithParallelism(8))
+	c.Assert(gotWarnings, DeepEquals, wantWarnings)
+	c.Assert(gotWarnings, HasLen, 200)
+
+	// the first errors are still found with WithMaxErrors
+	got = validator.Validate(v, validator.WithParallelism(8), validator.WithMaxErrors(3))
+	c.Assert(got, DeepEquals, validator.Validate(v, validator.WithMaxErrors(3)))
+}
+
+func (ms *MySuite) TestValidateSliceParallelism(c *C) {
+	items := parallelItems(100)
+	want, err := validator.ValidateSlice(items)
+	c.Assert(err, IsNil)
+	got, err := validator.ValidateSlice(items, validator.WithParallelism(3))
+	c.Assert(err, IsNil)
+	c.Assert(got, DeepEquals, want)
+}
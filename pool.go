@@ -0,0 +1,40 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import "sync"
+
+// errorMaps holds the empty ErrorMaps errors are collected in. Most
+// validations find no error, so the map of such a call is put back to
+// be reused instead of being garbage collected.
+var errorMaps = sync.Pool{
+	New: func() interface{} { return make(ErrorMap) },
+}
+
+// getErrorMap returns an empty ErrorMap from the pool.
+func getErrorMap() ErrorMap {
+	return errorMaps.Get().(ErrorMap)
+}
+
+// putErrorMap clears m and puts it back to the pool. The maps returned
+// to the caller of a validation must not be put back.
+func putErrorMap(m ErrorMap) {
+	for k := range m {
+		delete(m, k)
+	}
+	errorMaps.Put(m)
+}
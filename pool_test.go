@@ -0,0 +1,73 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestErrorsNotReused(c *C) {
+	type test struct {
+		A string `validate:"min=3"`
+		B []csvRow
+	}
+	err := validator.Validate(test{A: "ab", B: []csvRow{{"", 20}}})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+
+	// the errors returned are not changed by later validations
+	for i := 0; i < 100; i++ {
+		c.Assert(validator.Validate(test{A: "abc"}), IsNil)
+		validator.Validate(test{B: []csvRow{{"Joe", 3}}})
+	}
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["A"], DeepEquals, validator.ErrorArray{validator.ErrMinString(3, 2)})
+	c.Assert(errs["B[0].Name"], DeepEquals, validator.ErrorArray{validator.ErrZeroValueEmpty})
+
+	b, jerr := json.Marshal(errs)
+	c.Assert(jerr, IsNil)
+	c.Assert(string(b), Equals, `{"A":["Must be at least 3 characters long, only had 2 characters"],"B[0].Name":["Must not be empty"]}`)
+}
+
+func (ms *MySuite) TestErrorsMerged(c *C) {
+	v := validator.New()
+	v.RegisterStructValidation(func(interface{}) error {
+		return validator.ErrorArray{validator.ErrInvalid}
+	}, csvRow{})
+	errs, ok := v.Validate(csvRow{"", 20}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"], DeepEquals, validator.ErrorArray{validator.ErrZeroValueEmpty})
+	c.Assert(errs["csvRow"], DeepEquals, validator.ErrorArray{validator.ErrInvalid})
+}
+
+func BenchmarkValidateInvalid(b *testing.B) {
+	type user struct {
+		Username string `validate:"min=3,max=40"`
+		Name     string `validate:"nonzero"`
+		Age      int    `validate:"min=18"`
+	}
+	u := user{"jo", "", 12}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.Validate(u)
+	}
+}
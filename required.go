@@ -0,0 +1,76 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import "reflect"
+
+// WithRequired requires the given fields in a single validation, on top
+// of the rules of their tags, e.g. Validate(v, WithRequired("Email",
+// "Address.Zip")) when the fields required depend on configuration.
+// Fields are given by their path or by a pattern like for Except. A
+// required field fails as with the nonzero rule, and the nil pointers
+// and interfaces holding it as with the required rule, so that a
+// missing Address is reported when Address.Zip is required.
+func WithRequired(fields ...string) Option {
+	return func(mv *Validator) {
+		mv.required = append(mv.required[:len(mv.required):len(mv.required)], fields...)
+	}
+}
+
+// requiredTags returns the tags of the field sf, found at any of the
+// given paths, preceded by the rule it is required by in the call, if
+// any and not among them.
+func (mv *Validator) requiredTags(sf structField, paths ...string) []tag {
+	var rule tag
+	for _, p := range paths {
+		sp := stripIndexes(p)
+		for _, f := range mv.required {
+			switch {
+			case pathMatch(f, p), pathMatch(f, sp):
+				rule = tag{Name: "nonzero", Fn: nonzero}
+			case rule.Fn == nil && canBeNil(sf.field.Type) && (ancestorMatch(f, p) || ancestorMatch(f, sp)):
+				rule = tag{Name: "required", Fn: required}
+			}
+		}
+	}
+	if rule.Fn == nil {
+		return sf.tags
+	}
+	for _, t := range sf.tags {
+		if t.Name == rule.Name {
+			return sf.tags
+		}
+	}
+	return append([]tag{rule}, sf.tags...)
+}
+
+// canBeNil reports whether the values of type t holding the fields of a
+// struct can be nil.
+func canBeNil(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface
+}
+
+// ancestorMatch reports whether path matches a field holding the fields
+// matching pattern, e.g. Address for Address.Zip.
+func ancestorMatch(pattern, path string) bool {
+	for i := 0; i < len(pattern); i++ {
+		if (pattern[i] == '.' || pattern[i] == '[') && pathMatch(pattern[:i], path) {
+			return true
+		}
+	}
+	return false
+}
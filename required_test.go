@@ -0,0 +1,72 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestWithRequired(c *C) {
+	type address struct {
+		Street string
+		Zip    string `validate:"len=4"`
+	}
+	type user struct {
+		Email string
+	}
+	type test struct {
+		Name    string `validate:"nonzero"`
+		Email   string `validate:"max=20"`
+		Address *address
+		Users   []user
+	}
+	t := test{Name: "a", Users: []user{{Email: "a@b.c"}, {}}}
+
+	c.Assert(validator.Validate(t), IsNil)
+
+	err := validator.Validate(t, validator.WithRequired("Name", "Email", "Address.Zip", "Users.Email"))
+	c.Assert(err, DeepEquals, validator.ErrorMap{
+		"Email":          {validator.ErrZeroValueEmpty},
+		"Address":        {validator.ErrRequired},
+		"Users[1].Email": {validator.ErrZeroValueEmpty},
+	})
+
+	t.Address = &address{}
+	t.Email = "a@b.c"
+	err = validator.Validate(t, validator.WithRequired("Address.Zip", "Users[*].Email"))
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Address.Zip", "Users[1].Email"})
+	// the rules of the tags still apply
+	c.Assert(errs["Address.Zip"], HasLen, 2)
+	c.Assert(errs["Address.Zip"][0].Error(), Equals, validator.ErrZeroValueEmpty.Error())
+
+	// only for the call
+	c.Assert(validator.Validate(t), DeepEquals, validator.ErrorMap{
+		"Address.Zip": {validator.ErrLenString(4, 0)},
+	})
+}
+
+func (ms *MySuite) TestWithRequiredRule(c *C) {
+	type test struct {
+		A string
+	}
+	errs := validator.ValidateErrors(test{}, validator.WithRequired("A"))
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs[0].Rule, Equals, "nonzero")
+}
@@ -0,0 +1,95 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// sanitizeTag is the name of the struct tag holding the sanitizers of
+// fields.
+const sanitizeTag = "sanitize"
+
+// SanitizeFunc returns the sanitized value of a string.
+type SanitizeFunc func(s string) string
+
+// RegisterSanitizer sets the function to be used for a given sanitizer
+// of the sanitize tag, e.g. to normalize strings to NFC:
+//
+//	validator.RegisterSanitizer("nfc", norm.NFC.String)
+//
+// Calling this function with nil fn removes the sanitizer.
+func RegisterSanitizer(name string, fn SanitizeFunc) error {
+	return defaultValidator.RegisterSanitizer(name, fn)
+}
+
+// RegisterSanitizer sets the function to be used for a given sanitizer
+// of the sanitize tag. Calling this function with nil fn removes the
+// sanitizer.
+func (mv *Validator) RegisterSanitizer(name string, fn SanitizeFunc) error {
+	if name == "" {
+		return errors.New("name cannot be empty")
+	}
+	mv.lock.Lock()
+	defer mv.lock.Unlock()
+	mv.structCache.reset()
+	if fn == nil {
+		delete(mv.sanitizers, name)
+		return nil
+	}
+	if mv.sanitizers == nil {
+		mv.sanitizers = map[string]SanitizeFunc{}
+	}
+	mv.sanitizers[name] = fn
+	return nil
+}
+
+// parseSanitizers returns the sanitizers of the comma separated list t.
+func (mv *Validator) parseSanitizers(t string) ([]SanitizeFunc, error) {
+	names := strings.Split(t, ",")
+	fns := make([]SanitizeFunc, len(names))
+	for i, name := range names {
+		fn, ok := mv.sanitizers[strings.Trim(name, " ")]
+		if !ok {
+			return nil, ErrUnknownTag
+		}
+		fns[i] = fn
+	}
+	return fns, nil
+}
+
+// sanitize replaces the value of the string f, if it can be set, with
+// the result of the sanitizers fns.
+func sanitize(f reflect.Value, fns []SanitizeFunc) {
+	if f.Kind() != reflect.String || !f.CanSet() {
+		return
+	}
+	s := f.String()
+	for _, fn := range fns {
+		s = fn(s)
+	}
+	f.SetString(s)
+}
+
+// collapseSpace trims s and replaces the runs of white space within it
+// with a single space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
@@ -0,0 +1,85 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"strings"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type sanitized struct {
+	Email    string  `sanitize:"trim,tolower" validate:"nonzero,max=16"`
+	Name     string  `sanitize:"collapse"`
+	Code     *string `sanitize:"toupper" validate:"len=3"`
+	Password string  `validate:"min=8"`
+}
+
+func (ms *MySuite) TestSanitize(c *C) {
+	code := "abc"
+	s := sanitized{
+		Email:    "  Joe@Example.COM \n",
+		Name:     " Joe \t  Bloggs ",
+		Code:     &code,
+		Password: "  secret  ",
+	}
+	c.Assert(validator.Validate(&s), IsNil)
+	c.Assert(s.Email, Equals, "joe@example.com")
+	c.Assert(s.Name, Equals, "Joe Bloggs")
+	c.Assert(code, Equals, "ABC")
+	c.Assert(s.Password, Equals, "  secret  ")
+
+	// values which cannot be set are validated as they are
+	s = sanitized{Email: "  joe@example.com  "}
+	errs, ok := validator.Validate(s).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Email"], HasError, validator.ErrMaxString(16, 19))
+	c.Assert(s.Email, Equals, "  joe@example.com  ")
+	errs, ok = validator.Validate(&s).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Email"], IsNil)
+}
+
+func (ms *MySuite) TestRegisterSanitizer(c *C) {
+	v := validator.New()
+	type test struct {
+		A string `sanitize:"trim, reverse" validate:"regexp=^c"`
+	}
+	t := test{A: "abc "}
+	errs, ok := v.Validate(&t).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrUnknownTag)
+
+	c.Assert(v.RegisterSanitizer("reverse", func(s string) string {
+		r := []rune(s)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r)
+	}), IsNil)
+	c.Assert(v.Validate(&t), IsNil)
+	c.Assert(t.A, Equals, "cba")
+
+	c.Assert(v.RegisterSanitizer("trim", strings.ToUpper), IsNil)
+	t.A = "c b"
+	c.Assert(v.Validate(&t), NotNil)
+	c.Assert(t.A, Equals, "B C")
+
+	c.Assert(v.RegisterSanitizer("", nil), NotNil)
+}
@@ -0,0 +1,178 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+)
+
+// Schema holds the rules of a struct type, or of the values of a map,
+// compiled once so that they can be applied to many values. Schemas are
+// safe for concurrent use and are not affected by the functions and
+// aliases registered after they are compiled.
+type Schema struct {
+	mv *Validator
+	// typ is the struct type of the schema, nil for map rules.
+	typ    reflect.Type
+	rules  MapRules
+	fields []FieldRules
+}
+
+// Compile compiles the rules of v, a struct or a pointer to a struct
+// whose type the schema validates, or MapRules for the values of maps.
+// The rules are checked first and the ErrorMap returned by Check is
+// returned if they are not valid.
+//
+//	schema, err := validator.Compile(User{})
+//	...
+//	err = schema.Validate(u)
+func Compile(v interface{}) (*Schema, error) {
+	return defaultValidator.Compile(v)
+}
+
+// Compile compiles the rules of v, a struct or a pointer to a struct
+// whose type the schema validates, or MapRules for the values of maps.
+// The rules are checked first and the ErrorMap returned by Check is
+// returned if they are not valid.
+func (mv *Validator) Compile(v interface{}) (*Schema, error) {
+	s := &Schema{mv: mv.copy()}
+	if err := s.mv.Check(v); err != nil {
+		return nil, err
+	}
+	if rules, ok := v.(MapRules); ok {
+		var err error
+		s.rules, err = s.mv.compileRules(rules, "", &s.fields)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	t, err := structType(v)
+	if err != nil {
+		return nil, err
+	}
+	s.typ = t
+	if err := s.mv.describeStruct(t, "", map[reflect.Type]bool{}, &s.fields); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MustCompile is like Compile but panics if the rules are not valid, so
+// that invalid tags are found at startup, e.g. when initializing global
+// variables.
+//
+//	var userSchema = validator.MustCompile(User{})
+func MustCompile(v interface{}) *Schema {
+	return defaultValidator.MustCompile(v)
+}
+
+// MustCompile is like Compile but panics if the rules are not valid, so
+// that invalid tags are found at startup, e.g. when initializing global
+// variables.
+func (mv *Validator) MustCompile(v interface{}) *Schema {
+	s, err := mv.Compile(v)
+	if err != nil {
+		panic("validator: Compile: " + err.Error())
+	}
+	return s
+}
+
+// compileRules parses the rules of the values of maps and adds them to
+// fields, so that they are parsed only once, and returns a copy of them.
+func (mv *Validator) compileRules(rules MapRules, path string, fields *[]FieldRules) (MapRules, error) {
+	compiled := make(MapRules, len(rules))
+	for _, k := range sortedKeys(rules) {
+		p := joinPath(path, k)
+		switch r := rules[k].(type) {
+		case string:
+			mv.lock.RLock()
+			tags, err := mv.parseTags(r)
+			mv.lock.RUnlock()
+			if err != nil {
+				return nil, err
+			}
+			mv.tagsCache.set(r, tags)
+			fr := FieldRules{Path: p, Rules: make([]Rule, len(tags))}
+			for i, t := range tags {
+				fr.Rules[i] = Rule{Name: t.Name, Param: t.Param, Warn: t.Warn}
+			}
+			*fields = append(*fields, fr)
+			compiled[k] = r
+		case MapRules:
+			nested, err := mv.compileRules(r, p, fields)
+			if err != nil {
+				return nil, err
+			}
+			compiled[k] = nested
+		case map[string]interface{}:
+			nested, err := mv.compileRules(MapRules(r), p, fields)
+			if err != nil {
+				return nil, err
+			}
+			compiled[k] = nested
+		default:
+			return nil, ErrBadParameter
+		}
+	}
+	return compiled, nil
+}
+
+// Validate validates v against the schema, as Validate does for structs
+// and ValidateMap for maps. It returns ErrUnsupported if v is neither of
+// the struct type of the schema, or a pointer to it, nor a map for
+// schemas compiled from MapRules.
+func (s *Schema) Validate(v interface{}, opts ...Option) error {
+	if s.typ == nil {
+		data, ok := v.(map[string]interface{})
+		if !ok {
+			return ErrUnsupported
+		}
+		return s.mv.ValidateMap(data, s.rules, opts...)
+	}
+	if t, err := structType(v); err != nil || t != s.typ {
+		return ErrUnsupported
+	}
+	return s.mv.Validate(v, opts...)
+}
+
+// Type returns the struct type validated by the schema, nil for schemas
+// compiled from MapRules.
+func (s *Schema) Type() reflect.Type {
+	return s.typ
+}
+
+// Rules returns the rules of the schema, as Describe does.
+func (s *Schema) Rules() []FieldRules {
+	return append([]FieldRules(nil), s.fields...)
+}
+
+// Manifest returns the rules of the schema as a JSON object mapping the
+// path of each field to its rules, as Manifest does.
+func (s *Schema) Manifest() ([]byte, error) {
+	return manifest(s.fields)
+}
+
+// JSONSchema returns a JSON Schema document describing the struct type
+// of the schema, as JSONSchema does. It returns ErrUnsupported for
+// schemas compiled from MapRules.
+func (s *Schema) JSONSchema() ([]byte, error) {
+	if s.typ == nil {
+		return nil, ErrUnsupported
+	}
+	return s.mv.JSONSchema(reflect.Zero(s.typ).Interface())
+}
@@ -0,0 +1,96 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"reflect"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestCompile(c *C) {
+	v := validator.New()
+	schema, err := v.Compile(schemaAddress{})
+	c.Assert(err, IsNil)
+	c.Assert(schema.Type(), Equals, reflect.TypeOf(schemaAddress{}))
+	c.Assert(schema.Rules(), DeepEquals, []validator.FieldRules{
+		{Path: "city", Rules: []validator.Rule{{Name: "nonzero"}}},
+	})
+
+	c.Assert(schema.Validate(schemaAddress{City: "Paris"}), IsNil)
+	c.Assert(schema.Validate(&schemaAddress{City: "Paris"}), IsNil)
+	errs, ok := schema.Validate(schemaAddress{}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["city"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(schema.Validate(schemaUser{}), Equals, validator.ErrUnsupported)
+	c.Assert(schema.Validate(map[string]interface{}{}), Equals, validator.ErrUnsupported)
+
+	b, err := schema.Manifest()
+	c.Assert(err, IsNil)
+	c.Assert(string(b), Equals, `{"city":[{"rule":"nonzero"}]}`)
+	b, err = schema.JSONSchema()
+	c.Assert(err, IsNil)
+	doc, err := v.JSONSchema(schemaAddress{})
+	c.Assert(err, IsNil)
+	c.Assert(string(b), Equals, string(doc))
+
+	// functions registered later do not affect the schema
+	c.Assert(v.SetValidationFunc("nonzero", nil), IsNil)
+	c.Assert(schema.Validate(schemaAddress{}), NotNil)
+}
+
+func (ms *MySuite) TestCompileErrors(c *C) {
+	_, err := validator.Compile(struct {
+		A string `validate:"foo"`
+	}{})
+	c.Assert(err, DeepEquals, validator.ErrorMap{"A": {validator.ErrUnknownTag}})
+	_, err = validator.Compile(42)
+	c.Assert(err, Equals, validator.ErrUnsupported)
+	_, err = validator.Compile(validator.MapRules{"a": 1})
+	c.Assert(err, DeepEquals, validator.ErrorMap{"a": {validator.ErrBadParameter}})
+	_, err = validator.Compile(validator.MapRules{"a": validator.MapRules{"b": "foo"}})
+	c.Assert(err, DeepEquals, validator.ErrorMap{"a.b": {validator.ErrUnknownTag}})
+}
+
+func (ms *MySuite) TestCompileMapRules(c *C) {
+	rules := validator.MapRules{
+		"name": "nonzero,max=5",
+		"address": validator.MapRules{
+			"city": "nonzero",
+		},
+	}
+	schema, err := validator.Compile(rules)
+	c.Assert(err, IsNil)
+	c.Assert(schema.Type(), IsNil)
+	c.Assert(schema.Rules(), DeepEquals, []validator.FieldRules{
+		{Path: "address.city", Rules: []validator.Rule{{Name: "nonzero"}}},
+		{Path: "name", Rules: []validator.Rule{{Name: "nonzero"}, {Name: "max", Param: "5"}}},
+	})
+
+	// changing the rules does not change the schema
+	rules["name"] = "-"
+	errs, ok := schema.Validate(map[string]interface{}{"name": "Joseph"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["name"], HasError, validator.ErrMaxString(5, 6))
+	c.Assert(errs["address.city"], HasError, validator.ErrZeroValue)
+
+	c.Assert(schema.Validate(schemaAddress{}), Equals, validator.ErrUnsupported)
+	_, err = schema.JSONSchema()
+	c.Assert(err, Equals, validator.ErrUnsupported)
+}
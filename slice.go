@@ -0,0 +1,89 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"reflect"
+)
+
+// ValidateSlice validates each element of items, a slice or an array of
+// structs, and returns the error of each element, nil for the valid ones,
+// in the order of items. Validate only takes a struct and merges the
+// errors of the elements of its slice fields into a single ErrorMap,
+// whereas ValidateSlice takes the slice itself and keeps the errors of
+// each element apart, e.g. to report the invalid rows of a batch by row.
+// Nil elements, such as in a slice of pointers, are reported with
+// ErrZeroValue. Options apply to the validation of each element.
+func ValidateSlice(items interface{}, opts ...Option) ([]error, error) {
+	return defaultValidator.ValidateSlice(items, opts...)
+}
+
+// ValidateSlice validates each element of items, a slice or an array of
+// structs, and returns the error of each element, nil for the valid ones,
+// in the order of items. Validate only takes a struct and merges the
+// errors of the elements of its slice fields into a single ErrorMap,
+// whereas ValidateSlice takes the slice itself and keeps the errors of
+// each element apart, e.g. to report the invalid rows of a batch by row.
+// Nil elements, such as in a slice of pointers, are reported with
+// ErrZeroValue. Options apply to the validation of each element.
+func (mv *Validator) ValidateSlice(items interface{}, opts ...Option) ([]error, error) {
+	v := reflect.ValueOf(items)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, ErrUnsupported
+	}
+	mv = mv.with(opts...)
+	errs := make([]error, v.Len())
+	if mv.parallel(len(errs)) {
+		mv.inParallel(len(errs), func(_, lo, hi int) {
+			nv := mv.sequential()
+			for i := lo; i < hi; i++ {
+				errs[i] = nv.validateElem(v.Index(i))
+			}
+		})
+		return errs, nil
+	}
+	for i := range errs {
+		errs[i] = mv.validateElem(v.Index(i))
+	}
+	return errs, nil
+}
+
+// validateElem validates the element e of a slice or an array,
+// reporting it with ErrZeroValue if it is a nil pointer or interface.
+func (mv *Validator) validateElem(e reflect.Value) error {
+	if (e.Kind() == reflect.Ptr || e.Kind() == reflect.Interface) && e.IsNil() {
+		return ErrZeroValue
+	}
+	return mv.validate(context.Background(), e.Interface())
+}
+
+// Partition returns the indexes of the valid and of the invalid elements
+// given their errors, as returned by ValidateSlice.
+func Partition(errs []error) (valid, invalid []int) {
+	for i, err := range errs {
+		if err == nil {
+			valid = append(valid, i)
+		} else {
+			invalid = append(invalid, i)
+		}
+	}
+	return valid, invalid
+}
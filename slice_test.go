@@ -0,0 +1,73 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type csvRow struct {
+	Name string `validate:"nonzero"`
+	Age  int    `validate:"min=18"`
+}
+
+func (ms *MySuite) TestValidateSlice(c *C) {
+	rows := []csvRow{{"Joe", 20}, {"", 20}, {"Ann", 30}, {"", 3}}
+	errs, err := validator.ValidateSlice(rows)
+	c.Assert(err, IsNil)
+	c.Assert(errs, HasLen, 4)
+	c.Assert(errs[0], IsNil)
+	c.Assert(errs[2], IsNil)
+	c.Assert(errs[1].(validator.ErrorMap)["Name"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs[3].(validator.ErrorMap), HasLen, 2)
+
+	valid, invalid := validator.Partition(errs)
+	c.Assert(valid, DeepEquals, []int{0, 2})
+	c.Assert(invalid, DeepEquals, []int{1, 3})
+
+	errs, err = validator.ValidateSlice(&rows, validator.WithFailFast())
+	c.Assert(err, IsNil)
+	c.Assert(errs[3].(validator.ErrorMap), HasLen, 1)
+
+	errs, err = validator.ValidateSlice([2]*csvRow{{"Joe", 20}, nil})
+	c.Assert(err, IsNil)
+	c.Assert(errs[0], IsNil)
+	c.Assert(errs[1], Equals, validator.ErrZeroValue)
+
+	_, err = validator.ValidateSlice(csvRow{})
+	c.Assert(err, Equals, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestValidateSliceNilElements(c *C) {
+	rows := []*csvRow{{"", 20}, nil, {"Ann", 30}}
+	for _, opts := range [][]validator.Option{nil, {validator.WithParallelism(2)}} {
+		errs, err := validator.ValidateSlice(rows, opts...)
+		c.Assert(err, IsNil)
+		c.Assert(errs[0].(validator.ErrorMap)["Name"], HasError, validator.ErrZeroValueEmpty)
+		c.Assert(errs[1], Equals, validator.ErrZeroValue)
+		c.Assert(errs[2], IsNil)
+
+		valid, invalid := validator.Partition(errs)
+		c.Assert(valid, DeepEquals, []int{2})
+		c.Assert(invalid, DeepEquals, []int{0, 1})
+	}
+	errs, err := validator.ValidateSlice([]interface{}{csvRow{"Joe", 20}, nil})
+	c.Assert(err, IsNil)
+	c.Assert(errs, DeepEquals, []error{nil, validator.ErrZeroValue})
+}
@@ -0,0 +1,204 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// StructValidationFunc is a function that validates a struct as a
+// whole, e.g. to check invariants spanning several fields. It receives
+// the struct being validated and returns either nil, an ErrorMap indexed
+// by field names, real or virtual, or any other error which is then
+// reported under the path of the struct itself (or its type name for the
+// top-level struct).
+type StructValidationFunc func(v interface{}) error
+
+// RegisterStructValidation registers fn to be called whenever a value
+// of the same type as each of the given types is validated.
+func RegisterStructValidation(fn StructValidationFunc, types ...interface{}) error {
+	return defaultValidator.RegisterStructValidation(fn, types...)
+}
+
+// RegisterStructValidation registers fn to be called whenever a value
+// of the same type as each of the given types is validated.
+//
+//	type Payment struct {
+//		CardToken   string
+//		BankAccount string
+//	}
+//	v.RegisterStructValidation(func(v interface{}) error {
+//		p := v.(Payment)
+//		if (p.CardToken == "") == (p.BankAccount == "") {
+//			return validator.ErrorMap{"Method": {errors.New("exactly one payment method must be set")}}
+//		}
+//		return nil
+//	}, Payment{})
+func (mv *Validator) RegisterStructValidation(fn StructValidationFunc, types ...interface{}) error {
+	if fn == nil {
+		return errors.New("fn cannot be nil")
+	}
+	for _, t := range types {
+		st := reflect.TypeOf(t)
+		for st != nil && st.Kind() == reflect.Ptr {
+			st = st.Elem()
+		}
+		if st == nil || st.Kind() != reflect.Struct {
+			return ErrUnsupported
+		}
+		mv.lock.Lock()
+		if mv.structFuncs == nil {
+			mv.structFuncs = map[reflect.Type][]StructValidationFunc{}
+		}
+		mv.structFuncs[st] = append(mv.structFuncs[st], fn)
+		mv.lock.Unlock()
+	}
+	return nil
+}
+
+// validateStructLevel calls the struct validation functions registered
+// for the type of sv and adds the errors they return to m.
+func (mv *Validator) validateStructLevel(sv reflect.Value, path string, m ErrorMap) {
+	mv.lock.RLock()
+	fns := mv.structFuncs[sv.Type()]
+	mv.lock.RUnlock()
+	if len(fns) == 0 || !sv.CanInterface() {
+		return
+	}
+	if validate, _ := mv.selected(path); !validate {
+		return
+	}
+	name := path
+	if name == "" {
+		name = sv.Type().Name()
+	}
+	for _, fn := range fns {
+		mv.mergeErrors(m, path, name, fn(sv.Interface()))
+	}
+}
+
+// Validatable is implemented by types carrying their own validation
+// rules. When a nested value, be it a field of a struct or an element of
+// a slice, array or map, implements Validatable, its Validate method is
+// called instead of validating its fields and the errors returned are
+// merged under the path of the value, like those of struct validation
+// functions. The tags of the field holding the value still apply.
+//
+// Only the Validate methods declared by a type itself are called: a
+// struct embedding a Validatable type is validated by its tags rather
+// than by the Validate method it gets from the embedded field, which
+// only validates the embedded value.
+type Validatable interface {
+	Validate() error
+}
+
+// asValidatable returns v as a Validatable if it, or a pointer to it,
+// implements the interface.
+func asValidatable(v reflect.Value) (Validatable, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, false
+		}
+	}
+	var vv Validatable
+	ok := false
+	if vv, ok = v.Interface().(Validatable); !ok && v.Kind() != reflect.Ptr && v.CanAddr() {
+		vv, ok = v.Addr().Interface().(Validatable)
+	}
+	if !ok || promotedValidate(v.Type()) {
+		return nil, false
+	}
+	return vv, true
+}
+
+// isValidatable reports whether the values of type t, or pointers to
+// them, implement Validatable with a Validate method of their own.
+func isValidatable(t reflect.Type) bool {
+	return (t.Implements(validatableType) || reflect.PtrTo(t).Implements(validatableType)) && !promotedValidate(t)
+}
+
+// promotedValidates caches whether the Validate methods of struct types
+// are promoted from embedded fields.
+var promotedValidates = struct {
+	sync.RWMutex
+	types map[reflect.Type]bool
+}{types: map[reflect.Type]bool{}}
+
+// promotedValidate reports whether the Validate method of the struct
+// type t, or of a pointer to it, is promoted from an embedded field
+// rather than declared by t. Promoted methods are wrappers generated by
+// the compiler, which are found as such by their file.
+func promotedValidate(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || !hasEmbedded(t) {
+		return false
+	}
+	promotedValidates.RLock()
+	promoted, ok := promotedValidates.types[t]
+	promotedValidates.RUnlock()
+	if ok {
+		return promoted
+	}
+	m, ok := t.MethodByName("Validate")
+	if !ok {
+		m, ok = reflect.PtrTo(t).MethodByName("Validate")
+	}
+	if ok {
+		pc := m.Func.Pointer()
+		file, _ := runtime.FuncForPC(pc).FileLine(pc)
+		promoted = file == "<autogenerated>"
+	}
+	promotedValidates.Lock()
+	promotedValidates.types[t] = promoted
+	promotedValidates.Unlock()
+	return promoted
+}
+
+// hasEmbedded reports whether the struct type t has embedded fields.
+func hasEmbedded(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Anonymous {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeErrors adds err to m. The entries of an ErrorMap are added
+// below path while any other error is added under name.
+func (mv *Validator) mergeErrors(m ErrorMap, path, name string, err error) {
+	switch e := err.(type) {
+	case nil:
+	case ErrorMap:
+		for k, errs := range e {
+			mv.addErrors(m, joinPath(path, k), errs...)
+		}
+	case ErrorArray:
+		mv.addErrors(m, name, e...)
+	default:
+		mv.addErrors(m, name, err)
+	}
+}
@@ -0,0 +1,224 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type Payment struct {
+	CardToken   string
+	BankAccount string
+	Amount      int `validate:"min=1"`
+}
+
+var errPaymentMethod = errors.New("exactly one payment method must be set")
+
+func validatePayment(v interface{}) error {
+	p := v.(Payment)
+	if (p.CardToken == "") == (p.BankAccount == "") {
+		return validator.ErrorMap{"Method": {errPaymentMethod}}
+	}
+	return nil
+}
+
+func (ms *MySuite) TestRegisterStructValidation(c *C) {
+	v := validator.NewValidator()
+	err := v.RegisterStructValidation(validatePayment, Payment{})
+	c.Assert(err, IsNil)
+
+	err = v.Validate(Payment{CardToken: "tok", Amount: 10})
+	c.Assert(err, IsNil)
+
+	err = v.Validate(&Payment{CardToken: "tok", BankAccount: "acc"})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Method"], HasError, errPaymentMethod)
+	c.Assert(errs["Amount"], HasError, validator.ErrMinInt(1, 0))
+
+	type order struct {
+		Payments []Payment
+	}
+	err = v.Validate(order{[]Payment{{CardToken: "tok", Amount: 1}, {Amount: 1}}})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Payments[1].Method"], HasError, errPaymentMethod)
+
+	// plain errors are reported under the struct itself
+	type wrapper struct {
+		P Payment
+	}
+	v = validator.NewValidator()
+	v.RegisterStructValidation(func(interface{}) error { return errPaymentMethod }, &Payment{})
+	err = v.Validate(wrapper{Payment{Amount: 1}})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["P"], HasError, errPaymentMethod)
+	err = v.Validate(Payment{Amount: 1})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Payment"], HasError, errPaymentMethod)
+
+	// the default validator is left untouched
+	err = validator.Validate(Payment{Amount: 1})
+	c.Assert(err, IsNil)
+
+	err = v.RegisterStructValidation(validatePayment, 42)
+	c.Assert(err, Equals, validator.ErrUnsupported)
+}
+
+type Money struct {
+	Currency string `validate:"len=3"`
+	Cents    int
+}
+
+var errNegative = errors.New("amount cannot be negative")
+
+func (m Money) Validate() error {
+	if m.Cents < 0 {
+		return errNegative
+	}
+	return nil
+}
+
+type Range struct {
+	From, To int
+}
+
+func (r *Range) Validate() error {
+	if r.From > r.To {
+		return validator.ErrorMap{"From": {errors.New("must not be after To")}}
+	}
+	return nil
+}
+
+func (ms *MySuite) TestValidatable(c *C) {
+	type test struct {
+		Price   Money
+		Fee     *Money `validate:"nonzero"`
+		Ranges  []Range
+		Ignored Money `validate:"-"`
+	}
+	t := test{
+		Price:   Money{"NZD", -1},
+		Ranges:  []Range{{1, 2}, {3, 1}},
+		Ignored: Money{"", -1},
+	}
+	err := validator.Validate(t)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["Price"], HasError, errNegative)
+	c.Assert(errs["Fee"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Ranges[1].From"], HasLen, 1)
+
+	// the top-level value does not have its Validate method called
+	err = validator.Validate(Money{"NZD", -1})
+	c.Assert(err, IsNil)
+}
+
+type Email string
+
+var errBadEmail = errors.New("bad email")
+
+func (e Email) Validate() error {
+	if !strings.Contains(string(e), "@") {
+		return errBadEmail
+	}
+	return nil
+}
+
+func (ms *MySuite) TestValidatableElements(c *C) {
+	type test struct {
+		One    Email
+		Many   []Email
+		Nested [][]*Email
+		Map    map[string]Email
+	}
+	bad := Email("b")
+	t := test{
+		One:    "a",
+		Many:   []Email{"a@b", "c"},
+		Nested: [][]*Email{{&bad}},
+		Map:    map[string]Email{"x": "d"},
+	}
+	err := validator.Validate(t)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Many[1]", "Map[x]", "Nested[0][0]", "One"})
+	c.Assert(errs["Many[1]"], HasError, errBadEmail)
+}
+
+type Audited struct {
+	Money
+	Note string `validate:"nonzero"`
+}
+
+func (ms *MySuite) TestValidatableEmbedded(c *C) {
+	type test struct {
+		In Audited
+	}
+	// the Validate method promoted from Money does not replace the tags
+	// of Audited, Money being validated on its own
+	err := validator.Validate(test{In: Audited{Money: Money{"NZD", -1}}})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"In.Money", "In.Note"})
+	c.Assert(errs["In.Money"], HasError, errNegative)
+	c.Assert(errs["In.Note"], HasError, validator.ErrZeroValueEmpty)
+}
+
+func (ms *MySuite) TestTraversalModifiers(c *C) {
+	v := validator.NewValidator()
+	c.Assert(v.RegisterStructValidation(validatePayment, Payment{}), IsNil)
+
+	type test struct {
+		Skipped  Payment   `validate:"-"`
+		Only     *Payment  `validate:"structonly,nonzero"`
+		Fields   []Payment `validate:"nostructlevel"`
+		Price    Money     `validate:"nostructlevel"`
+		Complete Payment
+	}
+	t := test{
+		Skipped:  Payment{},
+		Only:     &Payment{},
+		Fields:   []Payment{{}},
+		Price:    Money{"NZ", -1},
+		Complete: Payment{CardToken: "tok"},
+	}
+	errs, ok := v.Validate(t).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Complete.Amount", "Fields[0].Amount", "Price.Currency"})
+
+	t.Only = nil
+	errs, ok = v.Validate(t).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Only"], HasError, validator.ErrZeroValueEmpty)
+
+	fields, err := v.Describe(t)
+	c.Assert(err, IsNil)
+	c.Assert(fields, HasLen, 4)
+	c.Assert(fields[0], DeepEquals, validator.FieldRules{Path: "Only", Rules: []validator.Rule{{Name: "nonzero"}}})
+	c.Assert(fields[2], DeepEquals, validator.FieldRules{Path: "Price.Currency", Rules: []validator.Rule{{Name: "len", Param: "3"}}})
+}
@@ -0,0 +1,210 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"sync"
+)
+
+// structField holds what is needed to validate a field of a struct,
+// as found in its type.
+type structField struct {
+	index int
+	field reflect.StructField
+	// jsonName is the name of the field in its json tag, if any.
+	jsonName string
+	// tags holds the rules of the field and err the error found
+	// parsing them, if any.
+	tags []tag
+	err  error
+	// sanitizers holds the functions of the sanitize tag of the field,
+	// applied to its value before its rules.
+	sanitizers []SanitizeFunc
+	// defaults holds the text of the default value of the field, set
+	// when it is zero before it is sanitized and validated.
+	defaults []string
+	// ctxTags reports whether some of the tags are context aware and
+	// may thus look up the other fields of the struct.
+	ctxTags bool
+	// descend reports whether the value of the field may need to
+	// be walked to validate nested values.
+	descend bool
+	// embedded reports whether the field is an embedded struct, or
+	// pointer to a struct, not named by its json tag, whose fields
+	// may be promoted.
+	embedded bool
+	// noStructLevel reports whether the structs held by the field are
+	// validated without their struct validation functions.
+	noStructLevel bool
+}
+
+// structKey is the key of the fields of a struct type in the cache,
+// as they depend on the name of the tag holding the rules.
+type structKey struct {
+	t   reflect.Type
+	tag string
+}
+
+// structCache caches the fields to be validated of struct types, so
+// that their tags are only looked up and parsed once.
+type structCache struct {
+	fields map[structKey][]structField
+	lock   sync.RWMutex
+}
+
+func newStructCache() *structCache {
+	return &structCache{fields: map[structKey][]structField{}}
+}
+
+func (c *structCache) get(k structKey) ([]structField, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	fields, ok := c.fields[k]
+	return fields, ok
+}
+
+func (c *structCache) set(k structKey, fields []structField) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.fields[k] = fields
+}
+
+// reset empties the cache, e.g. when the validation funcs change.
+func (c *structCache) reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.fields = map[structKey][]structField{}
+}
+
+// structFields returns the fields of the struct type st to be validated.
+func (mv *Validator) structFields(st reflect.Type) []structField {
+	k := structKey{st, mv.tagName}
+	if fields, ok := mv.structCache.get(k); ok {
+		return fields
+	}
+
+	mv.lock.RLock()
+	defer mv.lock.RUnlock()
+	nfields := st.NumField()
+	fields := make([]structField, 0, nfields)
+	for i := 0; i < nfields; i++ {
+		f := st.Field(i)
+		tag := f.Tag.Get(mv.tagName)
+		// the values of unexported fields cannot be read, except for
+		// the exported fields of embedded structs
+		if tag == "-" || f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		sf := structField{
+			index:    i,
+			field:    f,
+			jsonName: jsonTagName(f),
+		}
+		sf.embedded = f.Anonymous && sf.jsonName == "" && isStructType(f.Type)
+		if f.PkgPath != "" && !sf.embedded {
+			continue
+		}
+		sf.descend = (f.PkgPath == "" || sf.embedded) && (mayNeedWalk(f.Type) || mv.mayHoldTypedLocked(f.Type))
+		if tag != "" {
+			sf.tags, sf.err = mv.parseTags(tag)
+			var mods map[string]bool
+			sf.tags, mods = splitModifiers(sf.tags)
+			if mods["nodynamic"] && isInterfaceType(f.Type) || mods["structonly"] {
+				sf.descend = false
+			}
+			sf.noStructLevel = mods["nostructlevel"]
+			if sf.err == nil && len(sf.tags) == 0 {
+				sf.tags = nil
+			}
+			for _, t := range sf.tags {
+				sf.ctxTags = sf.ctxTags || t.FnCtx != nil
+			}
+		}
+		if dt := f.Tag.Get(defaultTag); dt != "" {
+			var err error
+			if sf.defaults, err = parseDefault(f.Type, dt); err != nil && sf.err == nil {
+				sf.err = err
+			}
+		}
+		if st := f.Tag.Get(sanitizeTag); st != "" {
+			var err error
+			if sf.sanitizers, err = mv.parseSanitizers(st); err != nil && sf.err == nil {
+				sf.err = err
+			}
+		}
+		fields = append(fields, sf)
+	}
+	mv.structCache.set(k, fields)
+	return fields
+}
+
+// splitModifiers returns the rules of tags and the names of the modifiers
+// among them.
+func splitModifiers(tags []tag) ([]tag, map[string]bool) {
+	var mods map[string]bool
+	rules := tags[:0:0]
+	for _, t := range tags {
+		if !t.Modifier {
+			rules = append(rules, t)
+			continue
+		}
+		if mods == nil {
+			mods = map[string]bool{}
+		}
+		mods[t.Name] = true
+	}
+	if mods == nil {
+		return tags, nil
+	}
+	return rules, mods
+}
+
+// isInterfaceType reports whether t is an interface or a pointer to an
+// interface.
+func isInterfaceType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Interface
+}
+
+// isStructType reports whether t is a struct or a pointer to a struct.
+func isStructType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+var validatableType = reflect.TypeOf((*Validatable)(nil)).Elem()
+
+// mayNeedWalk reports whether a value of type t may hold a struct or
+// a Validatable value, directly or as the elements of slices, arrays
+// and maps.
+func mayNeedWalk(t reflect.Type) bool {
+	if isValidatable(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return mayNeedWalk(t.Elem())
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// PluralRule returns the index of the plural form of a count among
+// those of a message template, e.g. 0 for the singular and 1 for the
+// plural in English.
+type PluralRule func(n float64) int
+
+// pluralRules holds the plural rules of languages, indexed by language.
+var pluralRules = struct {
+	sync.RWMutex
+	rules map[string]PluralRule
+}{rules: map[string]PluralRule{
+	"en": englishPlural,
+	// 0 and 1 are singular in French
+	"fr": func(n float64) int {
+		if n < 2 {
+			return 0
+		}
+		return 1
+	},
+}}
+
+func englishPlural(n float64) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+// SetPluralRule sets the plural rule of the language lang, e.g. "pl",
+// choosing among the forms of the plural placeholders of the messages
+// of the locales of the language. Languages without a rule of their own
+// use the English one, the singular form being chosen for 1 and the
+// plural form for any other count. Calling this function with nil rule
+// removes the rule of the language.
+func SetPluralRule(lang string, rule PluralRule) {
+	pluralRules.Lock()
+	defer pluralRules.Unlock()
+	if rule == nil {
+		delete(pluralRules.rules, lang)
+		return
+	}
+	pluralRules.rules[lang] = rule
+}
+
+// pluralForm returns the form of forms matching the count n in locale.
+func pluralForm(locale string, n float64, forms []string) string {
+	pluralRules.RLock()
+	rule, ok := pluralRules.rules[locale]
+	for !ok {
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			rule = englishPlural
+			break
+		}
+		locale = locale[:i]
+		rule, ok = pluralRules.rules[locale]
+	}
+	pluralRules.RUnlock()
+	i := rule(n)
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return forms[i]
+}
+
+// count is a count of things formatted by the default English messages
+// with the singular or plural name of the things, e.g. 1 character or
+// 2 characters.
+type count struct {
+	n           int64
+	one, others string
+}
+
+// String implements the fmt.Stringer interface.
+func (c count) String() string {
+	return strconv.FormatInt(c.n, 10) + " " + pluralForm("en", float64(c.n), []string{c.one, c.others})
+}
+
+// plural returns the count of n things named one, or others if not one.
+func plural(n int64, one, others string) count {
+	return count{n, one, others}
+}
+
+// expandTemplate expands the placeholders of the message template msg
+// of a rule with the given param, for value, in locale: {param} is
+// replaced with the parameter, {value} with the value and {len} with its
+// length, in characters for strings. The plural placeholders, such as
+// {len|character|characters}, are replaced with the form, among those
+// following the name, matching the number the name stands for by the
+// plural rule of locale, # standing for the number itself, e.g.
+// {param|# day|# days}.
+func expandTemplate(msg, locale, param string, value interface{}) string {
+	if !strings.Contains(msg, "{") {
+		return msg
+	}
+	var buf []byte
+	for {
+		i := strings.IndexByte(msg, '{')
+		j := strings.IndexByte(msg[i+1:], '}')
+		if i < 0 || j < 0 {
+			break
+		}
+		j += i + 1
+		buf = append(buf, msg[:i]...)
+		s, ok := expandPlaceholder(msg[i+1:j], locale, param, value)
+		if !ok {
+			s = msg[i : j+1]
+		}
+		buf = append(buf, s...)
+		msg = msg[j+1:]
+	}
+	return string(append(buf, msg...))
+}
+
+// expandPlaceholder returns the expansion of the placeholder p, false if
+// it is unknown.
+func expandPlaceholder(p, locale, param string, value interface{}) (string, bool) {
+	forms := strings.Split(p, "|")
+	var s string
+	switch forms[0] {
+	case "param":
+		s = param
+	case "value":
+		s = fmt.Sprint(value)
+	case "len":
+		l, ok := valueLen(value)
+		if !ok {
+			return "", false
+		}
+		s = strconv.Itoa(l)
+	default:
+		return "", false
+	}
+	if len(forms) == 1 {
+		return s, true
+	}
+	num, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return "", false
+	}
+	return strings.Replace(pluralForm(locale, num, forms[1:]), "#", s, -1), true
+}
+
+// valueLen returns the length of v, in characters for strings.
+func valueLen(v interface{}) (int, bool) {
+	if s, ok := v.(string); ok {
+		return utf8.RuneCountInString(s), true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(rv.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len(), true
+	}
+	return 0, false
+}
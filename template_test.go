@@ -0,0 +1,100 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestDefaultMessagesPluralized(c *C) {
+	c.Assert(validator.ErrMinString(1, 0).Error(), Equals, "Must be at least 1 character long, only had 0 characters")
+	c.Assert(validator.ErrMinString(2, 1).Error(), Equals, "Must be at least 2 characters long, only had 1 character")
+	c.Assert(validator.ErrLenArray(1, 2).Error(), Equals, "Must have exactly 1 value, had 2 values")
+	c.Assert(validator.ErrMaxBytes(1, 3).Error(), Equals, "Must not have more than 1 byte, had 3 bytes")
+}
+
+func (ms *MySuite) TestTemplatePlurals(c *C) {
+	type test struct {
+		A string   `validate:"min=1"`
+		B string   `validate:"min=2"`
+		C []string `validate:"len=1"`
+		D int      `validate:"min=10"`
+	}
+	v := validator.NewValidator(validator.WithTranslator(validator.Catalog{
+		"en": {
+			"min": "needs {param|# character|# characters}, had {len|# character|# characters}",
+			"len": "needs {param|one value|# values}, had {len}: {value}",
+		},
+		"fr": {
+			"min": "doit avoir au moins {param|# caractère|# caractères}, avait {len|# caractère|# caractères}",
+		},
+	}))
+	t := test{B: "é", C: []string{"a", "b"}}
+
+	err := v.ValidateTranslated(t, "en")
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"][0].Error(), Equals, "needs 1 character, had 0 characters")
+	c.Assert(errs["B"][0].Error(), Equals, "needs 2 characters, had 1 character")
+	c.Assert(errs["C"][0].Error(), Equals, "needs one value, had 2: [a b]")
+	// no length, the placeholder is kept as is
+	c.Assert(errs["D"][0].Error(), Equals, "needs 10 characters, had {len|# character|# characters}")
+
+	// 0 is singular in French
+	err = v.ValidateTranslated(t, "fr-CA")
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"][0].Error(), Equals, "doit avoir au moins 1 caractère, avait 0 caractère")
+}
+
+func (ms *MySuite) TestSetPluralRule(c *C) {
+	// one, few and many forms
+	validator.SetPluralRule("xx", func(n float64) int {
+		switch {
+		case n == 1:
+			return 0
+		case n < 5:
+			return 1
+		}
+		return 2
+	})
+	defer validator.SetPluralRule("xx", nil)
+	type test struct {
+		A string `validate:"min=5,max=6"`
+	}
+	v := validator.NewValidator(validator.WithTranslator(validator.Catalog{
+		"xx": {"min": "{len|# one|# few|# many}"},
+	}))
+	err := v.ValidateTranslated(test{A: "abc"}, "xx")
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"][0].Error(), Equals, "3 few")
+}
+
+func (ms *MySuite) TestTemplateTagMessage(c *C) {
+	type test struct {
+		A []int `validate:"min=2 ~ {len|# item|# items} of {param}"`
+	}
+	for in, out := range map[int]string{0: "0 items of 2", 1: "1 item of 2"} {
+		err := validator.Validate(test{A: make([]int, in)})
+		errs, ok := err.(validator.ErrorMap)
+		c.Assert(ok, Equals, true)
+		c.Assert(errs["A"][0].Error(), Equals, out)
+	}
+}
@@ -0,0 +1,70 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding"
+	"reflect"
+	"time"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// WithTextMarshaler makes the validator validate the values implementing
+// encoding.TextMarshaler, such as UUID, decimal or enum types, as their
+// text, so that string rules like regexp or len apply to them. Times are
+// still validated as times, and big.Int, big.Rat, big.Float and Ratter
+// decimals as numbers.
+func WithTextMarshaler() Option {
+	return func(mv *Validator) {
+		mv.textMarshaler = true
+	}
+}
+
+// marshalText returns the text of val, or of the value it points to, if
+// it implements encoding.TextMarshaler and is neither a time nor a
+// number the rules compare exactly.
+func marshalText(val interface{}) (string, bool) {
+	if _, ok := indirect(val).(time.Time); ok {
+		return "", false
+	}
+	if _, _, ok := asRat(indirect(val)); ok {
+		return "", false
+	}
+	v := reflect.ValueOf(val)
+	for v.IsValid() {
+		if v.Type().Implements(textMarshalerType) {
+			if v.Kind() == reflect.Ptr && v.IsNil() {
+				return "", false
+			}
+			b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+			return string(b), err == nil
+		}
+		switch {
+		case v.Kind() == reflect.Ptr && !v.IsNil():
+			v = v.Elem()
+		case v.Kind() != reflect.Ptr && reflect.PtrTo(v.Type()).Implements(textMarshalerType):
+			// the method has a pointer receiver
+			p := reflect.New(v.Type())
+			p.Elem().Set(v)
+			v = p
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
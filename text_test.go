@@ -0,0 +1,75 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type uuid [16]byte
+
+func (u uuid) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])), nil
+}
+
+type level int
+
+func (l *level) MarshalText() ([]byte, error) {
+	return []byte([]string{"debug", "info", "error"}[*l]), nil
+}
+
+type textRequest struct {
+	ID      uuid       `validate:"len=36,regexp=^[0-9a-f-]+$"`
+	Level   *level     `validate:"regexp=^(info|error)$"`
+	Created time.Time  `validate:"before=now"`
+	Updated *time.Time `validate:"before=now"`
+}
+
+func (ms *MySuite) TestWithTextMarshaler(c *C) {
+	debug, info := level(0), level(1)
+	v := validator.New(validator.WithTextMarshaler())
+	now := time.Now()
+	c.Assert(v.Validate(textRequest{ID: uuid{1}, Level: &info, Updated: &now}), IsNil)
+
+	errs, ok := v.Validate(textRequest{Level: &debug}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Level"], HasError, validator.ErrRegexpDetailed("^(info|error)$"))
+	c.Assert(errs, HasLen, 1)
+
+	c.Assert(v.Valid(uuid{}, "len=36"), IsNil)
+	c.Assert(validator.Valid(uuid{}, "len=36"), HasError, validator.ErrLenArray(36, 16))
+}
+
+func (ms *MySuite) TestWithTextMarshalerNumbers(c *C) {
+	type order struct {
+		Amount *big.Int   `validate:"min=100"`
+		Rate   *big.Float `validate:"max=1"`
+	}
+	v := validator.New(validator.WithTextMarshaler())
+	c.Assert(v.Validate(order{Amount: big.NewInt(500), Rate: big.NewFloat(0.5)}), IsNil)
+
+	errs, ok := v.Validate(order{Amount: big.NewInt(5), Rate: big.NewFloat(2)}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Amount"], HasError, validator.ErrMinNumber("100", "5"))
+	c.Assert(errs["Rate"], HasError, validator.ErrMaxNumber("1", "2"))
+}
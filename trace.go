@@ -0,0 +1,116 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+)
+
+// RuleTrace records the evaluation of a rule.
+type RuleTrace struct {
+	// Path is the path of the field the rule belongs to, as in the keys
+	// of ErrorMap.
+	Path  string
+	Rule  string
+	Param string
+	// Err is the error of the rule, nil if it passed, and Warn reports
+	// whether the rule is marked as a warning.
+	Err  error
+	Warn bool
+	// Skipped reports whether the rule was left out, as none of its
+	// groups is validated.
+	Skipped  bool
+	Duration time.Duration
+}
+
+// String returns the trace of the rule as path: rule=param: outcome.
+func (rt RuleTrace) String() string {
+	rule := rt.Rule
+	if rt.Param != "" {
+		rule += "=" + rt.Param
+	}
+	if rt.Path != "" {
+		rule = rt.Path + ": " + rule
+	}
+	switch {
+	case rt.Skipped:
+		return rule + ": skipped"
+	case rt.Err == nil:
+		return fmt.Sprintf("%s: ok (%v)", rule, rt.Duration)
+	case rt.Warn:
+		return fmt.Sprintf("%s: warning: %v (%v)", rule, rt.Err, rt.Duration)
+	}
+	return fmt.Sprintf("%s: failed: %v (%v)", rule, rt.Err, rt.Duration)
+}
+
+// Trace records the rules evaluated by a validation, in order.
+type Trace []RuleTrace
+
+// String returns the traces of the rules, one per line.
+func (t Trace) String() string {
+	var buf bytes.Buffer
+	for _, rt := range t {
+		buf.WriteString(rt.String())
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// ValidateWithTrace validates the fields of a struct like Validate, and
+// also returns the trace of every rule evaluated, with its outcome and
+// duration, and of the rules left out by the groups validated, e.g. to
+// find out why a field of nested structs did or did not fail. Elements
+// of slices and arrays are validated one after the other when tracing.
+func ValidateWithTrace(v interface{}, opts ...Option) (Trace, error) {
+	return defaultValidator.ValidateWithTrace(v, opts...)
+}
+
+// ValidateWithTrace validates the fields of a struct like Validate, and
+// also returns the trace of every rule evaluated, with its outcome and
+// duration, and of the rules left out by the groups validated, e.g. to
+// find out why a field of nested structs did or did not fail. Elements
+// of slices and arrays are validated one after the other when tracing.
+func (mv *Validator) ValidateWithTrace(v interface{}, opts ...Option) (Trace, error) {
+	nv := *mv.with(opts...)
+	nv.trace = new(Trace)
+	nv.parallelism = 0
+	err := nv.validate(context.Background(), v)
+	return *nv.trace, err
+}
+
+// traceRule records the evaluation of the rule t, or that it was
+// skipped, if tracing.
+func (mv *Validator) traceRule(t tag, err error, skipped bool, start time.Time) {
+	if mv.trace == nil {
+		return
+	}
+	rt := RuleTrace{Rule: t.Name, Param: t.Param, Err: err, Warn: t.Warn, Skipped: skipped}
+	if !skipped {
+		rt.Duration = time.Since(start)
+	}
+	*mv.trace = append(*mv.trace, rt)
+}
+
+// tracePath sets the path of the rules traced since the nth to path.
+func (mv *Validator) tracePath(n int, path string) {
+	for i := n; i < len(*mv.trace); i++ {
+		(*mv.trace)[i].Path = path
+	}
+}
@@ -0,0 +1,73 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"strings"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type tracedAddress struct {
+	City string `validate:"nonzero"`
+}
+
+type tracedUser struct {
+	ID        int    `validate:"nonzero,groups=update"`
+	Name      string `validate:"nonzero,min=3"`
+	Nick      string `validate:"max=4@warn"`
+	Addresses []tracedAddress
+}
+
+func (ms *MySuite) TestValidateWithTrace(c *C) {
+	u := tracedUser{Name: "Jo", Nick: "Joseph", Addresses: []tracedAddress{{City: "Paris"}, {}}}
+	trace, err := validator.ValidateWithTrace(u, validator.WithGroups("create"))
+	c.Assert(err, NotNil)
+	c.Assert(trace, HasLen, 6)
+
+	var lines []string
+	for _, rt := range trace {
+		c.Assert(rt.Duration >= 0, Equals, true)
+		rt.Duration = 0
+		lines = append(lines, rt.String())
+	}
+	c.Assert(lines, DeepEquals, []string{
+		"ID: nonzero: skipped",
+		"Name: nonzero: ok (0s)",
+		"Name: min=3: failed: " + validator.ErrMinString(3, 2).Error() + " (0s)",
+		"Nick: max=4: warning: " + validator.ErrMaxString(4, 6).Error() + " (0s)",
+		"Addresses[0].City: nonzero: ok (0s)",
+		"Addresses[1].City: nonzero: failed: " + validator.ErrZeroValueEmpty.Error() + " (0s)",
+	})
+	c.Assert(trace[2].Err.Error(), Equals, validator.ErrMinString(3, 2).Error())
+	c.Assert(trace[3].Warn, Equals, true)
+	c.Assert(strings.Count(trace.String(), "\n"), Equals, 6)
+
+	trace, err = validator.ValidateWithTrace(&tracedUser{ID: 1, Name: "Joe"})
+	c.Assert(err, IsNil)
+	c.Assert(trace, HasLen, 4)
+	for _, rt := range trace {
+		c.Assert(rt.Err, IsNil)
+		c.Assert(rt.Skipped, Equals, false)
+	}
+
+	trace, err = validator.ValidateWithTrace(42)
+	c.Assert(err, Equals, validator.ErrUnsupported)
+	c.Assert(trace, HasLen, 0)
+}
@@ -0,0 +1,122 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+)
+
+// Translator translates the errors returned by validation rules.
+type Translator interface {
+	// Translate returns the message for the error err returned by
+	// the rule with the given param when validating value, in the
+	// given locale. ok is false if no translation is available.
+	Translate(locale, rule, param string, value interface{}, err error) (msg string, ok bool)
+}
+
+// Catalog is a Translator holding message templates indexed by locale
+// and then by rule name. Templates may refer to the parameter of the
+// rule as {param}, to the validated value as {value} and to its length
+// as {len}, and choose among plural forms by their number, e.g.
+// {len|# caractère|# caractères}.
+//
+//	validator.Catalog{
+//		"fr": {
+//			"nonzero": "ne doit pas être vide",
+//			"min":     "doit avoir au moins {param|# caractère|# caractères}",
+//		},
+//	}
+//
+// A locale with a region such as "fr-CA" falls back to the messages
+// of its language ("fr") when it has none of its own.
+type Catalog map[string]map[string]string
+
+// Translate implements the Translator interface.
+func (c Catalog) Translate(locale, rule, param string, value interface{}, err error) (string, bool) {
+	for {
+		if msg, ok := c[locale][rule]; ok {
+			return expandTemplate(msg, locale, param, value), true
+		}
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			return "", false
+		}
+		locale = locale[:i]
+	}
+}
+
+// WithTranslator sets the Translator used to translate error
+// messages into the locale of the validator.
+func WithTranslator(t Translator) Option {
+	return func(mv *Validator) {
+		mv.translator = t
+	}
+}
+
+// WithLocale sets the locale error messages are translated into.
+func WithLocale(locale string) Option {
+	return func(mv *Validator) {
+		mv.locale = locale
+	}
+}
+
+// SetTranslator sets the Translator used to translate error messages.
+// Calling this function with nil t disables translations.
+func SetTranslator(t Translator) {
+	defaultValidator.SetTranslator(t)
+}
+
+// SetTranslator sets the Translator used to translate error messages.
+// Calling this function with nil t disables translations.
+func (mv *Validator) SetTranslator(t Translator) {
+	mv.translator = t
+}
+
+// ValidateTranslated validates v like Validate does, translating
+// the messages of the errors found into the given locale.
+func ValidateTranslated(v interface{}, locale string) error {
+	return defaultValidator.ValidateTranslated(v, locale)
+}
+
+// ValidateTranslated validates v like Validate does, translating
+// the messages of the errors found into the given locale.
+func (mv *Validator) ValidateTranslated(v interface{}, locale string) error {
+	return mv.Validate(v, WithLocale(locale))
+}
+
+// translate returns err translated into the locale of the validator,
+// or formatted by its formatter, keeping its code, or err itself when
+// there is no translation for it and no formatter. A message set in
+// the tag itself takes precedence over any translation, and a
+// translation over the formatter. field is the name of the field
+// holding v, if any.
+func (mv *Validator) translate(t tag, v interface{}, err error, field string) error {
+	if t.Msg != "" {
+		return TextErr{coded(errorCode(err), expandTemplate(t.Msg, mv.locale, t.Param, v))}
+	}
+	if mv.translator != nil && mv.locale != "" {
+		if msg, ok := mv.translator.Translate(mv.locale, t.Name, t.Param, v, err); ok {
+			return TextErr{coded(errorCode(err), msg)}
+		}
+	}
+	if mv.formatter != nil {
+		if msg, ok := mv.formatter.Format(field, t.Name, t.Param, v, err); ok {
+			return TextErr{coded(errorCode(err), msg)}
+		}
+	}
+	return err
+}
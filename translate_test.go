@@ -0,0 +1,73 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+var testCatalog = validator.Catalog{
+	"fr": {
+		"nonzero": "ne doit pas être vide",
+		"min":     "doit être au moins {param}, était {value}",
+	},
+	"fr-CA": {
+		"nonzero": "ne peut pas être vide",
+	},
+}
+
+func (ms *MySuite) TestValidateTranslated(c *C) {
+	type test struct {
+		A string `validate:"nonzero"`
+		B int    `validate:"min=10"`
+		C string `validate:"len=3"`
+	}
+	v := validator.NewValidator(validator.WithTranslator(testCatalog))
+	t := test{B: 5}
+
+	err := v.ValidateTranslated(t, "fr")
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"][0].Error(), Equals, "ne doit pas être vide")
+	c.Assert(errs["B"][0].Error(), Equals, "doit être au moins 10, était 5")
+	// no translation, falls back to the original message
+	c.Assert(errs["C"], HasError, validator.ErrLenString(3, 0))
+
+	err = v.ValidateTranslated(t, "fr-CA")
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"][0].Error(), Equals, "ne peut pas être vide")
+	c.Assert(errs["B"][0].Error(), Equals, "doit être au moins 10, était 5")
+
+	// the locale is not kept
+	err = v.Validate(t)
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrZeroValueEmpty)
+
+	err = v.ValidateTranslated(t, "de")
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrZeroValueEmpty)
+
+	fv := validator.NewValidator(validator.WithTranslator(testCatalog), validator.WithLocale("fr"))
+	err = fv.Valid("", "nonzero")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "ne doit pas être vide")
+}
@@ -0,0 +1,116 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+)
+
+// TypeValidationFunc is a function that validates the values of a type
+// wherever they appear, whatever the tags of the fields holding them. It
+// returns nil or the error of the value, reported like those of struct
+// validation functions.
+type TypeValidationFunc func(v interface{}) error
+
+// RegisterTypeValidation registers fn to be called whenever a value of
+// the same type as each of the given types is found within a struct
+// being validated, be it a field, a value pointed to or an element of a
+// slice, array or map, e.g. to validate enum types everywhere.
+func RegisterTypeValidation(fn TypeValidationFunc, types ...interface{}) error {
+	return defaultValidator.RegisterTypeValidation(fn, types...)
+}
+
+// RegisterTypeValidation registers fn to be called whenever a value of
+// the same type as each of the given types is found within a struct
+// being validated, be it a field, a value pointed to or an element of a
+// slice, array or map, e.g. to validate enum types everywhere.
+//
+//	type Status int
+//	v.RegisterTypeValidation(func(v interface{}) error {
+//		if s := v.(Status); s < Active || s > Closed {
+//			return validator.ErrInvalid
+//		}
+//		return nil
+//	}, Status(0))
+func (mv *Validator) RegisterTypeValidation(fn TypeValidationFunc, types ...interface{}) error {
+	if fn == nil {
+		return errors.New("fn cannot be nil")
+	}
+	for _, t := range types {
+		tt := reflect.TypeOf(t)
+		for tt != nil && tt.Kind() == reflect.Ptr {
+			tt = tt.Elem()
+		}
+		if tt == nil {
+			return ErrUnsupported
+		}
+		mv.lock.Lock()
+		if mv.typeFuncs == nil {
+			mv.typeFuncs = map[reflect.Type][]TypeValidationFunc{}
+		}
+		mv.typeFuncs[tt] = append(mv.typeFuncs[tt], fn)
+		atomic.StoreInt32(&mv.hasTypeFuncs, 1)
+		mv.structCache.reset()
+		mv.lock.Unlock()
+	}
+	return nil
+}
+
+// validateType calls the type validation functions registered for the
+// type of v and adds the errors they return to m under path.
+func (mv *Validator) validateType(v reflect.Value, path string, m ErrorMap) {
+	if atomic.LoadInt32(&mv.hasTypeFuncs) == 0 || !v.IsValid() || !v.CanInterface() {
+		return
+	}
+	mv.lock.RLock()
+	fns := mv.typeFuncs[v.Type()]
+	mv.lock.RUnlock()
+	for _, fn := range fns {
+		mv.mergeErrors(m, path, path, fn(v.Interface()))
+	}
+}
+
+// mayHoldTyped reports whether a value of type t may hold values with
+// type validation functions.
+func (mv *Validator) mayHoldTyped(t reflect.Type) bool {
+	if atomic.LoadInt32(&mv.hasTypeFuncs) == 0 {
+		return false
+	}
+	mv.lock.RLock()
+	defer mv.lock.RUnlock()
+	return mv.mayHoldTypedLocked(t)
+}
+
+// mayHoldTypedLocked is mayHoldTyped for callers holding mv.lock.
+func (mv *Validator) mayHoldTypedLocked(t reflect.Type) bool {
+	if len(mv.typeFuncs) == 0 {
+		return false
+	}
+	for {
+		if _, ok := mv.typeFuncs[t]; ok {
+			return true
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+}
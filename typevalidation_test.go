@@ -0,0 +1,76 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type typedStatus int
+
+const (
+	statusActive typedStatus = iota + 1
+	statusClosed
+)
+
+type typedAccount struct {
+	Status   typedStatus
+	Previous *typedStatus
+	History  []typedStatus
+	ByRegion map[string]typedStatus
+	Any      interface{}
+	Name     string `validate:"nonzero"`
+}
+
+func validStatus(v interface{}) error {
+	if s := v.(typedStatus); s < statusActive || s > statusClosed {
+		return validator.ErrInvalid
+	}
+	return nil
+}
+
+func (ms *MySuite) TestRegisterTypeValidation(c *C) {
+	v := validator.New()
+	a := typedAccount{Status: statusActive, History: []typedStatus{statusActive, statusClosed}, Name: "a"}
+	// the cache of the fields is reset on registration
+	c.Assert(v.Validate(a), IsNil)
+	c.Assert(v.RegisterTypeValidation(validStatus, typedStatus(0)), IsNil)
+	c.Assert(v.Validate(a), IsNil)
+
+	bad := typedStatus(7)
+	a = typedAccount{
+		Previous: &bad,
+		History:  []typedStatus{statusActive, 0},
+		ByRegion: map[string]typedStatus{"eu": statusClosed, "us": 9},
+		Any:      typedStatus(5),
+	}
+	errs, ok := v.Validate(&a).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 6)
+	for _, path := range []string{"Status", "Previous", "History[1]", "ByRegion[us]", "Any"} {
+		c.Assert(errs[path], HasError, validator.ErrInvalid)
+	}
+	c.Assert(errs["Name"], HasError, validator.ErrZeroValueEmpty)
+
+	// other validators are left alone
+	c.Assert(validator.Validate(typedAccount{Name: "a"}), IsNil)
+
+	c.Assert(v.RegisterTypeValidation(nil, typedStatus(0)), NotNil)
+	c.Assert(v.RegisterTypeValidation(validStatus, nil), Equals, validator.ErrUnsupported)
+}
@@ -0,0 +1,161 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+)
+
+// FieldError is an error found validating the field at Path, returned
+// by the rule Rule with the parameter Param.
+type FieldError struct {
+	Path string
+	// Rule is the name of the rule, empty when the error is not
+	// returned by a rule, e.g. by a struct validation function.
+	Rule  string
+	Param string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	return PathError{e.Path, e.Err}.Error()
+}
+
+// Unwrap returns the error of the field.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the code of the error of the field, if any.
+func (e FieldError) Code() string {
+	return errorCode(e.Err)
+}
+
+// ValidationErrors holds the errors found by a validation, in the order
+// of their paths, as sorted by ErrorMap.Paths, the errors of each path
+// being in the order of its rules.
+type ValidationErrors []FieldError
+
+// Error implements the error interface and returns the first error, as
+// ErrorMap does.
+func (errs ValidationErrors) Error() string {
+	if len(errs) > 0 {
+		return errs[0].Error()
+	}
+	return ""
+}
+
+// First returns the first error, nil if there is none.
+func (errs ValidationErrors) First() *FieldError {
+	if len(errs) > 0 {
+		return &errs[0]
+	}
+	return nil
+}
+
+// ByField returns the errors of the field at path, e.g. Users[2].Name.
+func (errs ValidationErrors) ByField(path string) ValidationErrors {
+	var out ValidationErrors
+	for _, e := range errs {
+		if e.Path == path {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// HasRule reports whether one of the errors is returned by the rule
+// with the given name.
+func (errs ValidationErrors) HasRule(rule string) bool {
+	for _, e := range errs {
+		if e.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorMap returns the errors indexed by path, as returned by Validate.
+func (errs ValidationErrors) ErrorMap() ErrorMap {
+	if len(errs) == 0 {
+		return nil
+	}
+	m := make(ErrorMap)
+	for _, e := range errs {
+		m[e.Path] = append(m[e.Path], e.Err)
+	}
+	return m
+}
+
+// ruleError wraps the error of a rule with the rule returning it.
+type ruleError struct {
+	error
+	rule, param string
+}
+
+// Code returns the code of the error of the rule, if any.
+func (e ruleError) Code() string {
+	return errorCode(e.error)
+}
+
+// ValidateErrors validates the fields of a struct like Validate but
+// returns the errors found as ValidationErrors, along with the rules
+// returning them, nil if v is valid. Errors other than those of the
+// fields, such as ErrUnsupported, are returned at the empty path.
+func ValidateErrors(v interface{}, opts ...Option) ValidationErrors {
+	return defaultValidator.ValidateErrors(v, opts...)
+}
+
+// ValidateErrors validates the fields of a struct like Validate but
+// returns the errors found as ValidationErrors, along with the rules
+// returning them, nil if v is valid. Errors other than those of the
+// fields, such as ErrUnsupported, are returned at the empty path.
+func (mv *Validator) ValidateErrors(v interface{}, opts ...Option) ValidationErrors {
+	nv := *mv.with(opts...)
+	nv.ruleErrors = true
+	err := nv.validate(context.Background(), v)
+	errs := AsValidationErrors(err)
+	if m, ok := err.(ErrorMap); ok {
+		putErrorMap(m)
+	}
+	return errs
+}
+
+// AsValidationErrors returns the errors held by err, as returned by
+// Validate and the other validation functions, as ValidationErrors,
+// nested ErrorMaps and ErrorArrays being flattened as by Flatten. The
+// rules of the errors are only known when returned by ValidateErrors.
+func AsValidationErrors(err error) ValidationErrors {
+	if errs, ok := err.(ValidationErrors); ok {
+		return errs
+	}
+	return appendFieldErrors(nil, "", "", "", err)
+}
+
+// appendFieldErrors appends the errors held by err at path to errs, as
+// returned by the given rule unless wrapped with their own.
+func appendFieldErrors(errs ValidationErrors, path, rule, param string, err error) ValidationErrors {
+	for _, e := range appendFlat(nil, path, err) {
+		if re, ok := e.Err.(ruleError); ok {
+			errs = appendFieldErrors(errs, e.Path, re.rule, re.param, re.error)
+			continue
+		}
+		errs = append(errs, FieldError{e.Path, rule, param, e.Err})
+	}
+	return errs
+}
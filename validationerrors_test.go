@@ -0,0 +1,82 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"errors"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestValidateErrors(c *C) {
+	type address struct {
+		City string `validate:"nonzero"`
+	}
+	type test struct {
+		A string `validate:"nonzero,min=2"`
+		B int    `validate:"max=3"`
+		C []address
+	}
+	t := test{B: 5, C: []address{{City: "a"}, {}}}
+	errs := validator.ValidateErrors(t)
+	c.Assert(errs, HasLen, 4)
+	c.Assert(errs.First(), DeepEquals, &validator.FieldError{Path: "A", Rule: "nonzero", Err: validator.ErrZeroValueEmpty})
+	c.Assert(errs.ByField("A"), DeepEquals, validator.ValidationErrors{
+		{Path: "A", Rule: "nonzero", Err: validator.ErrZeroValueEmpty},
+		{Path: "A", Rule: "min", Param: "2", Err: errs[1].Err},
+	})
+	c.Assert(errs[1].Err.Error(), Equals, validator.ErrMinString(2, 0).Error())
+	c.Assert(errs.ByField("C[1].City")[0].Rule, Equals, "nonzero")
+	c.Assert(errs.ByField("C[0].City"), IsNil)
+	c.Assert(errs.HasRule("max"), Equals, true)
+	c.Assert(errs.HasRule("len"), Equals, false)
+	c.Assert(errs[2].Code(), Equals, validator.CodeMax)
+	c.Assert(errs.Error(), Equals, "A: Must not be empty")
+
+	// the errors are the same as those of Validate
+	c.Assert(errs.ErrorMap(), DeepEquals, validator.Validate(t))
+
+	c.Assert(validator.ValidateErrors(test{A: "ab"}), IsNil)
+	c.Assert(validator.ValidateErrors(1), DeepEquals, validator.ValidationErrors{{Err: validator.ErrUnsupported}})
+}
+
+func (ms *MySuite) TestValidateErrorsRuleReturningMap(c *C) {
+	v := validator.NewValidator()
+	errA := errors.New("a")
+	v.SetValidationFunc("pair", func(interface{}, string) error {
+		return validator.ErrorMap{"First": {errA}}
+	})
+	type test struct {
+		A string `validate:"pair"`
+	}
+	c.Assert(v.ValidateErrors(test{}), DeepEquals, validator.ValidationErrors{
+		{Path: "A.First", Rule: "pair", Err: errA},
+	})
+}
+
+func (ms *MySuite) TestAsValidationErrors(c *C) {
+	type test struct {
+		A string `validate:"nonzero"`
+	}
+	errs := validator.AsValidationErrors(validator.Validate(test{}))
+	// the rules are unknown
+	c.Assert(errs, DeepEquals, validator.ValidationErrors{{Path: "A", Err: validator.ErrZeroValueEmpty}})
+	c.Assert(validator.AsValidationErrors(errs), DeepEquals, errs)
+	c.Assert(validator.AsValidationErrors(nil), IsNil)
+}
@@ -17,12 +17,15 @@
 package validator
 
 import (
+	"context"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
-	"unicode"
+	"time"
 )
 
 // TextErr is an error that also implements the TextMarshaller interface for
@@ -43,113 +46,214 @@ func (t TextErr) MarshalText() ([]byte, error) {
 	return []byte(t.Err.Error()), nil
 }
 
+// Code returns the code of the error, one of the Code constants for the
+// errors of the builtin rules, or an empty string if it has none.
+func (t TextErr) Code() string {
+	return errorCode(t.Err)
+}
+
+// formatError is an error whose message is only formatted when asked
+// for, so that the errors replaced by a message or a translation, or
+// discarded, cost no formatting. Messages without args are taken as
+// they are.
+type formatError struct {
+	code   string
+	format string
+	args   []interface{}
+}
+
+// Error implements the error interface.
+func (e *formatError) Error() string {
+	if e.args == nil {
+		return e.format
+	}
+	return fmt.Sprintf(e.format, e.args...)
+}
+
+// Code returns the code of the error.
+func (e *formatError) Code() string {
+	return e.code
+}
+
+// errorf returns an error formatting its message as fmt.Sprintf does
+// when its Error method is called.
+func errorf(format string, args ...interface{}) error {
+	return &formatError{format: format, args: args}
+}
+
+// codef is errorf for errors with a code.
+func codef(code, format string, args ...interface{}) error {
+	return &formatError{code, format, args}
+}
+
+// coded returns an error with the given code and message.
+func coded(code, msg string) error {
+	return &formatError{code: code, format: msg}
+}
+
+// errorCode returns the code of err, if any.
+func errorCode(err error) string {
+	if c, ok := err.(interface {
+		Code() string
+	}); ok {
+		return c.Code()
+	}
+	return ""
+}
+
 var (
 	// ErrZeroValue is the error returned when variable has zero valud
 	// and nonzero was specified
-	ErrZeroValue       = TextErr{errors.New("zero value")}
-	ErrZeroValueEmpty  = TextErr{errors.New("Must not be empty")}
-	ErrZeroValueNumber = TextErr{errors.New("Cannot be 0")}
-	ErrZeroValueBool   = TextErr{errors.New("Cannot be false")}
+	ErrZeroValue       = TextErr{coded(CodeZeroValue, "zero value")}
+	ErrZeroValueEmpty  = TextErr{coded(CodeZeroValue, "Must not be empty")}
+	ErrZeroValueNumber = TextErr{coded(CodeZeroValue, "Cannot be 0")}
+	ErrZeroValueBool   = TextErr{coded(CodeZeroValue, "Cannot be false")}
 	// ErrMin is the error returned when variable is less than mininum
 	// value specified
-	ErrMin       = TextErr{errors.New("less than min")}
+	ErrMin       = TextErr{coded(CodeMin, "less than min")}
 	ErrMinString = func(min int64, actual int) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must be at least %d characters long, only had %d characters", min, actual),
-		)}
+		return TextErr{codef(CodeMin, "Must be at least %v long, only had %v", plural(min, "character", "characters"), plural(int64(actual), "character", "characters"))}
 	}
 	ErrMinArray = func(min int64, actual int) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must have at least %d value(s), only had %d value(s)", min, actual),
-		)}
+		return TextErr{codef(CodeMin, "Must have at least %v, only had %v", plural(min, "value", "values"), plural(int64(actual), "value", "values"))}
 	}
 	ErrMinInt = func(min int64, actual int64) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must be at least %d, was %d", min, actual),
-		)}
+		return TextErr{codef(CodeMin, "Must be at least %d, was %d", min, actual)}
+	}
+	ErrMinUint = func(min uint64, actual uint64) TextErr {
+		return TextErr{codef(CodeMin, "Must be at least %d, was %d", min, actual)}
 	}
 	ErrMinFloat = func(min float64, actual float64) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must be at least %.2f, was %.2f", min, actual),
-		)}
+		return TextErr{codef(CodeMin, "Must be at least %.2f, was %.2f", min, actual)}
+	}
+
+	ErrMinDuration = func(min time.Duration, actual time.Duration) TextErr {
+		return TextErr{codef(CodeMin, "Must be at least %s, was %s", min, actual)}
 	}
 
 	// ErrMax is the error returned when variable is more than
 	// maximum specified
-	ErrMax       = TextErr{errors.New("greater than max")}
+	ErrMax       = TextErr{coded(CodeMax, "greater than max")}
 	ErrMaxString = func(max int64, actual int) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must not have more than %d characters, had %d characters", max, actual),
-		)}
+		return TextErr{codef(CodeMax, "Must not have more than %v, had %v", plural(max, "character", "characters"), plural(int64(actual), "character", "characters"))}
 	}
 	ErrMaxArray = func(max int64, actual int) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must not have more than %d value(s), had %d value(s)", max, actual),
-		)}
+		return TextErr{codef(CodeMax, "Must not have more than %v, had %v", plural(max, "value", "values"), plural(int64(actual), "value", "values"))}
 	}
 	ErrMaxInt = func(max int64, actual int64) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must not be greater than %d, was %d", max, actual),
-		)}
+		return TextErr{codef(CodeMax, "Must not be greater than %d, was %d", max, actual)}
+	}
+	ErrMaxUint = func(max uint64, actual uint64) TextErr {
+		return TextErr{codef(CodeMax, "Must not be greater than %d, was %d", max, actual)}
 	}
 	ErrMaxFloat = func(max float64, actual float64) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must not be greater than %.2f, was %.2f", max, actual),
-		)}
+		return TextErr{codef(CodeMax, "Must not be greater than %.2f, was %.2f", max, actual)}
+	}
+	ErrMaxDuration = func(max time.Duration, actual time.Duration) TextErr {
+		return TextErr{codef(CodeMax, "Must not be greater than %s, was %s", max, actual)}
 	}
 	// ErrLen is the error returned when length is not equal to
 	// param specified
-	ErrLen       = TextErr{errors.New("invalid length")}
+	ErrLen       = TextErr{coded(CodeLen, "invalid length")}
 	ErrLenString = func(len int64, actual int) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must have exactly %d characters, was %d characters", len, actual),
-		)}
+		return TextErr{codef(CodeLen, "Must have exactly %v, was %v", plural(len, "character", "characters"), plural(int64(actual), "character", "characters"))}
 	}
 	ErrLenArray = func(len int64, actual int) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must have exactly %d value(s), had %d value(s)", len, actual),
-		)}
+		return TextErr{codef(CodeLen, "Must have exactly %v, had %v", plural(len, "value", "values"), plural(int64(actual), "value", "values"))}
 	}
 	ErrLenInt = func(len int64, actual int64) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must be exactly %d, was %d", len, actual),
-		)}
+		return TextErr{codef(CodeLen, "Must be exactly %d, was %d", len, actual)}
+	}
+	ErrLenUint = func(len uint64, actual uint64) TextErr {
+		return TextErr{codef(CodeLen, "Must be exactly %d, was %d", len, actual)}
 	}
 	ErrLenFloat = func(len float64, actual float64) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf("Must be exactly %f, was %f", len, actual),
-		)}
+		return TextErr{codef(CodeLen, "Must be exactly %f, was %f", len, actual)}
 	}
 	// ErrRegexp is the error returned when the value does not
 	// match the provided regular expression parameter
-	ErrRegexp         = TextErr{errors.New("regular expression mismatch")}
+	ErrRegexp         = TextErr{coded(CodeRegexp, "regular expression mismatch")}
 	ErrRegexpDetailed = func(regex string) TextErr {
-		return TextErr{errors.New(
-			fmt.Sprintf(`Failed to match regular expression "%s"`, regex),
-		)}
+		return TextErr{codef(CodeRegexp, `Failed to match regular expression "%s"`, regex)}
 	}
 	// ErrUnsupported is the error error returned when a validation rule
 	// is used with an unsupported variable type
-	ErrUnsupported = TextErr{errors.New("unsupported type")}
+	ErrUnsupported = TextErr{coded(CodeUnsupported, "unsupported type")}
 	// ErrBadParameter is the error returned when an invalid parameter
 	// is provided to a validation rule (e.g. a string where an int was
 	// expected (max=foo,len=bar) or missing a parameter when one is required (len=))
-	ErrBadParameter = TextErr{errors.New("bad parameter")}
+	ErrBadParameter = TextErr{coded(CodeBadParameter, "bad parameter")}
 	// ErrUnknownTag is the error returned when an unknown tag is found
-	ErrUnknownTag = TextErr{errors.New("unknown tag")}
+	ErrUnknownTag = TextErr{coded(CodeUnknownTag, "unknown tag")}
 	// ErrInvalid is the error returned when variable is invalid
 	// (normally a nil pointer)
-	ErrInvalid = TextErr{errors.New("invalid value")}
+	ErrInvalid = TextErr{coded(CodeInvalid, "invalid value")}
+	// ErrBefore is the error returned when a time is not before
+	// the time specified
+	ErrBefore = func(t time.Time) TextErr {
+		return TextErr{codef(CodeBefore, "Must be before %s", t.Format(time.RFC3339))}
+	}
+	// ErrAfter is the error returned when a time is not after
+	// the time specified
+	ErrAfter = func(t time.Time) TextErr {
+		return TextErr{codef(CodeAfter, "Must be after %s", t.Format(time.RFC3339))}
+	}
+	// ErrDatetime is the error returned when a string is not a time
+	// formatted with the layout specified
+	ErrDatetime = func(layout string) TextErr {
+		return TextErr{codef(CodeDatetime, "Must be a time formatted as %s", layout)}
+	}
+	// ErrMinNumber and ErrMaxNumber are the errors returned when a
+	// big number is less than the minimum or more than the maximum
+	// specified
+	ErrMinNumber = func(min string, actual string) TextErr {
+		return TextErr{codef(CodeMin, "Must be at least %s, was %s", min, actual)}
+	}
+	ErrMaxNumber = func(max string, actual string) TextErr {
+		return TextErr{codef(CodeMax, "Must not be greater than %s, was %s", max, actual)}
+	}
+	// ErrGreaterThan is the error returned when a number is not
+	// greater than the number specified
+	ErrGreaterThan = func(min string, actual string) TextErr {
+		return TextErr{codef(CodeGreaterThan, "Must be greater than %s, was %s", min, actual)}
+	}
+	// ErrLessThan is the error returned when a number is not less
+	// than the number specified
+	ErrLessThan = func(max string, actual string) TextErr {
+		return TextErr{codef(CodeLessThan, "Must be less than %s, was %s", max, actual)}
+	}
+	// ErrEqual is the error returned when a number is not equal to the
+	// number specified
+	ErrEqual = func(n string, actual string) TextErr {
+		return TextErr{codef(CodeEqual, "Must be %s, was %s", n, actual)}
+	}
+	// ErrNotEqual is the error returned when a number is equal to the
+	// number specified
+	ErrNotEqual = func(n string) TextErr {
+		return TextErr{codef(CodeNotEqual, "Must not be %s", n)}
+	}
+	// ErrMultipleOf is the error returned when a number is not a
+	// multiple of the number specified
+	ErrMultipleOf = func(n string) TextErr {
+		return TextErr{codef(CodeMultipleOf, "Must be a multiple of %s", n)}
+	}
+	// ErrRequired is the error returned when a required value
+	// is missing, e.g. a nil pointer tagged required
+	ErrRequired = TextErr{coded(CodeRequired, "required")}
 )
 
 // ErrorMap is a map which contains all errors from validating a struct.
+// The errors of each field are in the order of its rules. As with any
+// map, ranging over an ErrorMap is in no particular order, use Paths to
+// get its keys in a stable order.
 type ErrorMap map[string]ErrorArray
 
 // ErrorMap implements the Error interface so we can check error against nil.
-// The returned error is if existent the first error which was added to the map.
+// The returned error is if existent the first error of the first path with
+// errors, as sorted by Paths.
 func (err ErrorMap) Error() string {
-	for k, errs := range err {
-		if len(errs) > 0 {
+	for _, k := range err.Paths() {
+		if errs := err[k]; len(errs) > 0 {
 			return fmt.Sprintf("%s: %s", k, errs.Error())
 		}
 	}
@@ -157,6 +261,57 @@ func (err ErrorMap) Error() string {
 	return ""
 }
 
+// Paths returns the keys of the map sorted, the indexes of slices being
+// compared as numbers, e.g. Users[2].Name before Users[10].Name.
+func (err ErrorMap) Paths() []string {
+	paths := make([]string, 0, len(err))
+	for k := range err {
+		paths = append(paths, k)
+	}
+	sort.Sort(byPath(paths))
+	return paths
+}
+
+// byPath sorts paths in natural order, comparing the runs of digits they
+// hold as numbers.
+type byPath []string
+
+func (p byPath) Len() int           { return len(p) }
+func (p byPath) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byPath) Less(i, j int) bool { return pathLess(p[i], p[j]) }
+
+// pathLess reports whether the path a sorts before b.
+func pathLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := digitsPrefix(a), digitsPrefix(b)
+		if da > 0 && db > 0 {
+			na, nb := strings.TrimLeft(a[:da], "0"), strings.TrimLeft(b[:db], "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			a, b = a[da:], b[db:]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// digitsPrefix returns the number of ASCII digits s starts with.
+func digitsPrefix(s string) int {
+	n := 0
+	for n < len(s) && isASCIIDigit(rune(s[n])) {
+		n++
+	}
+	return n
+}
+
 // ErrorArray is a slice of errors returned by the Validate function.
 type ErrorArray []error
 
@@ -180,8 +335,173 @@ type Validator struct {
 	// validationFuncs is a map of ValidationFuncs indexed
 	// by their name.
 	validationFuncs map[string]ValidationFunc
+	// validationFuncsCtx is a map of ValidationFuncCtxs
+	// indexed by their name.
+	validationFuncsCtx map[string]ValidationFuncCtx
+	// lock guards the functions registered with the validator.
+	lock *sync.RWMutex
+	// nameFunc returns the name of a field in error paths.
+	nameFunc NameFunc
+	// translator translates error messages into locale.
+	translator Translator
+	locale     string
+	// formatter formats the messages of errors, if not nil.
+	formatter Formatter
+	// structFuncs holds the struct validation functions indexed by
+	// the type of struct they validate.
+	structFuncs map[reflect.Type][]StructValidationFunc
+	// typeFuncs holds the type validation functions indexed by the
+	// type they validate.
+	typeFuncs map[reflect.Type][]TypeValidationFunc
+	// hasTypeFuncs is set, atomically, once typeFuncs is not empty,
+	// for values to be checked without locking otherwise.
+	hasTypeFuncs int32
+	// customTypeFuncs holds the functions returning the values to
+	// validate of custom types, indexed by their type.
+	customTypeFuncs map[reflect.Type]CustomTypeFunc
+	// aliases holds the rules of the aliases registered with
+	// RegisterAlias, indexed by their name.
+	aliases map[string]string
+	// sanitizers holds the functions of the sanitize tag indexed
+	// by their name.
+	sanitizers map[string]SanitizeFunc
+	// packs holds the namespaces of the packs enabled.
+	packs map[string]bool
+	// fieldFuncs reports whether functions were set with
+	// SetValidationFuncField, which need the top level struct.
+	fieldFuncs bool
+
+	// groups holds the groups of rules to validate, all of
+	// them when empty.
+	groups []string
+	// fields and except hold the paths of the fields to restrict
+	// validation to and to exclude from validation.
+	fields []string
+	except []string
+	// required holds the paths of the fields required by the call on
+	// top of their rules.
+	required []string
+	// maxErrors is the maximum number of errors to collect
+	// before stopping validation, unlimited when zero.
+	maxErrors int
+	// textMarshaler tells to validate the values implementing
+	// encoding.TextMarshaler as their text.
+	textMarshaler bool
+	// warnings collects the errors of the rules marked as warnings
+	// during a call, if not nil.
+	warnings ErrorMap
+	// promoteEmbedded reports whether the fields of embedded structs
+	// are named as if they were fields of the struct embedding them.
+	promoteEmbedded bool
+	// nilAsZero reports whether nil pointers are validated as the zero
+	// value of the type they point to.
+	nilAsZero bool
+	// parallelism is the number of goroutines validating the elements
+	// of slices and arrays, one after the other when less than two.
+	parallelism int
+	// observer is notified of the outcome of validations, if not nil.
+	observer Observer
+	// trace records the rules evaluated during a call, if not nil.
+	trace *Trace
+	// ruleErrors reports whether the errors of rules are wrapped with
+	// the rule returning them, for ValidateErrors.
+	ruleErrors bool
+	// maxBodySize is the maximum size of the bodies decoded by
+	// JSONHandler, DefaultMaxBodySize when zero and unlimited when
+	// negative.
+	maxBodySize int64
+
+	tagsCache   *tagsCache
+	structCache *structCache
+}
+
+// NameFunc returns the name to be used for a struct field in the
+// keys of an ErrorMap. Returning an empty string falls back to the
+// name of the field.
+type NameFunc func(f reflect.StructField) string
+
+// TagNameFunc returns a NameFunc that names fields after the given
+// struct tag, e.g. TagNameFunc("json") reports the field
+// FirstName string `json:"first_name"` as first_name.
+func TagNameFunc(tag string) NameFunc {
+	return func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	}
+}
+
+// Option configures a Validator.
+type Option func(*Validator)
+
+// WithTagName sets the name of the struct tag holding the validation
+// rules, e.g. WithTagName("binding"). The default is "validate".
+func WithTagName(tag string) Option {
+	return func(mv *Validator) {
+		mv.tagName = tag
+	}
+}
+
+// WithGroups restricts validation to the rules belonging to at least
+// one of the given groups, plus the rules not belonging to any group.
+// Rules are put in groups with the groups pseudo-rule of the tag,
+// e.g. `validate:"nonzero,groups=create|import"`.
+func WithGroups(groups ...string) Option {
+	return func(mv *Validator) {
+		mv.groups = groups
+	}
+}
+
+// WithFailFast stops validation at the first error found.
+func WithFailFast() Option {
+	return WithMaxErrors(1)
+}
+
+// WithMaxErrors stops validation once n errors have been found.
+// Zero means no limit, which is the default.
+func WithMaxErrors(n int) Option {
+	return func(mv *Validator) {
+		mv.maxErrors = n
+	}
+}
+
+// WithValidationFunc sets the function to be used for a given
+// validation constraint like SetValidationFunc does. Used for a
+// single call, it leaves the validator itself unchanged.
+func WithValidationFunc(name string, vf ValidationFunc) Option {
+	return func(mv *Validator) {
+		*mv = *mv.copy()
+		mv.SetValidationFunc(name, vf)
+	}
+}
+
+// WithNameFunc sets the function used to name fields in the keys
+// of an ErrorMap.
+func WithNameFunc(fn NameFunc) Option {
+	return func(mv *Validator) {
+		mv.nameFunc = fn
+	}
+}
 
-	tagsCache tagsCache
+// WithPromotedFields indexes the errors of the fields of embedded
+// structs by their promoted name, as encoding/json does, e.g. Name
+// rather than Base.Name for a Name field of an embedded Base struct.
+// Embedded structs named by their json tag are not promoted.
+func WithPromotedFields() Option {
+	return func(mv *Validator) {
+		mv.promoteEmbedded = true
+	}
+}
+
+// WithNilAsZero validates nil pointers as the zero value of the type
+// they point to, e.g. a nil *int field tagged min=18 fails as 0 would
+// rather than being left alone by the rules not applying to pointers.
+func WithNilAsZero() Option {
+	return func(mv *Validator) {
+		mv.nilAsZero = true
+	}
 }
 
 type TagsCache interface {
@@ -207,26 +527,133 @@ func (v *tagsCache) set(tagString string, tags []tag) {
 	v.cache[tagString] = tags
 }
 
+// reset empties the cache, e.g. when the validation funcs change.
+func (v *tagsCache) reset() {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.cache = map[string][]tag{}
+}
+
 // Helper validator so users can use the
 // functions directly from the package
-var defaultValidator = NewValidator()
+var defaultValidator = New()
 
-// NewValidator creates a new Validator
-func NewValidator() *Validator {
-	return &Validator{
+// New creates a new Validator configured with the given options.
+// Each Validator holds its own validation functions and settings,
+// so libraries can use their own without interfering with the
+// default validator used by the package-level functions.
+func New(opts ...Option) *Validator {
+	mv := &Validator{
 		tagName: "validate",
 		validationFuncs: map[string]ValidationFunc{
-			"nonzero": nonzero,
-			"len":     length,
-			"min":     min,
-			"max":     max,
-			"regexp":  regex,
+			"nonzero":                 nonzero,
+			"required":                required,
+			"notnil":                  required,
+			"len":                     length,
+			"min":                     min,
+			"max":                     max,
+			"regexp":                  regex,
+			"before":                  before,
+			"after":                   after,
+			"between":                 between,
+			"datetime":                datetime,
+			"gt":                      gt,
+			"lt":                      lt,
+			"gte":                     gte,
+			"lte":                     lte,
+			"eq":                      eq,
+			"ne":                      ne,
+			"credit_card":             creditCard,
+			"iban":                    iban,
+			"bic":                     bic,
+			"isbn10":                  isbn10,
+			"isbn13":                  isbn13,
+			"issn":                    issn,
+			"ean":                     ean,
+			"e164":                    e164,
+			"latitude":                latitude,
+			"longitude":               longitude,
+			"iso3166_alpha2":          iso3166Alpha2,
+			"iso3166_alpha3":          iso3166Alpha3,
+			"iso4217":                 iso4217,
+			"bcp47":                   bcp47,
+			"postcode_iso3166_alpha2": postcode,
+			"semver":                  semver,
+			"hexcolor":                hexColor,
+			"rgb":                     rgbColor,
+			"rgba":                    rgbaColor,
+			"hsl":                     hslColor,
+			"alpha":                   alpha,
+			"alphanum":                alphanum,
+			"alphaunicode":            alphaUnicode,
+			"alphanumunicode":         alphanumUnicode,
+			"ascii":                   ascii,
+			"printascii":              printASCII,
+			"lowercase":               lowercase,
+			"uppercase":               uppercase,
+			"startswith":              startsWith,
+			"endswith":                endsWith,
+			"contains":                contains,
+			"excludes":                excludes,
+			"excludesall":             excludesAll,
+			"numeric":                 numeric,
+			"boolean":                 boolean,
+			"validutf8":               validUTF8,
+			"nocontrol":               noControl,
+			"minbytes":                minBytes,
+			"maxbytes":                maxBytes,
+			"lenbytes":                lenBytes,
+			"password":                password,
+			"hostname":                hostname,
+			"fqdn":                    fqdn,
+			"dns_label":               dnsLabel,
+			"port":                    port,
+			"hostport":                hostPort,
+			"file":                    file,
+			"dir":                     dir,
+			"filepath":                filePath,
+			"mime":                    mediaType,
+			"datauri":                 dataURI,
+			"json":                    jsonRule,
+			"nohtml":                  noHTML,
+			"unique":                  unique,
+			"sorted":                  sorted,
+			"multipleof":              multipleOf,
+			"hexadecimal":             hexadecimal,
+			"base64":                  base64Std,
+			"base64url":               base64URL,
+			"base32":                  base32Std,
+			"jwt":                     jwt,
+			"md5":                     md5Digest,
+			"sha1":                    sha1Digest,
+			"sha256":                  sha256Digest,
+			"sha512":                  sha512Digest,
 		},
-		tagsCache: tagsCache{
+		validationFuncsCtx: map[string]ValidationFuncCtx{
+			"postcode_for": postcodeFor,
+		},
+		sanitizers: map[string]SanitizeFunc{
+			"trim":     strings.TrimSpace,
+			"tolower":  strings.ToLower,
+			"toupper":  strings.ToUpper,
+			"collapse": collapseSpace,
+		},
+		lock: &sync.RWMutex{},
+		tagsCache: &tagsCache{
 			cache: map[string][]tag{},
-			lock:  sync.RWMutex{},
 		},
+		structCache: newStructCache(),
+	}
+	for _, opt := range opts {
+		opt(mv)
 	}
+	return mv
+}
+
+// NewValidator creates a new Validator configured with the given options.
+// It is the same as New.
+func NewValidator(opts ...Option) *Validator {
+	return New(opts...)
 }
 
 // SetTag allows you to change the tag name used in structs
@@ -255,16 +682,71 @@ func (mv *Validator) WithTag(tag string) *Validator {
 	return v
 }
 
+// SetNameFunc sets the function used to name fields in the keys
+// of an ErrorMap. By default, fields are named after their json tag
+// and nested structs after their field name.
+func SetNameFunc(fn NameFunc) {
+	defaultValidator.SetNameFunc(fn)
+}
+
+// SetNameFunc sets the function used to name fields in the keys
+// of an ErrorMap. By default, fields are named after their json tag
+// and nested structs after their field name.
+func (mv *Validator) SetNameFunc(fn NameFunc) {
+	mv.nameFunc = fn
+}
+
 // Copy a validator
 func (mv *Validator) copy() *Validator {
+	mv.lock.RLock()
+	defer mv.lock.RUnlock()
 	newFuncs := map[string]ValidationFunc{}
 	for k, f := range mv.validationFuncs {
 		newFuncs[k] = f
 	}
-	return &Validator{
-		tagName:         mv.tagName,
-		validationFuncs: newFuncs,
+	newFuncsCtx := map[string]ValidationFuncCtx{}
+	for k, f := range mv.validationFuncsCtx {
+		newFuncsCtx[k] = f
 	}
+	newStructFuncs := map[reflect.Type][]StructValidationFunc{}
+	for k, fns := range mv.structFuncs {
+		newStructFuncs[k] = append([]StructValidationFunc(nil), fns...)
+	}
+	newTypeFuncs := map[reflect.Type][]TypeValidationFunc{}
+	for k, fns := range mv.typeFuncs {
+		newTypeFuncs[k] = append([]TypeValidationFunc(nil), fns...)
+	}
+	newCustomTypeFuncs := map[reflect.Type]CustomTypeFunc{}
+	for k, fn := range mv.customTypeFuncs {
+		newCustomTypeFuncs[k] = fn
+	}
+	newAliases := map[string]string{}
+	for k, tags := range mv.aliases {
+		newAliases[k] = tags
+	}
+	newSanitizers := map[string]SanitizeFunc{}
+	for k, fn := range mv.sanitizers {
+		newSanitizers[k] = fn
+	}
+	newPacks := map[string]bool{}
+	for k := range mv.packs {
+		newPacks[k] = true
+	}
+	nv := *mv
+	nv.validationFuncs = newFuncs
+	nv.validationFuncsCtx = newFuncsCtx
+	nv.structFuncs = newStructFuncs
+	nv.typeFuncs = newTypeFuncs
+	nv.customTypeFuncs = newCustomTypeFuncs
+	nv.aliases = newAliases
+	nv.sanitizers = newSanitizers
+	nv.packs = newPacks
+	nv.lock = &sync.RWMutex{}
+	nv.tagsCache = &tagsCache{
+		cache: map[string][]tag{},
+	}
+	nv.structCache = newStructCache()
+	return &nv
 }
 
 // SetValidationFunc sets the function to be used for a given
@@ -281,6 +763,12 @@ func (mv *Validator) SetValidationFunc(name string, vf ValidationFunc) error {
 	if name == "" {
 		return errors.New("name cannot be empty")
 	}
+	mv.lock.Lock()
+	defer mv.lock.Unlock()
+	delete(mv.validationFuncsCtx, name)
+	delete(mv.aliases, name)
+	mv.tagsCache.reset()
+	mv.structCache.reset()
 	if vf == nil {
 		delete(mv.validationFuncs, name)
 		return nil
@@ -289,45 +777,156 @@ func (mv *Validator) SetValidationFunc(name string, vf ValidationFunc) error {
 	return nil
 }
 
+// ValidationFuncParams is a function that receives the value of a
+// field and the values of the parameter of its validation tag, as split
+// by SplitParams, e.g. 1 and 10 for range=1:10.
+type ValidationFuncParams func(v interface{}, params []string) error
+
+// SetValidationFuncParams sets the function to be used for a given
+// validation constraint taking several values, which it receives split
+// by SplitParams. Calling this function with nil vf is the same as
+// removing the constraint function from the list.
+func SetValidationFuncParams(name string, vf ValidationFuncParams) error {
+	return defaultValidator.SetValidationFuncParams(name, vf)
+}
+
+// SetValidationFuncParams sets the function to be used for a given
+// validation constraint taking several values, which it receives split
+// by SplitParams. Calling this function with nil vf is the same as
+// removing the constraint function from the list.
+func (mv *Validator) SetValidationFuncParams(name string, vf ValidationFuncParams) error {
+	if vf == nil {
+		return mv.SetValidationFunc(name, nil)
+	}
+	return mv.SetValidationFunc(name, func(v interface{}, param string) error {
+		return vf(v, SplitParams(param))
+	})
+}
+
 // Validate validates the fields of a struct based
 // on 'validator' tags and returns errors found indexed
-// by the field name.
-func Validate(v interface{}) error {
-	return defaultValidator.Validate(v)
+// by the field name. Options given only apply to this
+// call, e.g. validator.Validate(u, validator.WithGroups("update")).
+func Validate(v interface{}, opts ...Option) error {
+	return defaultValidator.Validate(v, opts...)
 }
 
 // Validate validates the fields of a struct based
 // on 'validator' tags and returns errors found indexed
-// by the field name.
-func (mv *Validator) Validate(v interface{}) error {
+// by the field name. Options given only apply to this
+// call, e.g. v.Validate(u, validator.WithGroups("update")).
+func (mv *Validator) Validate(v interface{}, opts ...Option) error {
+	return mv.with(opts...).validate(context.Background(), v)
+}
+
+// with returns a validator configured with the options of a
+// single call. The validator returned shares the registered
+// functions and the tags cache of mv.
+func (mv *Validator) with(opts ...Option) *Validator {
+	if len(opts) == 0 {
+		return mv
+	}
+	nv := *mv
+	for _, opt := range opts {
+		opt(&nv)
+	}
+	return &nv
+}
+
+// validate validates the fields of the struct v using ctx.
+func (mv *Validator) validate(ctx context.Context, v interface{}) error {
 	sv := reflect.ValueOf(v)
-	st := reflect.TypeOf(v)
-	if sv.Kind() == reflect.Ptr && !sv.IsNil() {
-		return mv.Validate(sv.Elem().Interface())
+	// the struct is kept addressable for its fields to be sanitized
+	for (sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface) && !sv.IsNil() {
+		sv = sv.Elem()
 	}
-	if sv.Kind() != reflect.Struct && sv.Kind() != reflect.Interface {
+	if sv.Kind() != reflect.Struct {
 		return ErrUnsupported
 	}
 
-	nfields := sv.NumField()
-	m := make(ErrorMap)
-	for i := 0; i < nfields; i++ {
-		f := sv.Field(i)
+	var start time.Time
+	if mv.observer != nil {
+		start = time.Now()
+	}
+	m := getErrorMap()
+	mv.validateStruct(ctx, sv, "", m, true)
+	if mv.observer != nil {
+		mv.observer.OnValidate(sv.Type(), time.Since(start), countErrors(m))
+	}
+	if err := ctx.Err(); err != nil {
+		putErrorMap(m)
+		return err
+	}
+	if len(m) > 0 {
+		return m
+	}
+	putErrorMap(m)
+	return nil
+}
+
+// validateStruct validates the fields of the struct sv and adds any
+// errors found to m, indexed by their full path below path. The struct
+// validation functions of sv are only called if structLevel is true.
+func (mv *Validator) validateStruct(ctx context.Context, sv reflect.Value, path string, m ErrorMap, structLevel bool) {
+	if mv.fieldFuncs && ctx.Value(topKey{}) == nil {
+		ctx = context.WithValue(ctx, topKey{}, sv)
+	}
+	for _, sf := range mv.structFields(sv.Type()) {
+		if ctx.Err() != nil || mv.full(m) {
+			return
+		}
+		if sf.tags == nil && sf.err == nil && !sf.descend && sf.sanitizers == nil && sf.defaults == nil && mv.required == nil {
+			continue
+		}
+		name, errName := mv.fieldNames(sf)
+		nested := mv.nestedPath(sf, path, name)
+		tags := sf.tags
+		if mv.required != nil {
+			tags = mv.requiredTags(sf, joinPath(path, name), joinPath(path, errName))
+		}
+		validate, descend := true, true
+		if mv.selecting() {
+			validate, descend = mv.selected(joinPath(path, name), joinPath(path, errName))
+			if nested == path {
+				_, descend = mv.selected(path)
+			}
+			if !validate && !descend {
+				continue
+			}
+		}
+
+		f := sv.Field(sf.index)
+		if validate && sf.defaults != nil {
+			setDefault(f, sf.defaults)
+		}
 		// deal with pointers
 		for f.Kind() == reflect.Ptr && !f.IsNil() {
 			f = f.Elem()
 		}
-		tag := st.Field(i).Tag.Get(mv.tagName)
-		if tag == "-" {
-			continue
+		if validate && sf.sanitizers != nil {
+			sanitize(f, sf.sanitizers)
 		}
 
-		fname := st.Field(i).Name
-
 		var errs ErrorArray
 
-		if tag != "" {
-			err := mv.Valid(f.Interface(), tag)
+		if validate {
+			var err error
+			if sf.err != nil {
+				err = sf.err
+			} else if tags != nil {
+				fctx := ctx
+				if sf.ctxTags {
+					fctx = withParent(ctx, sv, sf.field)
+				}
+				var traced int
+				if mv.trace != nil {
+					traced = len(*mv.trace)
+				}
+				err = mv.validateTags(fctx, mv.ruleValueOf(f), tags, sv.Type(), sf.field.Name)
+				if mv.trace != nil {
+					mv.tracePath(traced, joinPath(path, errName))
+				}
+			}
 			if errors, ok := err.(ErrorArray); ok {
 				errs = errors
 			} else {
@@ -336,31 +935,174 @@ func (mv *Validator) Validate(v interface{}) error {
 				}
 			}
 		}
-		if f.Kind() == reflect.Struct || f.Kind() == reflect.Interface {
-			if !unicode.IsUpper(rune(fname[0])) {
-				continue
-			}
-			e := mv.Validate(f.Interface())
-			if e, ok := e.(ErrorMap); ok && len(e) > 0 {
-				for j, k := range e {
-					m[fname+"."+j] = k
-				}
-			}
+		if descend && sf.descend {
+			mv.validateDeep(ctx, f, nested, m, !sf.noStructLevel)
 		}
 		if len(errs) > 0 {
-			// replace error field name with json tag name if exists
-			errorFieldName := fname
-			jsonTag := st.Field(i).Tag.Get("json")
-			if jsonTag != "" && jsonTag != "-" {
-				errorFieldName = jsonTag
+			mv.addErrors(m, joinPath(path, errName), errs...)
+		}
+	}
+	if structLevel {
+		mv.validateStructLevel(sv, path, m)
+	}
+}
+
+// fieldNames returns the name of the field sf in the path of nested
+// values and the name its errors are indexed by.
+func (mv *Validator) fieldNames(sf structField) (name, errName string) {
+	name, errName = sf.field.Name, sf.field.Name
+	// replace error field name with json tag name if exists
+	if sf.jsonName != "" {
+		errName = sf.jsonName
+	}
+	if mv.nameFunc != nil {
+		if n := mv.nameFunc(sf.field); n != "" {
+			name, errName = n, n
+		}
+	}
+	return name, errName
+}
+
+// nestedPath returns the path of the values held by the field sf, named
+// name, of the struct at path: path itself for the promoted fields of
+// embedded structs.
+func (mv *Validator) nestedPath(sf structField, path, name string) string {
+	if sf.embedded && mv.promoteEmbedded {
+		return path
+	}
+	return joinPath(path, name)
+}
+
+// validateDeep looks for structs within v, following pointers and
+// interfaces and walking slices, arrays and maps, and validates them.
+// Values implementing Validatable are validated by their own Validate
+// method instead. If structLevel is false, the structs found are only
+// validated by the tags of their fields, as the nostructlevel modifier
+// asks.
+// Elements of slices and arrays are indexed by their position and
+// map values by their key, e.g. Users[2].Address.City or Tags[foo].Name,
+// and are validated in that order.
+func (mv *Validator) validateDeep(ctx context.Context, v reflect.Value, path string, m ErrorMap, structLevel bool) {
+	validate, _ := mv.selected(path)
+	for {
+		if vv, ok := asValidatable(v); ok && validate && structLevel {
+			mv.mergeErrors(m, path, path, vv.Validate())
+			return
+		}
+		if (v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface) || v.IsNil() {
+			break
+		}
+		v = v.Elem()
+	}
+	if validate {
+		mv.validateType(v, path, m)
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		mv.validateStruct(ctx, v, path, m, structLevel)
+	case reflect.Slice, reflect.Array:
+		if !mayNeedWalk(v.Type().Elem()) && !mv.mayHoldTyped(v.Type().Elem()) {
+			return
+		}
+		if mv.parallel(v.Len()) {
+			mv.validateElems(ctx, v, path, m, structLevel)
+			return
+		}
+		for i := 0; i < v.Len() && !mv.full(m); i++ {
+			mv.validateDeep(ctx, v.Index(i), fmt.Sprintf("%s[%d]", path, i), m, structLevel)
+		}
+	case reflect.Map:
+		if !mayNeedWalk(v.Type().Elem()) && !mv.mayHoldTyped(v.Type().Elem()) {
+			return
+		}
+		for _, k := range sortedMapKeys(v) {
+			if mv.full(m) {
+				return
 			}
-			m[errorFieldName] = errs
+			mv.validateDeep(ctx, v.MapIndex(k), fmt.Sprintf("%s[%v]", path, k.Interface()), m, structLevel)
 		}
 	}
-	if len(m) > 0 {
-		return m
+}
+
+// sortedMapKeys returns the keys of the map v in order, so that its
+// values are validated in the same order every time.
+func sortedMapKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Sort(mapKeys(keys))
+	return keys
+}
+
+// mapKeys sorts the keys of a map, as their text when they cannot be
+// compared.
+type mapKeys []reflect.Value
+
+func (k mapKeys) Len() int      { return len(k) }
+func (k mapKeys) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k mapKeys) Less(i, j int) bool {
+	if c, ok := compareValues(k[i], k[j]); ok {
+		return c < 0
 	}
-	return nil
+	return fmt.Sprint(k[i].Interface()) < fmt.Sprint(k[j].Interface())
+}
+
+// mayHoldStruct reports whether a value of type t may contain a struct
+// to be validated.
+func mayHoldStruct(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return mayHoldStruct(t.Elem())
+	}
+	return false
+}
+
+// jsonTagName is the TagNameFunc for the json tag.
+var jsonTagName = TagNameFunc("json")
+
+// addErrors adds errs to m under key, up to the maximum number of
+// errors of the validator.
+func (mv *Validator) addErrors(m ErrorMap, key string, errs ...error) {
+	errs = mv.addWarnings(key, errs)
+	if mv.maxErrors > 0 {
+		n := mv.maxErrors - countErrors(m)
+		if n < len(errs) {
+			if n <= 0 {
+				return
+			}
+			errs = errs[:n]
+		}
+	}
+	if len(errs) > 0 && len(m[key]) == 0 {
+		// errs is kept as is rather than copied, its capacity being
+		// limited for later errors not to overwrite the array it shares
+		m[key] = errs[:len(errs):len(errs)]
+	} else if len(errs) > 0 {
+		m[key] = append(m[key], errs...)
+	}
+}
+
+// full reports whether m holds the maximum number of errors of the
+// validator.
+func (mv *Validator) full(m ErrorMap) bool {
+	return mv.maxErrors > 0 && countErrors(m) >= mv.maxErrors
+}
+
+// countErrors returns the number of errors held by m.
+func countErrors(m ErrorMap) int {
+	n := 0
+	for _, errs := range m {
+		n += len(errs)
+	}
+	return n
+}
+
+// joinPath appends the field name to path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
 }
 
 // Valid validates a value based on the provided
@@ -372,41 +1114,172 @@ func Valid(val interface{}, tags string) error {
 // Valid validates a value based on the provided
 // tags and returns errors found or nil.
 func (mv *Validator) Valid(val interface{}, tags string) error {
+	return mv.valid(context.Background(), val, tags)
+}
+
+// valid validates a value based on the provided tags using ctx,
+// ignoring the errors of the rules marked as warnings.
+func (mv *Validator) valid(ctx context.Context, val interface{}, tags string) error {
+	if mv.observer != nil {
+		start := time.Now()
+		err := withoutWarnings(mv.validWarn(ctx, val, tags))
+		mv.observer.OnValidate(reflect.TypeOf(val), time.Since(start), countErrorsOf(err))
+		return err
+	}
+	return withoutWarnings(mv.validWarn(ctx, val, tags))
+}
+
+// validWarn validates a value based on the provided tags using ctx,
+// the errors of the rules marked as warnings being wrapped in warning.
+func (mv *Validator) validWarn(ctx context.Context, val interface{}, tags string) error {
 	if tags == "-" {
 		return nil
 	}
-	v := reflect.ValueOf(val)
-	if v.Kind() == reflect.Ptr && !v.IsNil() {
-		return mv.Valid(v.Elem().Interface(), tags)
+	return mv.validateVar(ctx, mv.ruleValue(val), tags)
+}
+
+// ruleValue returns the value the rules of val are applied to.
+func (mv *Validator) ruleValue(val interface{}) interface{} {
+	return mv.ruleValueOf(reflect.ValueOf(val))
+}
+
+// ruleValueOf returns the value the rules of v are applied to. Fields
+// are given as they are found by reflection, so that they are only
+// turned into an interface{} once.
+func (mv *Validator) ruleValueOf(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Interface {
+		// the zero Value for a nil interface
+		v = v.Elem()
 	}
-	var err error
-	switch v.Kind() {
-	case reflect.Invalid:
-		err = mv.validateVar(nil, tags)
-	default:
-		err = mv.validateVar(val, tags)
+	if cv, ok := mv.customTypeValue(v); ok {
+		return indirect(cv)
+	}
+	if mv.textMarshaler && v.IsValid() {
+		if text, ok := marshalText(v.Interface()); ok {
+			return text
+		}
+	}
+	if mv.nilAsZero {
+		v = zeroIfNil(v)
+	}
+	return indirectValue(v)
+}
+
+// zeroIfNil returns the zero value of the type v points to, following
+// pointers, if v is a nil pointer or points to one, and v otherwise.
+func zeroIfNil(v reflect.Value) reflect.Value {
+	e := v
+	for e.Kind() == reflect.Ptr && !e.IsNil() {
+		e = e.Elem()
+	}
+	if e.Kind() != reflect.Ptr {
+		return v
+	}
+	t := e.Type().Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return reflect.Zero(t)
+}
+
+// indirect returns the value val points to, following pointers
+// until a nil pointer or a value other than a pointer is found.
+// Values implementing driver.Valuer, such as sql.NullString, are
+// replaced with their value, nil for null ones.
+func indirect(val interface{}) interface{} {
+	return indirectValue(reflect.ValueOf(val))
+}
+
+// indirectValue is indirect for a value found by reflection.
+func indirectValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
 	}
-	return err
+	e := v
+	for e.Kind() == reflect.Ptr && !e.IsNil() {
+		e = e.Elem()
+	}
+	if e.Kind() == reflect.Ptr {
+		return v.Interface()
+	}
+	val := e.Interface()
+	if vr, ok := val.(driver.Valuer); ok {
+		if dv, err := vr.Value(); err == nil {
+			return dv
+		}
+	}
+	return val
 }
 
 // validateVar validates one single variable
-func (mv *Validator) validateVar(v interface{}, tagString string) error {
+func (mv *Validator) validateVar(ctx context.Context, v interface{}, tagString string) error {
 	tags, ok := mv.tagsCache.get(tagString)
 
 	if !ok {
+		mv.lock.RLock()
 		parsedtags, err := mv.parseTags(tagString)
+		if err == nil {
+			mv.tagsCache.set(tagString, parsedtags)
+		}
+		mv.lock.RUnlock()
 		if err != nil {
 			// unknown tag found, give up.
 			return err
 		}
-		mv.tagsCache.set(tagString, parsedtags)
 		tags = parsedtags
 	}
+	return mv.validateTags(ctx, v, tags, reflect.TypeOf(v), "")
+}
 
-	errs := make(ErrorArray, 0, len(tags))
+// validateTags validates one single variable against the given tags.
+// The rules failing are reported to the observer as rules of the field
+// named field of the struct type typ, or of a value of type typ if field
+// is empty.
+func (mv *Validator) validateTags(ctx context.Context, v interface{}, tags []tag, typ reflect.Type, field string) error {
+	// errs is only allocated once an error is found, as most values
+	// are valid
+	var errs ErrorArray
+	n := 0
 	for _, t := range tags {
-		if err := t.Fn(v, t.Param); err != nil {
-			errs = append(errs, err)
+		if t.Modifier {
+			continue
+		}
+		if !mv.inGroups(t.Groups) {
+			mv.traceRule(t, nil, true, time.Time{})
+			continue
+		}
+		var start time.Time
+		if mv.trace != nil {
+			start = time.Now()
+		}
+		var err error
+		if t.FnCtx != nil {
+			err = t.FnCtx(ctx, v, t.Param)
+		} else {
+			err = t.Fn(v, t.Param)
+		}
+		if err == nil {
+			mv.traceRule(t, nil, false, start)
+			continue
+		}
+		if mv.observer != nil && !t.Warn {
+			mv.observer.OnRuleFail(typ, field, t.Name)
+		}
+		err = mv.translate(t, v, err, field)
+		mv.traceRule(t, err, false, start)
+		if mv.ruleErrors {
+			err = ruleError{err, t.Name, t.Param}
+		}
+		if errs == nil {
+			errs = make(ErrorArray, 0, len(tags))
+		}
+		if t.Warn {
+			errs = append(errs, warning{err})
+			continue
+		}
+		errs = append(errs, err)
+		if n++; n == mv.maxErrors {
+			break
 		}
 	}
 	if len(errs) > 0 {
@@ -417,31 +1290,205 @@ func (mv *Validator) validateVar(v interface{}, tagString string) error {
 
 // tag represents one of the tag items
 type tag struct {
-	Name  string         // name of the tag
-	Fn    ValidationFunc // validation function to call
-	Param string         // parameter to send to the validation function
+	Name  string            // name of the tag
+	Fn    ValidationFunc    // validation function to call
+	FnCtx ValidationFuncCtx // context aware validation function to call
+	Param string            // parameter to send to the validation function
+	Msg   string            // message replacing the one of the error, if any
+	Warn  bool              // whether the errors of the tag are warnings
+	// Groups holds the groups the tag belongs to, if any.
+	Groups []string
+	// Modifier reports whether the tag changes how the value of the
+	// field is walked rather than validating it.
+	Modifier bool
+}
+
+// modifiers holds the names of the modifier tags.
+var modifiers = map[string]bool{
+	// the value held by an interface field is not walked
+	"nodynamic": true,
+	// the structs held by the field are not walked
+	"structonly": true,
+	// the structs held by the field are walked without calling their
+	// struct validation functions
+	"nostructlevel": true,
+}
+
+// tagItem holds the parts of one of the items of a struct tag.
+type tagItem struct {
+	name, param, msg string
+	warn             bool
+}
+
+// warnSuffix is the suffix of the rules whose errors are warnings.
+const warnSuffix = "@warn"
+
+// trimWarn returns s without the suffix marking the rule of the item as
+// a warning, if any.
+func (item *tagItem) trimWarn(s string) string {
+	if strings.HasSuffix(s, warnSuffix) {
+		item.warn = true
+		return strings.TrimRight(strings.TrimSuffix(s, warnSuffix), " ")
+	}
+	return s
+}
+
+// splitTag splits a struct tag into its items, separated by commas,
+// each made of a name, an optional parameter after an equal sign,
+// which may be followed by @warn, and an optional message after a
+// tilde. Commas and tildes are taken literally when escaped with a
+// backslash or, in a parameter, when the parameter is enclosed in
+// single quotes, which also keeps its spaces, e.g.
+// regexp='^[a-z]{2,8}$'. As tildes used to be part of parameters, a
+// tilde in an unquoted parameter only starts the message when it has
+// spaces around it or follows @warn, e.g. min=8 ~ too short, and is
+// otherwise taken literally, e.g. regexp=^a~b$.
+func splitTag(t string) ([]tagItem, error) {
+	var items []tagItem
+	var item tagItem
+	var buf []byte
+	// part is the part of the item being read: 0 for the name, 1 for
+	// the parameter and 2 for the message.
+	part := 0
+	quoted := false
+	flush := func() {
+		s := string(buf)
+		switch part {
+		case 0:
+			item.name = item.trimWarn(strings.Trim(s, " "))
+		case 1:
+			if !quoted {
+				s = item.trimWarn(strings.Trim(s, " "))
+			}
+			item.param = s
+		case 2:
+			item.msg = strings.Trim(s, " ")
+		}
+		buf = buf[:0]
+	}
+	for i := 0; i < len(t); i++ {
+		c := t[i]
+		switch {
+		case c == '\\' && i+1 < len(t) && (t[i+1] == ',' || t[i+1] == '~'):
+			i++
+			buf = append(buf, t[i])
+		case c == '\'' && part == 1 && !quoted && strings.Trim(string(buf), " ") == "":
+			end := strings.IndexByte(t[i+1:], '\'')
+			if end < 0 {
+				return nil, ErrBadParameter
+			}
+			buf = append(buf[:0], t[i+1:i+1+end]...)
+			quoted = true
+			i += end + 1
+			if strings.HasPrefix(t[i+1:], warnSuffix) {
+				item.warn = true
+				i += len(warnSuffix)
+			}
+			// only spaces may follow the closing quote
+			for i+1 < len(t) && t[i+1] == ' ' {
+				i++
+			}
+			if i+1 < len(t) && t[i+1] != ',' && t[i+1] != '~' {
+				return nil, ErrBadParameter
+			}
+		case c == '=' && part == 0:
+			flush()
+			part = 1
+		case c == '~' && (part == 0 || part == 1 && (quoted || isTildeSeparator(t, i, buf))):
+			flush()
+			part = 2
+		case c == ',':
+			flush()
+			items = append(items, item)
+			item, part, quoted = tagItem{}, 0, false
+		default:
+			buf = append(buf, c)
+		}
+	}
+	flush()
+	return append(items, item), nil
+}
+
+// isTildeSeparator reports whether the tilde at t[i] separates the
+// unquoted parameter being read in buf from the message, that is
+// whether it has spaces around it or the parameter ends with @warn.
+func isTildeSeparator(t string, i int, buf []byte) bool {
+	if len(buf) > 0 && buf[len(buf)-1] == ' ' && i+1 < len(t) && t[i+1] == ' ' {
+		return true
+	}
+	return strings.HasSuffix(strings.TrimRight(string(buf), " "), warnSuffix)
 }
 
 // parseTags parses all individual tags found within a struct tag.
 func (mv *Validator) parseTags(t string) ([]tag, error) {
-	tl := strings.Split(t, ",")
-	tags := make([]tag, 0, len(tl))
-	for _, i := range tl {
-		tg := tag{}
-		v := strings.SplitN(i, "=", 2)
-		tg.Name = strings.Trim(v[0], " ")
+	return mv.expandTags(t, nil)
+}
+
+// expandTags parses the tags of t, expanding the aliases found but
+// those already being expanded.
+func (mv *Validator) expandTags(t string, expanding []string) ([]tag, error) {
+	items, err := splitTag(t)
+	if err != nil {
+		return []tag{}, err
+	}
+	tags := make([]tag, 0, len(items))
+	var groups []string
+	for _, item := range items {
+		tg := tag{Name: item.name, Param: item.param, Msg: item.msg, Warn: item.warn}
 		if tg.Name == "" {
 			return []tag{}, ErrUnknownTag
 		}
-		if len(v) > 1 {
-			tg.Param = strings.Trim(v[1], " ")
+		if tg.Name == "groups" {
+			groups = append(groups, strings.Split(tg.Param, "|")...)
+			continue
+		}
+		if modifiers[tg.Name] {
+			if tg.Param != "" {
+				return []tag{}, ErrBadParameter
+			}
+			tg.Modifier = true
+			tags = append(tags, tg)
+			continue
+		}
+		if aliased, ok := mv.aliases[tg.Name]; ok && !isExpanding(tg.Name, expanding) {
+			rules, err := mv.expandAlias(tg, aliased, expanding)
+			if err != nil {
+				return []tag{}, err
+			}
+			tags = append(tags, rules...)
+			continue
 		}
 		var found bool
+		if tg.FnCtx, found = mv.validationFuncsCtx[tg.Name]; found {
+			tags = append(tags, tg)
+			continue
+		}
 		if tg.Fn, found = mv.validationFuncs[tg.Name]; !found {
 			return []tag{}, ErrUnknownTag
 		}
 		tags = append(tags, tg)
 
 	}
+	if len(groups) > 0 {
+		for i := range tags {
+			tags[i].Groups = groups
+		}
+	}
 	return tags, nil
 }
+
+// inGroups reports whether a tag belonging to the given groups is
+// to be validated.
+func (mv *Validator) inGroups(groups []string) bool {
+	if len(groups) == 0 || len(mv.groups) == 0 {
+		return true
+	}
+	for _, g := range groups {
+		for _, active := range mv.groups {
+			if g == active {
+				return true
+			}
+		}
+	}
+	return false
+}
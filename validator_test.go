@@ -17,8 +17,11 @@
 package validator_test
 
 import (
+	"database/sql"
+	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/movio/validator"
 
@@ -89,6 +92,161 @@ func (ms *MySuite) TestValidate(c *C) {
 	c.Assert(errs["E.F"], HasError, validator.ErrLenString(3, len(t.E.Foo())))
 }
 
+func (ms *MySuite) TestValidateNestedPaths(c *C) {
+	type Address struct {
+		City string `validate:"nonzero"`
+	}
+	type User struct {
+		Name    string `validate:"nonzero"`
+		Address *Address
+	}
+	type test struct {
+		Users  []User
+		ByName map[string]*User
+		Pair   [2]Address
+	}
+	t := test{
+		Users: []User{
+			{"a", &Address{"x"}},
+			{"b", nil},
+			{"", &Address{""}},
+		},
+		ByName: map[string]*User{
+			"joe": {"joe", &Address{}},
+		},
+	}
+	t.Pair[1].City = "y"
+
+	err := validator.Validate(t)
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 4)
+	c.Assert(errs["Users[2].Name"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Users[2].Address.City"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["ByName[joe].Address.City"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Pair[0].City"], HasError, validator.ErrZeroValueEmpty)
+}
+
+type embeddedAudit struct {
+	CreatedBy string `validate:"nonzero"`
+}
+
+type EmbeddedBase struct {
+	ID int `validate:"min=1"`
+}
+
+type embeddedItem struct {
+	EmbeddedBase
+	*embeddedAudit
+	Named    EmbeddedBase `json:"named"`
+	Name     string       `validate:"nonzero"`
+	internal string       `validate:"nonzero"`
+}
+
+func (ms *MySuite) TestEmbeddedStructs(c *C) {
+	v := embeddedItem{embeddedAudit: &embeddedAudit{}}
+	errs, ok := validator.Validate(v).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"EmbeddedBase.ID", "Name", "Named.ID", "embeddedAudit.CreatedBy"})
+
+	errs, ok = validator.Validate(v, validator.WithPromotedFields()).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"CreatedBy", "ID", "Name", "Named.ID"})
+	c.Assert(errs["ID"], HasError, validator.ErrMinInt(1, 0))
+
+	// promoted fields are selected by their promoted path
+	c.Assert(validator.ValidateFields(v, "ID"), IsNil)
+	errs, ok = validator.ValidateFields(v, "EmbeddedBase.ID").(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"EmbeddedBase.ID"})
+	errs, ok = validator.New(validator.WithPromotedFields()).ValidateFields(v, "ID").(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"ID"})
+	errs, ok = validator.New(validator.WithPromotedFields()).ValidateFieldsExcept(v, "ID", "Named").(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"CreatedBy", "Name"})
+
+	// nil embedded pointers are skipped
+	v = embeddedItem{EmbeddedBase{1}, nil, EmbeddedBase{1}, "a", ""}
+	c.Assert(validator.Validate(v, validator.WithPromotedFields()), IsNil)
+}
+
+type dynamicShape interface {
+	Area() float64
+}
+
+type dynamicSquare struct {
+	Side float64 `validate:"min=1"`
+}
+
+func (s dynamicSquare) Area() float64 { return s.Side * s.Side }
+
+func (ms *MySuite) TestInterfaceFields(c *C) {
+	type test struct {
+		Shape   dynamicShape   `validate:"nonzero"`
+		Shapes  []dynamicShape `validate:"max=2"`
+		Any     interface{}    `validate:"max=3"`
+		Static  dynamicShape   `validate:"nodynamic"`
+		Present interface{}    `validate:"required,nodynamic"`
+	}
+	v := test{
+		Shape:   &dynamicSquare{},
+		Shapes:  []dynamicShape{dynamicSquare{2}, dynamicSquare{}},
+		Any:     "abcd",
+		Static:  dynamicSquare{},
+		Present: &dynamicSquare{},
+	}
+	errs, ok := validator.Validate(v).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Any", "Shape.Side", "Shapes[1].Side"})
+	c.Assert(errs["Any"], HasError, validator.ErrMaxString(3, 4))
+	c.Assert(errs["Shape.Side"], HasError, validator.ErrMinFloat(1, 0))
+
+	errs, ok = validator.Validate(test{}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Present", "Shape"})
+
+	c.Assert(validator.Valid(1, "nodynamic=1"), Equals, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestNameFunc(c *C) {
+	type Address struct {
+		City string `json:"city,omitempty" validate:"nonzero"`
+	}
+	type test struct {
+		FirstName string  `json:"first_name" validate:"nonzero"`
+		Address   Address `json:"address"`
+		Other     []Address
+	}
+	t := test{Other: []Address{{}}}
+
+	err := validator.Validate(t)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["first_name"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Address.city"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Other[0].city"], HasError, validator.ErrZeroValueEmpty)
+
+	v := validator.NewValidator(validator.WithNameFunc(validator.TagNameFunc("json")))
+	err = v.Validate(t)
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["first_name"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["address.city"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["Other[0].city"], HasError, validator.ErrZeroValueEmpty)
+
+	v.SetNameFunc(func(f reflect.StructField) string {
+		return "x" + f.Name
+	})
+	err = v.Validate(t)
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["xAddress.xCity"], HasError, validator.ErrZeroValueEmpty)
+}
+
 func (ms *MySuite) TestValidSlice(c *C) {
 	s := make([]int, 0, 10)
 	err := validator.Valid(s, "nonzero")
@@ -214,6 +372,98 @@ func (ms *MySuite) TestValidString(c *C) {
 	c.Assert(errs, Not(HasError), validator.ErrMax)
 }
 
+func (ms *MySuite) TestBetween(c *C) {
+	c.Assert(validator.Valid(50, "between=1:100"), IsNil)
+	c.Assert(validator.Valid(1.5, "between=1.5 2"), IsNil)
+	c.Assert(validator.Valid("hello", "between=1:5"), IsNil)
+	c.Assert(validator.Valid([]int{1, 2}, "between=1:5"), IsNil)
+	c.Assert(validator.Valid(time.Minute, "between=1s:1m"), IsNil)
+	c.Assert(validator.Valid(*big.NewInt(7), "between=1:10"), IsNil)
+
+	c.Assert(validator.Valid(0, "between=1:100"), HasError, validator.ErrMinInt(1, 0))
+	c.Assert(validator.Valid(101, "between=1:100"), HasError, validator.ErrMaxInt(100, 101))
+	c.Assert(validator.Valid("hello!", "between=1:5"), HasError, validator.ErrMaxString(5, 6))
+	c.Assert(validator.Valid(map[string]int{}, "between=1:5"), HasError, validator.ErrMinArray(1, 0))
+
+	c.Assert(validator.Valid(50, "between=1"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(50, "between=1:50:100"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(50, "between=a:b"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(true, "between=0:1"), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestCommonTypes(c *C) {
+	// the types checked without reflection give the errors of the
+	// types of the same kind checked with it
+	type myString string
+	type myInt int
+	type myFloat float64
+	type myStrings []string
+	type myMap map[string]string
+	values := [][2]interface{}{
+		{"", myString("")},
+		{"héllo", myString("héllo")},
+		{0, myInt(0)},
+		{42, myInt(42)},
+		{int64(42), myInt(42)},
+		{0.0, myFloat(0)},
+		{2.5, myFloat(2.5)},
+		{[]string{}, myStrings{}},
+		{[]string{"a", "b"}, myStrings{"a", "b"}},
+		{map[string]string{"a": "b"}, myMap{"a": "b"}},
+	}
+	for _, tags := range []string{"nonzero", "len=2", "len=5", "min=3", "max=3", "between=1:10", "min=x"} {
+		for _, v := range values {
+			c.Assert(validator.Valid(v[0], tags), DeepEquals, validator.Valid(v[1], tags), Commentf("%#v %s", v[0], tags))
+		}
+	}
+
+	// as are pointers held by interface fields
+	s := "ab"
+	v := struct {
+		A interface{} `validate:"min=3"`
+	}{&s}
+	c.Assert(validator.Validate(v).(validator.ErrorMap)["A"], HasError, validator.ErrMinString(3, 2))
+	v.A = nil
+	c.Assert(validator.Validate(v), IsNil)
+}
+
+func (ms *MySuite) TestSplitParams(c *C) {
+	c.Assert(validator.SplitParams("1:100"), DeepEquals, []string{"1", "100"})
+	c.Assert(validator.SplitParams("2000-01-01T00:00:00Z  now"), DeepEquals, []string{"2000-01-01T00:00:00Z", "now"})
+	c.Assert(validator.SplitParams("a"), DeepEquals, []string{"a"})
+	c.Assert(validator.SplitParams(""), IsNil)
+	c.Assert(validator.SplitParams("a::b"), DeepEquals, []string{"a", "", "b"})
+	c.Assert(validator.SplitParams(`a\:b:c`), DeepEquals, []string{"a:b", "c"})
+	c.Assert(validator.SplitParams(`12:00 a\ b  c\\`), DeepEquals, []string{"12:00", "a b", "c\\"})
+	c.Assert(validator.SplitParams(`a\ b:c`), DeepEquals, []string{"a b", "c"})
+	c.Assert(validator.SplitParams(`a:b\`), DeepEquals, []string{"a", "b\\"})
+}
+
+func (ms *MySuite) TestValidationFuncParams(c *C) {
+	v := validator.New()
+	oneOf := func(val interface{}, params []string) error {
+		for _, p := range params {
+			if val == p {
+				return nil
+			}
+		}
+		return validator.ErrInvalid
+	}
+	c.Assert(v.SetValidationFuncParams("oneof", oneOf), IsNil)
+	type item struct {
+		Color string `validate:"oneof=red:green:light\\:blue"`
+	}
+	c.Assert(v.Validate(item{"green"}), IsNil)
+	c.Assert(v.Validate(item{"light:blue"}), IsNil)
+	errs, ok := v.Validate(item{"blue"}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Color"], HasError, validator.ErrInvalid)
+
+	c.Assert(v.SetValidationFuncParams("oneof", nil), IsNil)
+	c.Assert(v.Valid("red", "oneof=red"), Equals, validator.ErrUnknownTag)
+	c.Assert(v.SetValidationFuncParams("", oneOf), NotNil)
+}
+
 func (ms *MySuite) TestValidateStructVar(c *C) {
 	// just verifies that a the given val is a struct
 	validator.SetValidationFunc("struct", func(val interface{}, _ string) error {
@@ -317,6 +567,55 @@ func (ms *MySuite) TestValidatePointerVar(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (ms *MySuite) TestNilAsZero(c *C) {
+	type test struct {
+		Age   *int     `validate:"min=18"`
+		Name  **string `validate:"len=3"`
+		Score *float64
+	}
+	c.Assert(validator.Validate(test{}), IsNil)
+
+	v := validator.New(validator.WithNilAsZero())
+	errs, ok := v.Validate(test{}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Age"], HasError, validator.ErrMinInt(18, 0))
+	c.Assert(errs["Name"], HasError, validator.ErrLenString(3, 0))
+
+	age, name := 20, "Joe"
+	pname := &name
+	c.Assert(v.Validate(test{Age: &age, Name: &pname}), IsNil)
+
+	c.Assert(v.Valid((*int)(nil), "nonzero"), HasError, validator.ErrZeroValueNumber)
+	c.Assert(validator.Valid((*int)(nil), "nonzero"), HasError, validator.ErrZeroValueEmpty)
+}
+
+func (ms *MySuite) TestRequired(c *C) {
+	type test struct {
+		Count *int              `validate:"required,min=1"`
+		Tags  []string          `validate:"notnil"`
+		Extra interface{}       `validate:"required"`
+		Meta  map[string]string `validate:"required"`
+		Name  string            `validate:"required"`
+		Score sql.NullInt64     `validate:"required"`
+	}
+	errs, ok := validator.Validate(test{}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Count", "Extra", "Meta", "Score", "Tags"})
+	c.Assert(errs["Count"], DeepEquals, validator.ErrorArray{validator.ErrRequired})
+
+	// present values may be zero
+	zero := 0
+	v := test{&zero, []string{}, 0, map[string]string{}, "", sql.NullInt64{Valid: true}}
+	errs, ok = validator.Validate(v).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs.Paths(), DeepEquals, []string{"Count"})
+	c.Assert(errs["Count"], HasError, validator.ErrMinInt(1, 0))
+
+	c.Assert(validator.Valid(nil, "required"), HasError, validator.ErrRequired)
+	c.Assert(validator.Valid(false, "required"), IsNil)
+}
+
 func (ms *MySuite) TestValidateOmittedStructVar(c *C) {
 	type test2 struct {
 		B int `validate:"min=1"`
@@ -399,6 +698,348 @@ func (ms *MySuite) TestCopy(c *C) {
 	c.Assert(errs["A"], HasError, validator.ErrUnknownTag)
 }
 
+func (ms *MySuite) TestWithTagName(c *C) {
+	type test struct {
+		A int `binding:"nonzero" valid:"min=10"`
+	}
+	bv := validator.NewValidator(validator.WithTagName("binding"))
+	vv := validator.NewValidator(validator.WithTagName("valid"))
+
+	t := test{5}
+	err := bv.Validate(t)
+	c.Assert(err, IsNil)
+
+	err = vv.Validate(t)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrMinInt(10, 5))
+
+	err = validator.Validate(t)
+	c.Assert(err, IsNil)
+}
+
+func (ms *MySuite) TestCustomMessage(c *C) {
+	type test struct {
+		Password string `validate:"nonzero ~ password is required, min=8 ~ password must be at least {param} characters"`
+		Age      int    `validate:"min='18'~must be an adult but was {value}"`
+	}
+	err := validator.Validate(test{Password: "abc", Age: 12})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Password"], HasLen, 1)
+	c.Assert(errs["Password"][0].Error(), Equals, "password must be at least 8 characters")
+	c.Assert(errs["Age"][0].Error(), Equals, "must be an adult but was 12")
+
+	err = validator.Validate(test{Age: 18})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Password"], HasLen, 2)
+	c.Assert(errs["Password"][0].Error(), Equals, "password is required")
+
+	c.Assert(validator.Validate(test{Password: "abcdefgh", Age: 18}), IsNil)
+	c.Assert(validator.Check(test{}), IsNil)
+}
+
+func (ms *MySuite) TestTildeInParameter(c *C) {
+	// tildes in unquoted parameters only start a message when
+	// surrounded by spaces, unless escaped
+	type test struct {
+		A string `validate:"regexp=^a~b$"`
+		B string `validate:"regexp=^a \\~ b$"`
+		C string `validate:"max=1@warn ~ too long"`
+	}
+	c.Assert(validator.Validate(test{A: "a~b", B: "a ~ b"}), IsNil)
+	err := validator.Validate(test{A: "ax", B: "a"})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrRegexpDetailed("^a~b$"))
+	c.Assert(errs["B"], HasError, validator.ErrRegexpDetailed("^a ~ b$"))
+
+	warnings, err := validator.ValidateWithWarnings(test{A: "a~b", B: "a ~ b", C: "ab"})
+	c.Assert(err, IsNil)
+	c.Assert(warnings["C"][0].Error(), Equals, "too long")
+}
+
+func (ms *MySuite) TestEscapedParameters(c *C) {
+	type test struct {
+		Code  string `validate:"regexp=^[a-z]{2\\,3}$ ~ must be 2\\, 3 letters"`
+		Name  string `validate:"regexp='^[a-z]+(, [a-z]+)*$', min=3"`
+		Token string `validate:"excludesall=' '"`
+	}
+	err := validator.Validate(test{Code: "ab", Name: "joe, bob", Token: "ab"})
+	c.Assert(err, IsNil)
+
+	err = validator.Validate(test{Code: "abcd", Name: "joe,bob", Token: "a b"})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Code"], HasLen, 1)
+	c.Assert(errs["Code"][0].Error(), Equals, "must be 2, 3 letters")
+	c.Assert(errs["Name"], HasError, validator.ErrRegexpDetailed("^[a-z]+(, [a-z]+)*$"))
+	c.Assert(errs["Token"], HasError, validator.ErrExcludesAll(" "))
+
+	type unterminated struct {
+		A string `validate:"regexp='^a"`
+	}
+	err = validator.Validate(unterminated{A: "a"})
+	c.Assert(err, NotNil)
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrBadParameter)
+
+	type trailing struct {
+		A string `validate:"regexp='^a'b"`
+	}
+	err = validator.Validate(trailing{A: "a"})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestErrorMapPaths(c *C) {
+	errs := validator.ErrorMap{
+		"Users[10].Name": {validator.ErrZeroValueEmpty},
+		"Name":           {validator.ErrMinString(3, 1)},
+		"Users[2].Name":  {validator.ErrZeroValueEmpty},
+		"Address.City":   {validator.ErrZeroValueEmpty},
+		"Users[2]":       {validator.ErrInvalid},
+	}
+	c.Assert(errs.Paths(), DeepEquals, []string{"Address.City", "Name", "Users[2]", "Users[2].Name", "Users[10].Name"})
+	c.Assert(errs.Error(), Equals, "Address.City: Must not be empty")
+	c.Assert(validator.ErrorMap{}.Paths(), HasLen, 0)
+}
+
+func (ms *MySuite) TestErrorOrder(c *C) {
+	type item struct {
+		Name string `validate:"nonzero"`
+	}
+	type test struct {
+		B     string `validate:"nonzero,min=3"`
+		A     string `validate:"nonzero"`
+		Items map[int]item
+	}
+	t := test{Items: map[int]item{10: {}, 2: {}, 1: {}, 30: {}}}
+	for i := 0; i < 10; i++ {
+		err := validator.Validate(t, validator.WithMaxErrors(4))
+		errs, ok := err.(validator.ErrorMap)
+		c.Assert(ok, Equals, true)
+		c.Assert(errs.Paths(), DeepEquals, []string{"A", "B", "Items[1].Name"})
+		c.Assert(errs["B"], HasLen, 2)
+		c.Assert(errs["B"][0], Equals, validator.ErrZeroValueEmpty)
+	}
+}
+
+func (ms *MySuite) TestGroups(c *C) {
+	type user struct {
+		ID       int    `validate:"nonzero,groups=update"`
+		Name     string `validate:"nonzero"`
+		Password string `validate:"groups=create|reset,min=8"`
+	}
+	u := user{Name: "joe", Password: "abc"}
+
+	err := validator.Validate(u, validator.WithGroups("create"))
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Password"], HasError, validator.ErrMinString(8, 3))
+
+	err = validator.Validate(u, validator.WithGroups("update"))
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["ID"], HasError, validator.ErrZeroValueNumber)
+
+	err = validator.Validate(user{Password: "abc"}, validator.WithGroups("other"))
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Name"], HasError, validator.ErrZeroValueEmpty)
+
+	// without groups, all rules apply
+	err = validator.Validate(u)
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+
+	v := validator.NewValidator(validator.WithGroups("reset"))
+	err = v.Validate(u)
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Password"], HasError, validator.ErrMinString(8, 3))
+}
+
+func (ms *MySuite) TestFailFast(c *C) {
+	type item struct {
+		A string `validate:"nonzero"`
+	}
+	type test struct {
+		A int    `validate:"nonzero"`
+		B string `validate:"len=8,min=6,max=4"`
+		C []item
+	}
+	t := test{B: "12345", C: make([]item, 10)}
+
+	err := validator.Validate(t, validator.WithFailFast())
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["A"], HasLen, 1)
+
+	err = validator.Valid("12345", "len=8,min=6,max=4")
+	c.Assert(err, HasLen, 3)
+	v := validator.NewValidator(validator.WithFailFast())
+	err = v.Valid("12345", "len=8,min=6,max=4")
+	c.Assert(err, HasLen, 1)
+
+	err = validator.Validate(t, validator.WithMaxErrors(2))
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["A"], HasLen, 1)
+	c.Assert(errs["B"], HasLen, 1)
+
+	err = validator.Validate(t, validator.WithMaxErrors(5))
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["B"], HasLen, 3)
+	c.Assert(errs["C[0].A"], HasLen, 1)
+}
+
+func (ms *MySuite) TestNew(c *C) {
+	notZZ := func(v interface{}, _ string) error {
+		if v == "ZZ" {
+			return validator.ErrInvalid
+		}
+		return nil
+	}
+	type test struct {
+		A string `valid:"notzz"`
+	}
+	v := validator.New(validator.WithTagName("valid"), validator.WithValidationFunc("notzz", notZZ))
+	err := v.Validate(test{"ZZ"})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrInvalid)
+
+	// other validators do not see the function
+	err = validator.Valid("ZZ", "notzz")
+	c.Assert(err, Equals, validator.ErrUnknownTag)
+
+	// nor does the validator when given for a single call
+	v = validator.New()
+	err = v.Valid("ZZ", "min=1")
+	c.Assert(err, IsNil)
+	err = v.Validate(test{"ZZ"}, validator.WithTagName("valid"), validator.WithValidationFunc("notzz", notZZ))
+	c.Assert(err, NotNil)
+	err = v.Validate(test{"ZZ"}, validator.WithTagName("valid"))
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrUnknownTag)
+}
+
+func (ms *MySuite) TestConcurrentSetValidationFunc(c *C) {
+	type test struct {
+		A string `validate:"nonzero,custom"`
+	}
+	v := validator.New()
+	v.SetValidationFunc("custom", func(interface{}, string) error { return nil })
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			v.SetValidationFunc("custom", func(interface{}, string) error { return nil })
+		}
+		done <- true
+	}()
+	for i := 0; i < 100; i++ {
+		c.Assert(v.Validate(test{"a"}), IsNil)
+	}
+	<-done
+}
+
+func (ms *MySuite) TestRegexpCache(c *C) {
+	for i := 0; i < 3; i++ {
+		err := validator.Valid("abc", "regexp=^[a-c]+$")
+		c.Assert(err, IsNil)
+		err = validator.Valid("abc", "regexp=^[")
+		c.Assert(err, HasError, validator.ErrBadParameter)
+	}
+
+	validator.SetRegexpCacheSize(1)
+	defer validator.SetRegexpCacheSize(0)
+	for _, p := range []string{"^a", "^b", "^a"} {
+		err := validator.Valid("abc", "regexp="+p)
+		c.Assert(err == nil, Equals, p == "^a")
+	}
+}
+
+func (ms *MySuite) TestLargeUint(c *C) {
+	const big = uint64(1<<64 - 1)
+	err := validator.Valid(big, "max=10000000000000000000")
+	c.Assert(err, HasError, validator.ErrMaxUint(10000000000000000000, big))
+	c.Assert(err.Error(), Equals, "Must not be greater than 10000000000000000000, was 18446744073709551615")
+	c.Assert(validator.Valid(uint64(1), "min=10000000000000000000"), HasError, validator.ErrMinUint(10000000000000000000, 1))
+	c.Assert(validator.Valid(big, "len=1"), HasError, validator.ErrLenUint(1, big))
+}
+
+func (ms *MySuite) TestRegexpNamedString(c *C) {
+	type email string
+	c.Assert(validator.Valid(email("joe@example.com"), "regexp=@"), IsNil)
+	c.Assert(validator.Valid(email("joe"), "regexp=@"), HasError, validator.ErrRegexpDetailed("@"))
+}
+
+func BenchmarkValidateRegexp(b *testing.B) {
+	type test struct {
+		Email string `validate:"regexp=^[0-9a-z]+@[0-9a-z]+(\\.[0-9a-z]+)+$"`
+	}
+	t := test{"joe@example.com"}
+	for i := 0; i < b.N; i++ {
+		validator.Validate(t)
+	}
+}
+
+func (ms *MySuite) TestStructCache(c *C) {
+	type test struct {
+		A string `validate:"cached"`
+	}
+	v := validator.New()
+	v.SetValidationFunc("cached", func(interface{}, string) error { return validator.ErrInvalid })
+	err := v.Validate(test{})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrInvalid)
+
+	// changing the function is seen by the next validation
+	v.SetValidationFunc("cached", func(interface{}, string) error { return nil })
+	err = v.Validate(test{})
+	c.Assert(err, IsNil)
+
+	v.SetValidationFunc("cached", nil)
+	err = v.Validate(test{})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrUnknownTag)
+}
+
+func BenchmarkValidate(b *testing.B) {
+	type address struct {
+		Street string `validate:"nonzero"`
+		City   string `validate:"nonzero,max=40"`
+	}
+	type user struct {
+		Username string `validate:"min=3,max=40"`
+		Name     string `validate:"nonzero"`
+		Age      int    `validate:"min=18"`
+		Password string `validate:"min=8"`
+		Address  address
+	}
+	u := user{"joe", "Joe Doe", 21, "password", address{"1 Main St", "Wellington"}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.Validate(u)
+	}
+}
+
 type hasErrorChecker struct {
 	*CheckerInfo
 }
@@ -431,3 +1072,25 @@ func (c *hasErrorChecker) Info() *CheckerInfo {
 }
 
 var HasError = &hasErrorChecker{&CheckerInfo{Name: "HasError", Params: []string{"HasError", "expected to contain"}}}
+
+func BenchmarkValidMinMaxString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.Valid("Wellington", "nonzero,min=3,max=40")
+	}
+}
+
+func BenchmarkValidMinMaxInt(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.Valid(42, "nonzero,min=18,max=120")
+	}
+}
+
+func BenchmarkValidLenSlice(b *testing.B) {
+	tags := []string{"a", "b", "c"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validator.Valid(tags, "nonzero,len=3")
+	}
+}
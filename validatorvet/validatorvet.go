@@ -0,0 +1,174 @@
+// Package validatorvet checks validation tags statically
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validatorvet provides an analyzer checking the validation tags
+// of struct fields in source code, so that unknown rules, invalid
+// parameters and rules which do not apply to the type of their field are
+// reported when vetting rather than when values are validated.
+//
+// It is run by go vet with the validatorvet command:
+//
+//	go install github.com/movio/validator/cmd/validatorvet
+//	go vet -vettool=$(which validatorvet) ./...
+//
+// Custom rules and aliases, only registered at run time, are given with
+// the -rules flag, e.g. -validatortags.rules=isbn,username.
+package validatorvet
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/movio/validator"
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer checks the validation tags of struct fields.
+var Analyzer = &analysis.Analyzer{
+	Name: "validatortags",
+	Doc:  "check validation tags of struct fields: unknown rules, invalid parameters and rules not applying to the type of the field",
+	Run:  run,
+}
+
+var (
+	tagName string
+	rules   string
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&tagName, "tag", "validate", "name of the struct tag holding the validation rules")
+	Analyzer.Flags.StringVar(&rules, "rules", "", "comma separated names of the custom rules and aliases to accept")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	v := validator.New()
+	for _, name := range strings.Split(rules, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			v.SetValidationFunc(name, func(interface{}, string) error { return nil })
+		}
+	}
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			st, ok := n.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				if field.Tag == nil {
+					continue
+				}
+				tag, err := strconv.Unquote(field.Tag.Value)
+				if err != nil {
+					continue
+				}
+				tags := reflect.StructTag(tag).Get(tagName)
+				if tags == "" || tags == "-" {
+					continue
+				}
+				checkField(pass, v, field, tags)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// checkField reports the invalid rules of tags, the validation tag of
+// field.
+func checkField(pass *analysis.Pass, v *validator.Validator, field *ast.Field, tags string) {
+	rules, err := validator.ParseTag(tags)
+	if err != nil {
+		pass.Reportf(field.Tag.Pos(), "invalid validation tag %q: %v", tags, err)
+		return
+	}
+	typ := pass.TypesInfo.TypeOf(field.Type)
+	sample, ok := sampleValue(typ)
+	for _, r := range rules {
+		if r.Name == "groups" {
+			continue
+		}
+		if err := v.Check(validator.MapRules{"": r.String()}); err != nil {
+			if errs, _ := err.(validator.ErrorMap); hasError(errs[""], validator.ErrUnknownTag) {
+				pass.Reportf(field.Tag.Pos(), "unknown validation rule %q", r.Name)
+			} else {
+				pass.Reportf(field.Tag.Pos(), "invalid parameter %q of validation rule %s", r.Param, r.Name)
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if errs, _ := v.Valid(sample, r.String()).(validator.ErrorArray); hasError(errs, validator.ErrUnsupported) {
+			pass.Reportf(field.Tag.Pos(), "validation rule %s does not apply to %s fields", r.Name, types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+		}
+	}
+}
+
+// hasError reports whether errs holds err.
+func hasError(errs []error, err error) bool {
+	for _, e := range errs {
+		if e == err {
+			return true
+		}
+	}
+	return false
+}
+
+// sampleValue returns a value of the kind of the values of type t the
+// rules are applied to, to find those which do not apply to it, and
+// false if there is none, e.g. for interfaces.
+func sampleValue(t types.Type) (interface{}, bool) {
+	if t == nil {
+		return nil, false
+	}
+	for {
+		p, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			break
+		}
+		t = p.Elem()
+	}
+	if n, ok := t.(*types.Named); ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "time" && n.Obj().Name() == "Time" {
+		return time.Time{}, true
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		info := u.Info()
+		switch {
+		case info&types.IsString != 0:
+			return "", true
+		case info&types.IsBoolean != 0:
+			return false, true
+		case info&types.IsUnsigned != 0:
+			return uint64(0), true
+		case info&types.IsInteger != 0:
+			return int64(0), true
+		case info&types.IsFloat != 0:
+			return float64(0), true
+		}
+	case *types.Slice, *types.Array:
+		return []interface{}{}, true
+	case *types.Map:
+		return map[string]interface{}{}, true
+	case *types.Struct:
+		return struct{}{}, true
+	}
+	return nil, false
+}
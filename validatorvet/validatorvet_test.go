@@ -0,0 +1,95 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatorvet_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/movio/validator/validatorvet"
+	"golang.org/x/tools/go/analysis"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type AnalyzerSuite struct{}
+
+var _ = Suite(&AnalyzerSuite{})
+
+const src = `package user
+
+import "time"
+
+type Code string
+
+type User struct {
+	Name     string            ` + "`" + `validate:"nonzero,max=40"` + "`" + `
+	Email    string            ` + "`" + `validate:"nonzero,emial"` + "`" + `
+	Age      int               ` + "`" + `validate:"min=abc,max=130"` + "`" + `
+	Zip      *Code             ` + "`" + `validate:"regexp=^[0-9]{5\\,}$,regexp=(,len=5"` + "`" + `
+	Port     int               ` + "`" + `validate:"hostname"` + "`" + `
+	Born     time.Time         ` + "`" + `validate:"before=now,min=3"` + "`" + `
+	Tags     []string          ` + "`" + `validate:"max=5,unique,groups=update"` + "`" + `
+	Labels   map[string]string ` + "`" + `validate:"regexp='a"` + "`" + `
+	ISBN     string            ` + "`" + `validate:"isbn"` + "`" + `
+	Any      interface{}       ` + "`" + `validate:"hostname"` + "`" + `
+	Skipped  int               ` + "`" + `validate:"-"` + "`" + `
+	Untagged int
+}
+`
+
+func (s *AnalyzerSuite) TestAnalyzer(c *C) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "user.go", src, 0)
+	c.Assert(err, IsNil)
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("user", fset, []*ast.File{f}, info)
+	c.Assert(err, IsNil)
+
+	var diags []string
+	pass := &analysis.Pass{
+		Analyzer:  validatorvet.Analyzer,
+		Fset:      fset,
+		Files:     []*ast.File{f},
+		Pkg:       pkg,
+		TypesInfo: info,
+		Report: func(d analysis.Diagnostic) {
+			diags = append(diags, fmt.Sprintf("%d: %s", fset.Position(d.Pos).Line, d.Message))
+		},
+	}
+	c.Assert(validatorvet.Analyzer.Flags.Set("rules", "isbn"), IsNil)
+	_, err = validatorvet.Analyzer.Run(pass)
+	c.Assert(err, IsNil)
+	c.Assert(diags, DeepEquals, []string{
+		`9: unknown validation rule "emial"`,
+		`10: invalid parameter "abc" of validation rule min`,
+		`11: invalid parameter "(" of validation rule regexp`,
+		`12: validation rule hostname does not apply to int fields`,
+		`13: validation rule min does not apply to time.Time fields`,
+		`15: invalid validation tag "regexp='a": bad parameter`,
+	})
+}
@@ -0,0 +1,109 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ValueBuilder holds the rules of a standalone value declared in code,
+// for values which are not struct fields.
+//
+//	err := validator.Value(email).Rules("nonzero", "max=254").Error()
+type ValueBuilder struct {
+	mv    *Validator
+	v     interface{}
+	rules []string
+}
+
+// Value returns a builder of the rules of v, checked by the default
+// validator.
+func Value(v interface{}) *ValueBuilder {
+	return defaultValidator.Value(v)
+}
+
+// Value returns a builder of the rules of v, checked by mv.
+func (mv *Validator) Value(v interface{}) *ValueBuilder {
+	return &ValueBuilder{mv: mv, v: v}
+}
+
+// Rules adds rules, written as in a struct tag, to those of the value.
+func (b *ValueBuilder) Rules(rules ...string) *ValueBuilder {
+	nb := *b
+	nb.rules = append(append([]string(nil), b.rules...), rules...)
+	return &nb
+}
+
+// NonZero adds the nonzero rule to those of the value.
+func (b *ValueBuilder) NonZero() *ValueBuilder {
+	return b.Rules("nonzero")
+}
+
+// Len adds the len rule with parameter n to those of the value.
+func (b *ValueBuilder) Len(n interface{}) *ValueBuilder {
+	return b.Rules(fmt.Sprintf("len=%v", n))
+}
+
+// Min adds the min rule with parameter n to those of the value.
+func (b *ValueBuilder) Min(n interface{}) *ValueBuilder {
+	return b.Rules(fmt.Sprintf("min=%v", n))
+}
+
+// Max adds the max rule with parameter n to those of the value.
+func (b *ValueBuilder) Max(n interface{}) *ValueBuilder {
+	return b.Rules(fmt.Sprintf("max=%v", n))
+}
+
+// Regexp adds the regexp rule with the given pattern to those of the
+// value. The pattern is escaped as needed.
+func (b *ValueBuilder) Regexp(pattern string) *ValueBuilder {
+	return b.Rules("regexp=" + escapeParam(pattern))
+}
+
+// Error validates the value against its rules and returns the errors
+// found, as Valid does, or nil.
+func (b *ValueBuilder) Error() error {
+	return b.ErrorContext(context.Background())
+}
+
+// ErrorContext is like Error, passing ctx to the validation functions
+// needing it.
+func (b *ValueBuilder) ErrorContext(ctx context.Context) error {
+	if len(b.rules) == 0 {
+		return nil
+	}
+	return b.mv.valid(ctx, b.v, strings.Join(b.rules, ","))
+}
+
+// paramEscaper escapes the characters separating the items of tags.
+var paramEscaper = strings.NewReplacer(",", `\,`, "~", `\~`)
+
+// escapeParam returns param escaped to be used in a tag.
+func escapeParam(param string) string {
+	return paramEscaper.Replace(param)
+}
+
+// quoteParam returns param quoted or escaped to be used in a tag, as is
+// when possible.
+func quoteParam(param string) string {
+	if param != strings.Trim(param, " ") && !strings.Contains(param, "'") {
+		return "'" + param + "'"
+	}
+	return escapeParam(param)
+}
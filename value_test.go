@@ -0,0 +1,56 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ms *MySuite) TestValue(c *C) {
+	c.Assert(validator.Value("joe").Rules("nonzero", "max=5").Error(), IsNil)
+	c.Assert(validator.Value("joe").Error(), IsNil)
+
+	err := validator.Value("").Rules("nonzero").Rules("min=2").Error()
+	c.Assert(err, HasError, validator.ErrZeroValueEmpty)
+	c.Assert(err, HasError, validator.ErrMinString(2, 0))
+
+	c.Assert(validator.Value(3).Min(5).Error(), HasError, validator.ErrMinInt(5, 3))
+	c.Assert(validator.Value([]int{1, 2}).Len(3).Error(), HasError, validator.ErrLenArray(3, 2))
+	c.Assert(validator.Value(4.5).Max(4).Error(), HasError, validator.ErrMaxFloat(4, 4.5))
+	c.Assert(validator.Value((*string)(nil)).NonZero().Error(), HasError, validator.ErrZeroValueEmpty)
+	c.Assert(validator.Value("x").Rules("foo").Error(), Equals, validator.ErrUnknownTag)
+}
+
+func (ms *MySuite) TestValueRegexp(c *C) {
+	c.Assert(validator.Value("ab,c").Regexp("^[a-z]{1,2},c$").Error(), IsNil)
+	c.Assert(validator.Value("a~b").Regexp("^a~b$").Error(), IsNil)
+	c.Assert(validator.Value("abc").Regexp("^[a-z]{1,2}$").Error(), HasError, validator.ErrRegexpDetailed("^[a-z]{1,2}$"))
+}
+
+func (ms *MySuite) TestValueBuilderIsImmutable(c *C) {
+	name := validator.Value("joe").NonZero()
+	c.Assert(name.Max(2).Error(), NotNil)
+	c.Assert(name.Error(), IsNil)
+
+	v := validator.New()
+	c.Assert(v.SetValidationFunc("fails", func(interface{}, string) error { return validator.ErrInvalid }), IsNil)
+	c.Assert(v.Value(1).Rules("fails").ErrorContext(context.Background()), HasError, validator.ErrInvalid)
+}
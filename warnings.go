@@ -0,0 +1,98 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+)
+
+// warning wraps the error of a rule marked as a warning with @warn.
+type warning struct {
+	error
+}
+
+// ValidateWithWarnings validates the fields of a struct like Validate,
+// and also returns the errors of the rules marked as warnings with
+// @warn, e.g. validate:"max=255@warn", indexed the same way. Warnings
+// are otherwise ignored, so that values which are tolerated can be
+// reported without failing validation.
+func ValidateWithWarnings(v interface{}, opts ...Option) (warnings ErrorMap, err error) {
+	return defaultValidator.ValidateWithWarnings(v, opts...)
+}
+
+// ValidateWithWarnings validates the fields of a struct like Validate,
+// and also returns the errors of the rules marked as warnings with
+// @warn, e.g. validate:"max=255@warn", indexed the same way. Warnings
+// are otherwise ignored, so that values which are tolerated can be
+// reported without failing validation.
+func (mv *Validator) ValidateWithWarnings(v interface{}, opts ...Option) (warnings ErrorMap, err error) {
+	nv := *mv.with(opts...)
+	nv.warnings = make(ErrorMap)
+	err = nv.validate(context.Background(), v)
+	if len(nv.warnings) > 0 {
+		warnings = nv.warnings
+	}
+	return warnings, err
+}
+
+// addWarnings adds the warnings among errs to the warnings collected
+// under key, if any, and returns the other errors.
+func (mv *Validator) addWarnings(key string, errs []error) []error {
+	errs, warnings := splitWarnings(errs)
+	if len(warnings) > 0 && mv.warnings != nil {
+		mv.warnings[key] = append(mv.warnings[key], warnings...)
+	}
+	return errs
+}
+
+// withoutWarnings returns err without the warnings it holds, nil if it
+// only holds warnings.
+func withoutWarnings(err error) error {
+	errs, ok := err.(ErrorArray)
+	if !ok {
+		return err
+	}
+	others, warnings := splitWarnings(errs)
+	switch {
+	case len(warnings) == 0:
+		return err
+	case len(others) == 0:
+		return nil
+	}
+	return ErrorArray(others)
+}
+
+// splitWarnings splits errs into the errors which are not warnings and
+// the errors the warnings wrap.
+func splitWarnings(errs []error) (others, warnings []error) {
+	for i, err := range errs {
+		if _, ok := err.(warning); !ok {
+			continue
+		}
+		// only allocate when there are warnings
+		others = append([]error(nil), errs[:i]...)
+		for _, err := range errs[i:] {
+			if w, ok := err.(warning); ok {
+				warnings = append(warnings, w.error)
+			} else {
+				others = append(others, err)
+			}
+		}
+		return others, warnings
+	}
+	return errs, nil
+}
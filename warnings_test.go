@@ -0,0 +1,64 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type warned struct {
+	Name  string `validate:"nonzero,max=5@warn"`
+	Code  string `validate:"regexp='^[a-z]+$'@warn ~ code should be lower case"`
+	Bio   string `validate:"max=10 @warn"`
+	Email string `json:"email" validate:"min=3@warn,max=20"`
+}
+
+func (ms *MySuite) TestValidateWithWarnings(c *C) {
+	w := warned{Name: "Joseph", Code: "ABC", Bio: "a very long bio", Email: "a@b"}
+	warnings, err := validator.ValidateWithWarnings(w)
+	c.Assert(err, IsNil)
+	c.Assert(warnings["Name"], HasError, validator.ErrMaxString(5, 6))
+	c.Assert(warnings["Code"], HasLen, 1)
+	c.Assert(warnings["Code"][0].Error(), Equals, "code should be lower case")
+	c.Assert(warnings["Bio"], HasError, validator.ErrMaxString(10, 15))
+	c.Assert(warnings["email"], IsNil)
+	c.Assert(validator.Validate(w), IsNil)
+
+	w = warned{Code: "abc", Email: "a"}
+	warnings, err = validator.ValidateWithWarnings(w)
+	c.Assert(warnings["email"], HasError, validator.ErrMinString(3, 1))
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Name"], HasError, validator.ErrZeroValueEmpty)
+
+	warnings, err = validator.ValidateWithWarnings(warned{Name: "Joe", Code: "abc", Email: "joe@example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(warnings, IsNil)
+}
+
+func (ms *MySuite) TestValidWarnings(c *C) {
+	c.Assert(validator.Valid("Joseph", "max=5@warn"), IsNil)
+	c.Assert(validator.Valid("", "nonzero,max=5@warn"), HasError, validator.ErrZeroValueEmpty)
+
+	fields, err := validator.Describe(warned{})
+	c.Assert(err, IsNil)
+	c.Assert(fields[0].Rules, DeepEquals, []validator.Rule{{Name: "nonzero"}, {Name: "max", Param: "5", Warn: true}})
+	c.Assert(fields[1].Rules, DeepEquals, []validator.Rule{{Name: "regexp", Param: "^[a-z]+$", Warn: true}})
+}
@@ -0,0 +1,66 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"strings"
+)
+
+// UnmarshalFunc decodes the document data into v, as yaml.Unmarshal does.
+type UnmarshalFunc func(data []byte, v interface{}) error
+
+// UnmarshalYAML decodes the YAML document data into v with unmarshal,
+// e.g. yaml.Unmarshal of gopkg.in/yaml.v3, and validates it. The errors
+// of decoding are returned as they are, and the errors of validation as
+// an ErrorMap whose paths are made of the YAML keys of the fields, e.g.
+// database.max_connections, unless the validator has a NameFunc of its
+// own.
+func UnmarshalYAML(unmarshal UnmarshalFunc, data []byte, v interface{}) error {
+	return defaultValidator.UnmarshalYAML(unmarshal, data, v)
+}
+
+// UnmarshalYAML decodes the YAML document data into v with unmarshal,
+// e.g. yaml.Unmarshal of gopkg.in/yaml.v3, and validates it. The errors
+// of decoding are returned as they are, and the errors of validation as
+// an ErrorMap whose paths are made of the YAML keys of the fields, e.g.
+// database.max_connections, unless the validator has a NameFunc of its
+// own.
+func (mv *Validator) UnmarshalYAML(unmarshal UnmarshalFunc, data []byte, v interface{}) error {
+	if err := unmarshal(data, v); err != nil {
+		return err
+	}
+	if mv.nameFunc == nil {
+		mv = mv.with(WithNameFunc(YAMLName))
+	}
+	return mv.validateDecoded(v)
+}
+
+// YAMLName is a NameFunc naming fields as the YAML decoders of
+// gopkg.in/yaml.v2 and v3 do: after their yaml tag or, without one,
+// after their name in lower case, e.g. MaxConnections becomes
+// maxconnections.
+func YAMLName(f reflect.StructField) string {
+	name := strings.SplitN(f.Tag.Get("yaml"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return strings.ToLower(f.Name)
+	}
+	return name
+}
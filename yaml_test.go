@@ -0,0 +1,84 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/movio/validator"
+
+	. "gopkg.in/check.v1"
+)
+
+type yamlDatabase struct {
+	MaxConnections int `yaml:"max_connections" validate:"min=1"`
+}
+
+type yamlConfig struct {
+	Name     string         `validate:"nonzero"`
+	Database yamlDatabase   `yaml:"database"`
+	Replicas []yamlDatabase `yaml:"replicas,omitempty"`
+	Ignored  string         `yaml:"-" validate:"nonzero"`
+}
+
+// fakeYAML stands for yaml.Unmarshal, setting the fields of v as the
+// document data would.
+func fakeYAML(cfg yamlConfig) validator.UnmarshalFunc {
+	return func(data []byte, v interface{}) error {
+		*v.(*yamlConfig) = cfg
+		return nil
+	}
+}
+
+func (ms *MySuite) TestUnmarshalYAML(c *C) {
+	valid := yamlConfig{Name: "app", Database: yamlDatabase{MaxConnections: 10}, Ignored: "x"}
+	var cfg yamlConfig
+	c.Assert(validator.UnmarshalYAML(fakeYAML(valid), nil, &cfg), IsNil)
+	c.Assert(cfg, DeepEquals, valid)
+
+	invalid := yamlConfig{Replicas: []yamlDatabase{{MaxConnections: 1}, {}}}
+	err := validator.UnmarshalYAML(fakeYAML(invalid), nil, &cfg)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 4)
+	c.Assert(errs["name"], HasError, validator.ErrZeroValueEmpty)
+	c.Assert(errs["database.max_connections"], HasError, validator.ErrMinInt(1, 0))
+	c.Assert(errs["replicas[1].max_connections"], HasError, validator.ErrMinInt(1, 0))
+	c.Assert(errs["Ignored"], HasError, validator.ErrZeroValueEmpty)
+
+	// JSON documents are YAML documents too
+	err = validator.UnmarshalYAML(json.Unmarshal, []byte(`{"Name":"app","Database":{"MaxConnections":0}}`), &cfg)
+	c.Assert(err, NotNil)
+
+	decodeErr := errors.New("yaml: line 1: did not find expected key")
+	unmarshal := func([]byte, interface{}) error { return decodeErr }
+	c.Assert(validator.UnmarshalYAML(unmarshal, nil, &cfg), Equals, decodeErr)
+
+	v := validator.New(validator.WithNameFunc(validator.TagNameFunc("yaml")))
+	errs, ok = v.UnmarshalYAML(fakeYAML(invalid), nil, &cfg).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Name"], HasError, validator.ErrZeroValueEmpty)
+}
+
+func (ms *MySuite) TestYAMLName(c *C) {
+	t := reflect.TypeOf(yamlConfig{})
+	for i, want := range []string{"name", "database", "replicas", ""} {
+		c.Assert(validator.YAMLName(t.Field(i)), Equals, want)
+	}
+}